@@ -31,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
 	"gopkg.in/urfave/cli.v1"
 )
 
@@ -150,6 +151,40 @@ It's deprecated, please use "geth db import" instead.
 The export-preimages command exports hash preimages to an RLP encoded stream.
 It's deprecated, please use "geth db export" instead.
 `,
+	}
+	importHistoryCommand = cli.Command{
+		Action:    utils.MigrateFlags(importHistory),
+		Name:      "import-history",
+		Usage:     "Restore expired block bodies and receipts from history archives",
+		ArgsUsage: "<dir>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.CacheFlag,
+			utils.SyncModeFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The import-history command verifies the history archives of the network in the
+given directory and restores the bodies and receipts of the blocks which are
+part of the local chain but whose history expired already.`,
+	}
+	exportHistoryCommand = cli.Command{
+		Action:    utils.MigrateFlags(exportHistory),
+		Name:      "export-history",
+		Usage:     "Export block bodies and receipts into history archives",
+		ArgsUsage: "<dir> <blockNumFirst> <blockNumLast>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.CacheFlag,
+			utils.SyncModeFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The export-history command exports the blocks in the given range together with
+their receipts and total difficulties into the given directory. Every archive
+covers an epoch of 8192 blocks and commits to its blocks with an accumulator
+root, which is part of the file name, so the archives can be verified on their
+own when imported.`,
 	}
 	dumpCommand = cli.Command{
 		Action:    utils.MigrateFlags(dump),
@@ -378,6 +413,65 @@ func exportPreimages(ctx *cli.Context) error {
 	return nil
 }
 
+// historyNetwork returns the network name used to tag the history archives.
+func historyNetwork(db ethdb.Database) string {
+	switch rawdb.ReadCanonicalHash(db, 0) {
+	case params.MainnetGenesisHash:
+		return "mainnet"
+	case params.TestnetGenesisHash:
+		return "testnet"
+	default:
+		return "private"
+	}
+}
+
+// importHistory restores the expired history from the archives in the given
+// directory.
+func importHistory(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	defer db.Close()
+	start := time.Now()
+
+	if err := utils.ImportHistory(db, ctx.Args().First(), historyNetwork(db)); err != nil {
+		utils.Fatalf("Import error: %v\n", err)
+	}
+	fmt.Printf("Import done in %v\n", time.Since(start))
+	return nil
+}
+
+// exportHistory exports the given range of blocks into history archives.
+func exportHistory(ctx *cli.Context) error {
+	if len(ctx.Args()) != 3 {
+		utils.Fatalf("This command requires three arguments.")
+	}
+	first, ferr := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+	last, lerr := strconv.ParseUint(ctx.Args().Get(2), 10, 64)
+	if ferr != nil || lerr != nil {
+		utils.Fatalf("Export error in parsing parameters: block number not an integer\n")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+
+	if head := rawdb.ReadHeadBlock(db); head == nil || last > head.NumberU64() {
+		utils.Fatalf("Export error: block number %d larger than head block\n", last)
+	}
+	start := time.Now()
+	if err := utils.ExportHistory(db, ctx.Args().First(), historyNetwork(db), first, last); err != nil {
+		utils.Fatalf("Export error: %v\n", err)
+	}
+	fmt.Printf("Export done in %v\n", time.Since(start))
+	return nil
+}
+
 func parseDumpConfig(ctx *cli.Context, stack *node.Node) (*state.DumpConfig, ethdb.Database, common.Hash, error) {
 	db := utils.MakeChainDatabase(ctx, stack, true)
 	defer db.Close()
@@ -0,0 +1,286 @@
+// Copyright 2025 Silver Bitcoin Foundation
+// This file is part of go-ethereum.
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// go-ethereum is distributed in the hope that it will be useful,
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/congress"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+var (
+	congressFlags = []cli.Flag{
+		utils.DataDirFlag,
+		utils.AncientFlag,
+		utils.SyncModeFlag,
+		utils.MainnetFlag,
+		utils.TestnetFlag,
+	}
+	congressCommand = cli.Command{
+		Name:        "congress",
+		Usage:       "A set of commands for the congress consensus snapshots",
+		Category:    "MISCELLANEOUS COMMANDS",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:      "inspect",
+				Usage:     "Print the snapshot stored for a block",
+				ArgsUsage: "<number | hash>",
+				Action:    utils.MigrateFlags(inspectCongressSnapshot),
+				Flags:     congressFlags,
+				Description: `
+geth congress inspect <number | hash>
+prints the validator snapshot stored in the database for the given block. The
+snapshots are stored for every checkpoint block. The default block is the head.
+`,
+			},
+			{
+				Name:      "recompute",
+				Usage:     "Recompute the snapshot of a block from the headers",
+				ArgsUsage: "<number | hash>",
+				Action:    utils.MigrateFlags(recomputeCongressSnapshot),
+				Flags:     congressFlags,
+				Description: `
+geth congress recompute <number | hash>
+recomputes the validator snapshot of the given block from the headers, ignoring
+the stored snapshots, and prints it along with any divergence from the snapshot
+stored for the block. The default block is the head.
+`,
+			},
+			{
+				Name:      "verify",
+				Usage:     "Verify the stored snapshots against the headers",
+				ArgsUsage: "<start (optional)> <end (optional)>",
+				Action:    utils.MigrateFlags(verifyCongressSnapshots),
+				Flags:     congressFlags,
+				Description: `
+geth congress verify <start> <end>
+recomputes the snapshots of the canonical checkpoint blocks in the given range
+and reports the stored snapshots which diverge from them or can't be decoded.
+The default range is the whole chain.
+`,
+			},
+			{
+				Name:      "repair",
+				Usage:     "Rewrite the stored snapshots which diverge from the headers",
+				ArgsUsage: "<start (optional)> <end (optional)>",
+				Action:    utils.MigrateFlags(repairCongressSnapshots),
+				Flags:     congressFlags,
+				Description: `
+geth congress repair <start> <end>
+works like 'geth congress verify', but replaces the diverging or corrupted
+snapshots with the recomputed ones.
+`,
+			},
+		},
+	}
+)
+
+// makeCongressChain opens the chain and returns it along with its congress
+// engine.
+func makeCongressChain(ctx *cli.Context) (*core.BlockChain, *congress.Congress, func(), error) {
+	stack, _ := makeConfigNode(ctx)
+	chain, db := utils.MakeChain(ctx, stack)
+	closer := func() {
+		chain.Stop()
+		db.Close()
+		stack.Close()
+	}
+	engine, ok := chain.Engine().(*congress.Congress)
+	if !ok {
+		closer()
+		return nil, nil, nil, errors.New("chain doesn't use the congress engine")
+	}
+	return chain, engine, closer, nil
+}
+
+// parseCongressBlock resolves the header of the block given as the argument,
+// defaulting to the head.
+func parseCongressBlock(ctx *cli.Context, chain *core.BlockChain) (*types.Header, error) {
+	if ctx.NArg() > 1 {
+		return nil, fmt.Errorf("expected 1 argument (number or hash), got %d", ctx.NArg())
+	}
+	if ctx.NArg() == 0 {
+		return chain.CurrentHeader(), nil
+	}
+	arg := ctx.Args().First()
+	if hashish(arg) {
+		if header := chain.GetHeaderByHash(common.HexToHash(arg)); header != nil {
+			return header, nil
+		}
+		return nil, fmt.Errorf("block %s not found", arg)
+	}
+	number, err := strconv.ParseUint(arg, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if header := chain.GetHeaderByNumber(number); header != nil {
+		return header, nil
+	}
+	return nil, fmt.Errorf("header for block %d not found", number)
+}
+
+func printCongressSnapshot(snap *congress.Snapshot) error {
+	out, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+func inspectCongressSnapshot(ctx *cli.Context) error {
+	chain, engine, closer, err := makeCongressChain(ctx)
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	header, err := parseCongressBlock(ctx, chain)
+	if err != nil {
+		return err
+	}
+	snap, err := engine.StoredSnapshot(header.Hash())
+	if err != nil {
+		return err
+	}
+	if snap == nil {
+		return fmt.Errorf("no snapshot stored for block #%d, they are stored every %d blocks", header.Number, congress.CheckpointInterval)
+	}
+	return printCongressSnapshot(snap)
+}
+
+func recomputeCongressSnapshot(ctx *cli.Context) error {
+	chain, engine, closer, err := makeCongressChain(ctx)
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	header, err := parseCongressBlock(ctx, chain)
+	if err != nil {
+		return err
+	}
+	snap, err := engine.RecomputeSnapshot(chain, header.Number.Uint64(), header.Hash())
+	if err != nil {
+		return err
+	}
+	if err := printCongressSnapshot(snap); err != nil {
+		return err
+	}
+	stored, err := engine.StoredSnapshot(header.Hash())
+	switch {
+	case err != nil:
+		fmt.Printf("Stored snapshot: %v\n", err)
+	case stored == nil:
+		fmt.Println("No snapshot stored for the block")
+	default:
+		diffs := stored.Diff(snap)
+		if len(diffs) == 0 {
+			fmt.Println("Stored snapshot matches")
+		}
+		for _, diff := range diffs {
+			fmt.Printf("Stored snapshot diverges: %s\n", diff)
+		}
+	}
+	return nil
+}
+
+func verifyCongressSnapshots(ctx *cli.Context) error {
+	return checkCongressSnapshots(ctx, false)
+}
+
+func repairCongressSnapshots(ctx *cli.Context) error {
+	return checkCongressSnapshots(ctx, true)
+}
+
+// checkCongressSnapshots compares the snapshots stored for the checkpoint
+// blocks in the given range with the recomputed ones, optionally rewriting
+// the bad ones.
+func checkCongressSnapshots(ctx *cli.Context, repair bool) error {
+	if ctx.NArg() > 2 {
+		return fmt.Errorf("max 2 arguments: %v", ctx.Command.ArgsUsage)
+	}
+	chain, engine, closer, err := makeCongressChain(ctx)
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	start, end := uint64(0), chain.CurrentHeader().Number.Uint64()
+	if ctx.NArg() > 0 {
+		if start, err = strconv.ParseUint(ctx.Args().Get(0), 10, 64); err != nil {
+			return fmt.Errorf("invalid start block: %v", err)
+		}
+	}
+	if ctx.NArg() > 1 {
+		if end, err = strconv.ParseUint(ctx.Args().Get(1), 10, 64); err != nil {
+			return fmt.Errorf("invalid end block: %v", err)
+		}
+	}
+	var (
+		checked, bad int
+		begin        = time.Now()
+		logged       = time.Now()
+	)
+	for number := (start + congress.CheckpointInterval - 1) / congress.CheckpointInterval * congress.CheckpointInterval; number <= end; number += congress.CheckpointInterval {
+		header := chain.GetHeaderByNumber(number)
+		if header == nil {
+			return fmt.Errorf("header for block %d not found", number)
+		}
+		stored, err := engine.StoredSnapshot(header.Hash())
+		if err == nil && stored == nil {
+			continue
+		}
+		snap, rerr := engine.RecomputeSnapshot(chain, number, header.Hash())
+		if rerr != nil {
+			return fmt.Errorf("failed to recompute snapshot #%d: %v", number, rerr)
+		}
+		checked++
+
+		var diffs []string
+		if err != nil {
+			diffs = []string{err.Error()}
+		} else {
+			diffs = stored.Diff(snap)
+		}
+		if len(diffs) > 0 {
+			bad++
+			for _, diff := range diffs {
+				log.Warn("Stored snapshot diverges", "number", number, "hash", header.Hash(), "diff", diff)
+			}
+			if repair {
+				if err := engine.WriteSnapshot(snap); err != nil {
+					return err
+				}
+				log.Info("Rewrote snapshot", "number", number, "hash", header.Hash())
+			}
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Checking snapshots", "number", number, "end", end, "checked", checked, "bad", bad, "elapsed", common.PrettyDuration(time.Since(begin)))
+			logged = time.Now()
+		}
+	}
+	log.Info("Checked snapshots", "checked", checked, "bad", bad, "elapsed", common.PrettyDuration(time.Since(begin)))
+	if bad > 0 && !repair {
+		return fmt.Errorf("%d of %d stored snapshots diverge, rewrite them with 'geth congress repair'", bad, checked)
+	}
+	return nil
+}
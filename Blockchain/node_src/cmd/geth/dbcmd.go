@@ -62,6 +62,8 @@ Remove blockchain and state databases`,
 			dbDumpFreezerIndex,
 			dbImportCmd,
 			dbExportCmd,
+			dbMigrateCmd,
+			dbIndexStateCmd,
 		},
 	}
 	dbInspectCmd = cli.Command{
@@ -196,6 +198,49 @@ WARNING: This is a low-level operation which may cause database corruption!`,
 		},
 		Description: "Exports the specified chain data to an RLP encoded stream, optionally gzip-compressed.",
 	}
+	dbMigrateToFlag = cli.StringFlag{
+		Name:  "to",
+		Usage: "Database engine to migrate to ('leveldb' or 'pebble')",
+	}
+	dbMigrateCmd = cli.Command{
+		Action: utils.MigrateFlags(migrateDatabase),
+		Name:   "migrate",
+		Usage:  "Migrate the chain database to another database engine",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.AncientFlag,
+			utils.SyncModeFlag,
+			utils.MainnetFlag,
+			utils.TestnetFlag,
+			utils.CacheFlag,
+			utils.CacheDatabaseFlag,
+			dbMigrateToFlag,
+		},
+		Description: `This command copies the chain database into a fresh database of the
+engine given by --to, along with the ancient store if it resides within the
+chain database. The copy is verified by comparing entry counts and sampled values
+before the new database replaces the old one, which is kept aside for removal.
+An interrupted migration resumes where it stopped when run again.`,
+	}
+	dbIndexStateCmd = cli.Command{
+		Action:    utils.MigrateFlags(indexState),
+		Name:      "index-state",
+		Usage:     "Rebuild the state index by re-executing the chain",
+		ArgsUsage: "<start (optional)>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.AncientFlag,
+			utils.SyncModeFlag,
+			utils.MainnetFlag,
+			utils.TestnetFlag,
+			utils.CacheFlag,
+			utils.CacheDatabaseFlag,
+		},
+		Description: `This command recreates the state index used by --state.index to serve
+historical state, by re-executing the canonical blocks after the given start
+block (genesis by default) up to the current head. The state of the start block
+must be available in the database.`,
+	}
 )
 
 func removeDB(ctx *cli.Context) error {
@@ -650,3 +695,116 @@ func exportChaindata(ctx *cli.Context) error {
 	defer db.Close()
 	return utils.ExportChaindata(ctx.Args().Get(1), kind, exporter(db), stop)
 }
+
+// migrateDatabase copies the chain database into one of another engine and
+// swaps it in once verified.
+func migrateDatabase(ctx *cli.Context) error {
+	target := ctx.String(dbMigrateToFlag.Name)
+	if target != rawdb.DBLeveldb && target != rawdb.DBPebble {
+		return fmt.Errorf("invalid migration target %q, allowed 'leveldb' or 'pebble'", target)
+	}
+	stack, config := makeConfigNode(ctx)
+	defer stack.Close()
+
+	name := "chaindata"
+	if ctx.GlobalString(utils.SyncModeFlag.Name) == "light" {
+		name = "lightchaindata"
+	}
+	var (
+		srcPath = stack.ResolvePath(name)
+		dstPath = srcPath + ".migrate"
+		cache   = ctx.GlobalInt(utils.CacheFlag.Name) * ctx.GlobalInt(utils.CacheDatabaseFlag.Name) / 100
+		handles = utils.MakeDatabaseHandles()
+	)
+	source := rawdb.PreexistingDatabase(srcPath)
+	if source == "" {
+		return fmt.Errorf("no database found at %s", srcPath)
+	}
+	if source == target {
+		return fmt.Errorf("database is already %s", target)
+	}
+	src, err := rawdb.OpenKeyValueStore(rawdb.OpenOptions{
+		Type:      source,
+		Directory: srcPath,
+		Cache:     cache / 2,
+		Handles:   handles / 2,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := rawdb.OpenKeyValueStore(rawdb.OpenOptions{
+		Type:      target,
+		Directory: dstPath,
+		Cache:     cache / 2,
+		Handles:   handles / 2,
+	})
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	var (
+		interrupt = make(chan os.Signal, 1)
+		stop      = make(chan struct{})
+	)
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+	defer close(interrupt)
+	go func() {
+		if _, ok := <-interrupt; ok {
+			log.Info("Interrupted during db migration, stopping at next batch")
+		}
+		close(stop)
+	}()
+	log.Info("Migrating database", "from", source, "to", target, "source", srcPath, "destination", dstPath)
+	if err := rawdb.MigrateDatabase(src, dst, stop); err != nil {
+		return err
+	}
+	if err := rawdb.VerifyMigration(src, dst, 1000); err != nil {
+		return err
+	}
+	// The ancient store is engine independent, only copy it if it lives within
+	// the database directory that's being replaced.
+	if name == "chaindata" && config.Eth.DatabaseFreezer == "" {
+		if err := rawdb.CopyFreezer(filepath.Join(srcPath, "ancient"), filepath.Join(dstPath, "ancient")); err != nil {
+			return err
+		}
+	}
+	src.Close()
+	dst.Close()
+
+	oldPath := srcPath + "." + source
+	if err := os.Rename(srcPath, oldPath); err != nil {
+		return err
+	}
+	if err := os.Rename(dstPath, srcPath); err != nil {
+		return err
+	}
+	log.Info("Database migrated, the old one can be removed", "engine", target, "old", oldPath)
+	return nil
+}
+
+func indexState(ctx *cli.Context) error {
+	if ctx.NArg() > 1 {
+		return fmt.Errorf("max 1 argument: %v", ctx.Command.ArgsUsage)
+	}
+	var start uint64
+	if ctx.NArg() == 1 {
+		number, err := strconv.ParseUint(ctx.Args().First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid start block: %v", err)
+		}
+		start = number
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, db := utils.MakeChain(ctx, stack)
+	defer db.Close()
+	defer chain.Stop()
+
+	return chain.RebuildStateIndex(start)
+}
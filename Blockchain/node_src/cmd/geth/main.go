@@ -58,6 +58,7 @@ var (
 		utils.BootnodesFlag,
 		utils.DataDirFlag,
 		utils.AncientFlag,
+		utils.DBEngineFlag,
 		utils.MinFreeDiskSpaceFlag,
 		utils.KeyStoreDirFlag,
 		utils.ExternalSignerFlag,
@@ -83,12 +84,17 @@ var (
 		utils.TxPoolGlobalSlotsFlag,
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
+		utils.TxPoolPrivatePeersFlag,
 		utils.TxPoolLifetimeFlag,
 		utils.SyncModeFlag,
 		utils.ExitWhenSyncedFlag,
 		utils.GCModeFlag,
 		utils.SnapshotFlag,
+		utils.StateSchemeFlag,
+		utils.StateHistoryFlag,
 		utils.TxLookupLimitFlag,
+		utils.HistoryRetentionFlag,
+		utils.StateIndexFlag,
 		utils.LightServeFlag,
 		utils.LightIngressFlag,
 		utils.LightEgressFlag,
@@ -204,6 +210,8 @@ func init() {
 		exportCommand,
 		importPreimagesCommand,
 		exportPreimagesCommand,
+		importHistoryCommand,
+		exportHistoryCommand,
 		removedbCommand,
 		dumpCommand,
 		dumpGenesisCommand,
@@ -228,6 +236,8 @@ func init() {
 		utils.ShowDeprecated,
 		// See snapshot.go
 		snapshotCommand,
+		// See congresscmd.go
+		congressCommand,
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 
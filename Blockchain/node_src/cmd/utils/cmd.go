@@ -18,6 +18,7 @@ import (
 	"io"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"syscall"
@@ -31,6 +32,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/internal/debug"
+	"github.com/ethereum/go-ethereum/internal/era"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -341,6 +343,165 @@ func ExportPreimages(db ethdb.Database, fn string) error {
 	return nil
 }
 
+// ExportHistory exports the blocks in the given range with their receipts into
+// verifiable archives in the specified directory, one archive per epoch.
+func ExportHistory(db ethdb.Database, dir string, network string, first, last uint64) error {
+	log.Info("Exporting history", "dir", dir, "first", first, "last", last)
+	if first > last {
+		return fmt.Errorf("invalid range: first (%d) is greater than last (%d)", first, last)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	var (
+		start    = time.Now()
+		reported = time.Now()
+	)
+	for epoch := first / era.MaxEraSize; epoch <= last/era.MaxEraSize; epoch++ {
+		from, to := epoch*era.MaxEraSize, (epoch+1)*era.MaxEraSize-1
+		if from < first {
+			from = first
+		}
+		if to > last {
+			to = last
+		}
+		tmp := filepath.Join(dir, fmt.Sprintf("%s-%05d.era1.tmp", network, epoch))
+		root, err := exportEpoch(db, tmp, from, to)
+		if err != nil {
+			os.Remove(tmp)
+			return fmt.Errorf("epoch %d: %v", epoch, err)
+		}
+		if err := os.Rename(tmp, filepath.Join(dir, era.Filename(network, epoch, root))); err != nil {
+			return err
+		}
+		if time.Since(reported) >= 8*time.Second {
+			log.Info("Exporting history", "exported", to-first+1, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	log.Info("Exported history", "dir", dir, "blocks", last-first+1, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// exportEpoch writes the canonical blocks in the given range into an archive.
+func exportEpoch(db ethdb.Database, fn string, from, to uint64) (common.Hash, error) {
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	defer fh.Close()
+
+	writer := bufio.NewWriter(fh)
+	builder := era.NewBuilder(writer)
+	for number := from; number <= to; number++ {
+		hash := rawdb.ReadCanonicalHash(db, number)
+		if hash == (common.Hash{}) {
+			return common.Hash{}, fmt.Errorf("canonical hash missing for block %d", number)
+		}
+		var (
+			header   = rawdb.ReadHeaderRLP(db, hash, number)
+			body     = rawdb.ReadBodyRLP(db, hash, number)
+			receipts = rawdb.ReadReceiptsRLP(db, hash, number)
+			td       = rawdb.ReadTd(db, hash, number)
+		)
+		if len(header) == 0 || len(body) == 0 || len(receipts) == 0 || td == nil {
+			return common.Hash{}, fmt.Errorf("block %d unavailable, history tail %d", number, rawdb.ReadHistoryTail(db))
+		}
+		if err := builder.Add(number, hash, header, body, receipts, td); err != nil {
+			return common.Hash{}, err
+		}
+	}
+	root, err := builder.Finalize()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := writer.Flush(); err != nil {
+		return common.Hash{}, err
+	}
+	return root, fh.Sync()
+}
+
+// ImportHistory restores the bodies and receipts of the local chain from the
+// archives of the network in the specified directory. Every archive is fully
+// verified before its blocks are written, and its blocks have to be part of the
+// local canonical chain.
+func ImportHistory(db ethdb.Database, dir string, network string) error {
+	files, err := era.ReadDir(dir, network)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no %s archives found in %s", network, dir)
+	}
+	var (
+		start    = time.Now()
+		restored int
+	)
+	for _, file := range files {
+		n, err := importArchive(db, file)
+		if err != nil {
+			return fmt.Errorf("%s: %v", filepath.Base(file), err)
+		}
+		restored += n
+	}
+	log.Info("Imported history", "archives", len(files), "restored", restored, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// importArchive verifies a single archive and writes the missing bodies and
+// receipts of its blocks into the key-value store.
+func importArchive(db ethdb.Database, file string) (int, error) {
+	archive, err := era.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer archive.Close()
+
+	root, err := archive.Verify()
+	if err != nil {
+		return 0, err
+	}
+	if want := filepath.Base(file); !strings.HasSuffix(want, fmt.Sprintf("-%x.era1", root[:4])) {
+		return 0, fmt.Errorf("accumulator %x doesn't match file name", root)
+	}
+	var (
+		batch    = db.NewBatch()
+		restored int
+	)
+	for number := archive.Start(); number < archive.Start()+archive.Count(); number++ {
+		header, body, receipts, _, err := archive.GetBlock(number)
+		if err != nil {
+			return restored, err
+		}
+		hash := header.Hash()
+		if canon := rawdb.ReadCanonicalHash(db, number); canon != hash {
+			return restored, fmt.Errorf("block %d (%x) is not part of the local chain", number, hash)
+		}
+		if rawdb.HasBody(db, hash, number) && rawdb.HasReceipts(db, hash, number) {
+			continue
+		}
+		rawdb.WriteBody(batch, hash, number, body)
+		list := make(types.Receipts, len(receipts))
+		for i, receipt := range receipts {
+			list[i] = (*types.Receipt)(receipt)
+		}
+		rawdb.WriteReceipts(batch, hash, number, list)
+		restored++
+
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return restored, err
+			}
+			batch.Reset()
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return restored, err
+	}
+	log.Info("Imported history archive", "file", filepath.Base(file), "first", archive.Start(), "count", archive.Count(), "restored", restored)
+	return restored, nil
+}
+
 // exportHeader is used in the export/import flow. When we do an export,
 // Whenever a backwards-incompatible change is made, the Version header
 // should be bumped.
@@ -0,0 +1,537 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package utils
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state/snapshot"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+const (
+	stateFileMagic   = "gethstate"
+	stateFileVersion = 0
+
+	stateChunkSize = 4 * 1024 * 1024 // Approximate size of the entries in a chunk
+
+	// stateFileHistory is the minimum number of blocks exported along the state,
+	// enough to serve the BLOCKHASH opcode after the import.
+	stateFileHistory = 256
+)
+
+// Record kinds following the header in a state file.
+const (
+	stateRecordChunk   = 0
+	stateRecordTrailer = 1
+)
+
+var emptyCodeHash = crypto.Keccak256Hash(nil)
+
+// stateFileHeader is the first item of a state file, carrying the target block
+// and enough of its ancestors for the consensus engine to resume from.
+//
+// Whenever a backwards-incompatible change is made, the Version should be bumped.
+type stateFileHeader struct {
+	Magic    string // Always set to 'gethstate' for disambiguation
+	Version  uint64
+	Genesis  common.Hash
+	Blocks   []stateFileBlock // Ancestors of the target block, the target last
+	UnixTime uint64
+}
+
+// stateFileBlock is a block exported along the state.
+type stateFileBlock struct {
+	Header   rlp.RawValue
+	Body     rlp.RawValue
+	Receipts rlp.RawValue // Storage encoded receipts
+	Td       *big.Int
+}
+
+// stateFileRecord frames a chunk or the trailer of a state file with the
+// checksum of its payload.
+type stateFileRecord struct {
+	Kind     uint64
+	Payload  []byte
+	Checksum common.Hash
+}
+
+// stateFileChunk is a batch of the flat state, ordered by account hash.
+type stateFileChunk struct {
+	Accounts []stateFileAccount
+	Slots    []stateFileSlot
+	Codes    [][]byte
+}
+
+// stateFileAccount is an account in its slim snapshot encoding.
+type stateFileAccount struct {
+	Hash common.Hash
+	Data []byte
+}
+
+// stateFileSlot is a storage slot in its snapshot encoding.
+type stateFileSlot struct {
+	Account common.Hash
+	Hash    common.Hash
+	Value   []byte
+}
+
+// stateFileTrailer closes a state file, committing to all its chunks.
+type stateFileTrailer struct {
+	Chunks   uint64
+	Accounts uint64
+	Slots    uint64
+	Codes    uint64
+	Digest   common.Hash // Hash of the concatenated chunk checksums
+}
+
+// stateFileFirst returns the first block exported along the state of the given
+// block. At least stateFileHistory blocks are exported, starting at a consensus
+// checkpoint so the validator set can be recovered from its header.
+func stateFileFirst(config *params.ChainConfig, number uint64) uint64 {
+	var epoch uint64
+	switch {
+	case config == nil:
+	case config.Congress != nil:
+		epoch = config.Congress.Epoch
+	case config.Clique != nil:
+		epoch = config.Clique.Epoch
+	}
+	if number <= stateFileHistory {
+		return 1
+	}
+	first := number - stateFileHistory
+	if epoch > 0 {
+		first -= first % epoch
+	}
+	if first == 0 {
+		return 1
+	}
+	return first
+}
+
+// stateFileWriter frames the flat state into checksummed chunks.
+type stateFileWriter struct {
+	w       io.Writer
+	chunk   stateFileChunk
+	size    int
+	trailer stateFileTrailer
+	digest  crypto.KeccakState
+}
+
+// flush writes out the pending chunk, if any.
+func (sw *stateFileWriter) flush() error {
+	if sw.size == 0 {
+		return nil
+	}
+	payload, err := rlp.EncodeToBytes(&sw.chunk)
+	if err != nil {
+		return err
+	}
+	checksum := crypto.Keccak256Hash(payload)
+	if err := rlp.Encode(sw.w, &stateFileRecord{Kind: stateRecordChunk, Payload: payload, Checksum: checksum}); err != nil {
+		return err
+	}
+	sw.digest.Write(checksum[:])
+	sw.trailer.Chunks++
+
+	sw.chunk, sw.size = stateFileChunk{}, 0
+	return nil
+}
+
+// grow accounts for the given number of bytes added to the pending chunk,
+// flushing it if it's full.
+func (sw *stateFileWriter) grow(size int) error {
+	sw.size += size
+	if sw.size >= stateChunkSize {
+		return sw.flush()
+	}
+	return nil
+}
+
+// finish flushes the pending chunk and writes the trailer.
+func (sw *stateFileWriter) finish() error {
+	if err := sw.flush(); err != nil {
+		return err
+	}
+	sw.digest.Read(sw.trailer.Digest[:])
+	payload, err := rlp.EncodeToBytes(&sw.trailer)
+	if err != nil {
+		return err
+	}
+	return rlp.Encode(sw.w, &stateFileRecord{Kind: stateRecordTrailer, Payload: payload, Checksum: crypto.Keccak256Hash(payload)})
+}
+
+// ExportState writes the flat state of the given canonical block from the
+// snapshot into a chunked, checksummed state file, along with the block and
+// its recent ancestors. If the file name ends with 'gz', gzip compression is
+// used.
+func ExportState(db ethdb.Database, snaptree *snapshot.Tree, fn string, number uint64) error {
+	hash := rawdb.ReadCanonicalHash(db, number)
+	if hash == (common.Hash{}) {
+		return fmt.Errorf("canonical hash missing for block %d", number)
+	}
+	header := rawdb.ReadHeader(db, hash, number)
+	if header == nil {
+		return fmt.Errorf("header missing for block %d", number)
+	}
+	genesis := rawdb.ReadCanonicalHash(db, 0)
+	log.Info("Exporting state", "file", fn, "number", number, "hash", hash, "root", header.Root)
+
+	acctIt, err := snaptree.AccountIterator(header.Root, common.Hash{})
+	if err != nil {
+		return fmt.Errorf("state of block %d not available in the snapshot: %v", number, err)
+	}
+	defer acctIt.Release()
+
+	fh, err := os.OpenFile(fn+".tmp", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(fn + ".tmp")
+	defer fh.Close()
+
+	buffer := bufio.NewWriter(fh)
+	var writer io.Writer = buffer
+	if strings.HasSuffix(fn, ".gz") {
+		writer = gzip.NewWriter(writer)
+	}
+	// Write the header with the target block and its ancestors
+	head := &stateFileHeader{
+		Magic:    stateFileMagic,
+		Version:  stateFileVersion,
+		Genesis:  genesis,
+		UnixTime: uint64(time.Now().Unix()),
+	}
+	for n := stateFileFirst(rawdb.ReadChainConfig(db, genesis), number); n <= number; n++ {
+		hash := rawdb.ReadCanonicalHash(db, n)
+		block := stateFileBlock{
+			Header:   rawdb.ReadHeaderRLP(db, hash, n),
+			Body:     rawdb.ReadBodyRLP(db, hash, n),
+			Receipts: rawdb.ReadReceiptsRLP(db, hash, n),
+			Td:       rawdb.ReadTd(db, hash, n),
+		}
+		if len(block.Header) == 0 || len(block.Body) == 0 || len(block.Receipts) == 0 || block.Td == nil {
+			return fmt.Errorf("block %d unavailable, history tail %d", n, rawdb.ReadHistoryTail(db))
+		}
+		head.Blocks = append(head.Blocks, block)
+	}
+	if err := rlp.Encode(writer, head); err != nil {
+		return err
+	}
+	// Stream the accounts in hash order, each followed by its storage
+	var (
+		sw     = &stateFileWriter{w: writer, digest: crypto.NewKeccakState()}
+		codes  = make(map[common.Hash]struct{})
+		start  = time.Now()
+		logged = time.Now()
+	)
+	for acctIt.Next() {
+		blob := common.CopyBytes(acctIt.Account())
+		account, err := snapshot.FullAccount(blob)
+		if err != nil {
+			return err
+		}
+		sw.chunk.Accounts = append(sw.chunk.Accounts, stateFileAccount{Hash: acctIt.Hash(), Data: blob})
+		sw.trailer.Accounts++
+
+		if codeHash := common.BytesToHash(account.CodeHash); codeHash != emptyCodeHash {
+			if _, ok := codes[codeHash]; !ok {
+				code := rawdb.ReadCode(db, codeHash)
+				if len(code) == 0 {
+					return fmt.Errorf("missing code %x of account %x", codeHash, acctIt.Hash())
+				}
+				codes[codeHash] = struct{}{}
+				sw.chunk.Codes = append(sw.chunk.Codes, code)
+				sw.trailer.Codes++
+				if err := sw.grow(len(code)); err != nil {
+					return err
+				}
+			}
+		}
+		if err := sw.grow(common.HashLength + len(blob)); err != nil {
+			return err
+		}
+		if common.BytesToHash(account.Root) != types.EmptyRootHash {
+			storageIt, err := snaptree.StorageIterator(header.Root, acctIt.Hash(), common.Hash{})
+			if err != nil {
+				return err
+			}
+			for storageIt.Next() {
+				slot := stateFileSlot{Account: acctIt.Hash(), Hash: storageIt.Hash(), Value: common.CopyBytes(storageIt.Slot())}
+				sw.chunk.Slots = append(sw.chunk.Slots, slot)
+				sw.trailer.Slots++
+				if err := sw.grow(2*common.HashLength + len(slot.Value)); err != nil {
+					storageIt.Release()
+					return err
+				}
+			}
+			err = storageIt.Error()
+			storageIt.Release()
+			if err != nil {
+				return err
+			}
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Exporting state", "at", acctIt.Hash(), "accounts", sw.trailer.Accounts, "slots", sw.trailer.Slots,
+				"elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := acctIt.Error(); err != nil {
+		return err
+	}
+	if err := sw.finish(); err != nil {
+		return err
+	}
+	if gz, ok := writer.(*gzip.Writer); ok {
+		if err := gz.Close(); err != nil {
+			return err
+		}
+	}
+	if err := buffer.Flush(); err != nil {
+		return err
+	}
+	if err := fh.Sync(); err != nil {
+		return err
+	}
+	if err := os.Rename(fn+".tmp", fn); err != nil {
+		return err
+	}
+	log.Info("Exported state", "file", fn, "number", number, "accounts", sw.trailer.Accounts, "slots", sw.trailer.Slots,
+		"codes", sw.trailer.Codes, "chunks", sw.trailer.Chunks, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// ImportState bootstraps a freshly initialized database from a state file: the
+// flat state is verified chunk by chunk, the state tries are rebuilt from it and
+// checked against the root in the header of the target block, which becomes the
+// head of the chain. If the expected hash is not zero, the target block has to
+// match it.
+func ImportState(db ethdb.Database, fn string, expected common.Hash) error {
+	log.Info("Importing state", "file", fn)
+
+	fh, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var reader io.Reader = bufio.NewReader(fh)
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return err
+		}
+	}
+	stream := rlp.NewStream(reader, 0)
+
+	// Read and verify the header against the local database
+	var head stateFileHeader
+	if err := stream.Decode(&head); err != nil {
+		return fmt.Errorf("could not decode header: %v", err)
+	}
+	if head.Magic != stateFileMagic {
+		return errors.New("incompatible data, wrong magic")
+	}
+	if head.Version != stateFileVersion {
+		return fmt.Errorf("incompatible version %d, (support only %d)", head.Version, stateFileVersion)
+	}
+	if genesis := rawdb.ReadCanonicalHash(db, 0); genesis != head.Genesis {
+		return fmt.Errorf("genesis mismatch: have %x, want %x", genesis, head.Genesis)
+	}
+	if current := rawdb.ReadHeadHeaderHash(db); current != head.Genesis {
+		return errors.New("database is not empty, state can only be imported after init")
+	}
+	if frozen, err := db.Ancients(); err == nil && frozen > 0 {
+		return errors.New("ancient store is not empty, state can only be imported after init")
+	}
+	if rawdb.ReadStateScheme(db) == rawdb.PathScheme {
+		return errors.New("state import is not supported in the path scheme")
+	}
+	blocks, receipts, err := verifyStateFileBlocks(head.Blocks, head.Genesis)
+	if err != nil {
+		return err
+	}
+	target := blocks[len(blocks)-1].Header()
+	if expected != (common.Hash{}) && target.Hash() != expected {
+		return fmt.Errorf("target block mismatch: have %x, want %x", target.Hash(), expected)
+	}
+	log.Info("Importing state", "number", target.Number, "hash", target.Hash(), "root", target.Root,
+		"age", common.PrettyDuration(time.Since(time.Unix(int64(head.UnixTime), 0))))
+
+	// Drop any leftover snapshot of the genesis and write the flat state
+	if err := snapshot.Wipe(db); err != nil {
+		return err
+	}
+	var (
+		trailer stateFileTrailer
+		count   stateFileTrailer
+		digest  = crypto.NewKeccakState()
+		batch   = db.NewBatch()
+		start   = time.Now()
+		logged  = time.Now()
+	)
+	for {
+		var record stateFileRecord
+		if err := stream.Decode(&record); err != nil {
+			return fmt.Errorf("chunk %d: %v", count.Chunks, err)
+		}
+		if crypto.Keccak256Hash(record.Payload) != record.Checksum {
+			return fmt.Errorf("chunk %d: checksum mismatch", count.Chunks)
+		}
+		if record.Kind == stateRecordTrailer {
+			if err := rlp.DecodeBytes(record.Payload, &trailer); err != nil {
+				return fmt.Errorf("could not decode trailer: %v", err)
+			}
+			break
+		}
+		if record.Kind != stateRecordChunk {
+			return fmt.Errorf("chunk %d: unknown kind %d", count.Chunks, record.Kind)
+		}
+		var chunk stateFileChunk
+		if err := rlp.DecodeBytes(record.Payload, &chunk); err != nil {
+			return fmt.Errorf("chunk %d: %v", count.Chunks, err)
+		}
+		for _, account := range chunk.Accounts {
+			rawdb.WriteAccountSnapshot(batch, account.Hash, account.Data)
+		}
+		for _, slot := range chunk.Slots {
+			rawdb.WriteStorageSnapshot(batch, slot.Account, slot.Hash, slot.Value)
+		}
+		for _, code := range chunk.Codes {
+			rawdb.WriteCode(batch, crypto.Keccak256Hash(code), code)
+		}
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+		digest.Write(record.Checksum[:])
+		count.Chunks++
+		count.Accounts += uint64(len(chunk.Accounts))
+		count.Slots += uint64(len(chunk.Slots))
+		count.Codes += uint64(len(chunk.Codes))
+
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Importing state", "chunks", count.Chunks, "accounts", count.Accounts, "slots", count.Slots,
+				"elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	batch.Reset()
+
+	digest.Read(count.Digest[:])
+	if count != trailer {
+		return fmt.Errorf("trailer mismatch: have %+v, want %+v", count, trailer)
+	}
+	// Rebuild the tries from the flat state, verifying the root of the block
+	log.Info("Regenerating state tries", "accounts", count.Accounts, "slots", count.Slots, "codes", count.Codes)
+	if err := snapshot.GenerateTrieFromDisk(db, target.Root); err != nil {
+		return err
+	}
+	// Write the blocks and mark the target as the head. The ancient store starts
+	// at the first exported block, nothing below it is available.
+	for i, block := range blocks {
+		rawdb.WriteTd(batch, block.Hash(), block.NumberU64(), head.Blocks[i].Td)
+		rawdb.WriteBlock(batch, block)
+		rawdb.WriteReceipts(batch, block.Hash(), block.NumberU64(), receipts[i])
+		rawdb.WriteCanonicalHash(batch, block.Hash(), block.NumberU64())
+		rawdb.WriteTxLookupEntriesByBlock(batch, block)
+	}
+	rawdb.WriteHeadHeaderHash(batch, target.Hash())
+	rawdb.WriteHeadFastBlockHash(batch, target.Hash())
+	rawdb.WriteHeadBlockHash(batch, target.Hash())
+	rawdb.WriteTxIndexTail(batch, blocks[0].NumberU64())
+	if rawdb.ReadStateScheme(db) == "" {
+		rawdb.WriteStateScheme(batch, rawdb.HashScheme)
+	}
+	if first := blocks[0].NumberU64(); first > 1 {
+		if err := db.ResetAncients(first); err != nil {
+			return err
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	log.Info("Imported state", "file", fn, "number", target.Number, "hash", target.Hash(), "accounts", count.Accounts,
+		"slots", count.Slots, "codes", count.Codes, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// verifyStateFileBlocks checks the exported blocks form a chain with consistent
+// contents and total difficulties, returning the decoded blocks and receipts.
+func verifyStateFileBlocks(exported []stateFileBlock, genesis common.Hash) ([]*types.Block, []types.Receipts, error) {
+	if len(exported) == 0 {
+		return nil, nil, errors.New("no blocks in state file")
+	}
+	var (
+		blocks   = make([]*types.Block, len(exported))
+		receipts = make([]types.Receipts, len(exported))
+	)
+	for i, block := range exported {
+		header := new(types.Header)
+		if err := rlp.DecodeBytes(block.Header, header); err != nil {
+			return nil, nil, fmt.Errorf("block %d: %v", i, err)
+		}
+		number := header.Number.Uint64()
+		switch {
+		case block.Td == nil:
+			return nil, nil, fmt.Errorf("block %d: total difficulty missing", number)
+		case i == 0 && number == 0:
+			return nil, nil, errors.New("genesis block in state file")
+		case i == 0 && number == 1 && header.ParentHash != genesis:
+			return nil, nil, fmt.Errorf("block %d: not a descendant of the genesis", number)
+		case i > 0 && (number != blocks[i-1].NumberU64()+1 || header.ParentHash != blocks[i-1].Hash()):
+			return nil, nil, fmt.Errorf("block %d: not a child of block %d", number, blocks[i-1].NumberU64())
+		case i > 0 && block.Td.Cmp(new(big.Int).Add(exported[i-1].Td, header.Difficulty)) != 0:
+			return nil, nil, fmt.Errorf("block %d: total difficulty mismatch", number)
+		}
+		body := new(types.Body)
+		if err := rlp.DecodeBytes(block.Body, body); err != nil {
+			return nil, nil, fmt.Errorf("block %d: %v", number, err)
+		}
+		if hash := types.DeriveSha(types.Transactions(body.Transactions), trie.NewStackTrie(nil)); hash != header.TxHash {
+			return nil, nil, fmt.Errorf("block %d: transaction root mismatch: have %x, want %x", number, hash, header.TxHash)
+		}
+		if hash := types.CalcUncleHash(body.Uncles); hash != header.UncleHash {
+			return nil, nil, fmt.Errorf("block %d: uncle hash mismatch: have %x, want %x", number, hash, header.UncleHash)
+		}
+		var stored []*types.ReceiptForStorage
+		if err := rlp.DecodeBytes(block.Receipts, &stored); err != nil {
+			return nil, nil, fmt.Errorf("block %d: %v", number, err)
+		}
+		if len(stored) != len(body.Transactions) {
+			return nil, nil, fmt.Errorf("block %d: receipt count mismatch: have %d, want %d", number, len(stored), len(body.Transactions))
+		}
+		receipts[i] = make(types.Receipts, len(stored))
+		for j, receipt := range stored {
+			receipts[i][j] = (*types.Receipt)(receipt)
+			receipts[i][j].Type = body.Transactions[j].Type()
+		}
+		if hash := types.DeriveSha(receipts[i], trie.NewStackTrie(nil)); hash != header.ReceiptHash {
+			return nil, nil, fmt.Errorf("block %d: receipt root mismatch: have %x, want %x", number, hash, header.ReceiptHash)
+		}
+		blocks[i] = types.NewBlockWithHeader(header).WithBody(body.Transactions, body.Uncles)
+	}
+	return blocks, receipts, nil
+}
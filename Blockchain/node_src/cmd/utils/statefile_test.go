@@ -0,0 +1,119 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package utils
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that a node bootstrapped from an exported state file has the full
+// state of the exported block and can continue processing the chain.
+func TestStateExportImport(t *testing.T) {
+	var (
+		key, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr     = crypto.PubkeyToAddress(key.PublicKey)
+		contract = common.Address{0xaa}
+		gspec    = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: core.GenesisAlloc{
+				addr:     {Balance: big.NewInt(params.Ether)},
+				contract: {Balance: big.NewInt(1), Code: []byte{0x60, 0x00}, Storage: map[common.Hash]common.Hash{{0x01}: {0x02}, {0x03}: {0x04}}},
+			},
+		}
+		signer = types.LatestSigner(gspec.Config)
+		source = rawdb.NewMemoryDatabase()
+	)
+	genesis := gspec.MustCommit(source)
+	blocks, _ := core.GenerateChain(gspec.Config, genesis, ethash.NewFaker(), source, 300, func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(addr), common.Address{0x01, byte(i), byte(i >> 8)}, big.NewInt(1000), params.TxGas, b.BaseFee(), nil), signer, key)
+		b.AddTx(tx)
+	})
+	chain, err := core.NewBlockChain(source, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create source chain: %v", err)
+	}
+	if _, err := chain.InsertChain(blocks[:290]); err != nil {
+		t.Fatalf("failed to insert source chain: %v", err)
+	}
+	file := filepath.Join(t.TempDir(), "state.rlp.gz")
+	if err := ExportState(source, chain.Snapshots(), file, 290); err != nil {
+		t.Fatalf("failed to export state: %v", err)
+	}
+	chain.Stop()
+
+	newDatabase := func() ethdb.Database {
+		db, err := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), t.TempDir(), "", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gspec.MustCommit(db)
+		return db
+	}
+	// The import is rejected for a different target block
+	db := newDatabase()
+	defer db.Close()
+
+	if err := ImportState(db, file, blocks[288].Hash()); err == nil {
+		t.Fatalf("state imported for mismatching block")
+	}
+	if err := ImportState(db, file, blocks[289].Hash()); err != nil {
+		t.Fatalf("failed to import state: %v", err)
+	}
+	if err := ImportState(db, file, common.Hash{}); err == nil {
+		t.Fatalf("state imported twice")
+	}
+	if frozen, _ := db.Ancients(); frozen != 34 {
+		t.Fatalf("ancient store start mismatch: have %d, want %d", frozen, 34)
+	}
+	// The imported chain resumes from the exported block
+	imported, err := core.NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create imported chain: %v", err)
+	}
+	defer imported.Stop()
+
+	if head := imported.CurrentBlock(); head.Hash() != blocks[289].Hash() {
+		t.Fatalf("head mismatch: have #%d [%x], want #%d [%x]", head.NumberU64(), head.Hash(), 290, blocks[289].Hash())
+	}
+	statedb, err := imported.State()
+	if err != nil {
+		t.Fatalf("failed to open head state: %v", err)
+	}
+	if have := statedb.GetState(contract, common.Hash{0x01}); have != (common.Hash{0x02}) {
+		t.Fatalf("storage mismatch: have %x", have)
+	}
+	if have := statedb.GetBalance(common.Address{0x01, 0x10, 0x01}); have.Cmp(big.NewInt(1000)) != 0 {
+		t.Fatalf("balance mismatch: have %v", have)
+	}
+	if tx, _, _, _ := rawdb.ReadTransaction(db, blocks[289].Transactions()[0].Hash()); tx == nil {
+		t.Fatalf("transaction of the imported block not indexed")
+	}
+	if _, err := imported.InsertChain(blocks[290:]); err != nil {
+		t.Fatalf("failed to extend the imported chain: %v", err)
+	}
+	// Corrupted files are rejected
+	blob, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blob[len(blob)/2] ^= 0xff
+	if err := os.WriteFile(file, blob, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ImportState(newDatabase(), file, common.Hash{}); err == nil {
+		t.Fatalf("corrupted state file imported")
+	}
+}
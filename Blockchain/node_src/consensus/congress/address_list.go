@@ -0,0 +1,353 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package congress
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/congress/systemcontract"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Events emitted by the address list contract when the blacklist or the event
+// check rules change.
+var (
+	addrListEvents = systemcontract.GetInteractiveABI()[systemcontract.AddressListContractName].Events
+
+	blackAddrAddedTopic   = addrListEvents["BlackAddrAdded"].ID
+	blackAddrRemovedTopic = addrListEvents["BlackAddrRemoved"].ID
+	ruleAddedTopic        = addrListEvents["RuleAdded"].ID
+	ruleUpdatedTopic      = addrListEvents["RuleUpdated"].ID
+	ruleRemovedTopic      = addrListEvents["RuleRemoved"].ID
+)
+
+// errMissingState is returned if the address lists have to be loaded from the
+// contract, but the state of the block isn't available.
+var errMissingState = errors.New("missing state to load the address lists")
+
+func (d blacklistDirection) String() string {
+	switch d {
+	case DirectionFrom:
+		return "from"
+	case DirectionTo:
+		return "to"
+	case DirectionBoth:
+		return "both"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint(d))
+	}
+}
+
+// addressLists is the blacklist and the event check rules of the address list
+// contract after a block. The maps are shared with the lists of the following
+// blocks until they change, so they must never be modified in place.
+type addressLists struct {
+	Number uint64                                `json:"number"` // Block number where the lists were taken
+	Hash   common.Hash                           `json:"hash"`   // Block hash where the lists were taken
+	Root   common.Hash                           `json:"root"`   // Storage root of the contract the lists were loaded from, if unchanged since
+	Blacks map[common.Address]blacklistDirection `json:"blacks"` // Blacklisted addresses and their directions
+	Rules  map[common.Hash]*EventCheckRule       `json:"rules"`  // Event check rules by event signature
+}
+
+// newAddressLists creates empty address lists for the given block.
+func newAddressLists(number uint64, hash common.Hash) *addressLists {
+	return &addressLists{
+		Number: number,
+		Hash:   hash,
+		Blacks: make(map[common.Address]blacklistDirection),
+		Rules:  make(map[common.Hash]*EventCheckRule),
+	}
+}
+
+// loadAddressLists loads existing address lists from the database.
+func loadAddressLists(db ethdb.Database, hash common.Hash) (*addressLists, error) {
+	blob, err := db.Get(append([]byte("congress-addresslists-"), hash[:]...))
+	if err != nil {
+		return nil, err
+	}
+	lists := new(addressLists)
+	if err := json.Unmarshal(blob, lists); err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+// store inserts the address lists into the database.
+func (l *addressLists) store(db ethdb.Database) error {
+	blob, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return db.Put(append([]byte("congress-addresslists-"), l.Hash[:]...), blob)
+}
+
+// apply creates the address lists after the given block, which must be a child
+// of the block of the lists, from the address list events in its receipts. The
+// storage root is only carried over if the block emitted no such events.
+func (l *addressLists) apply(header *types.Header, receipts types.Receipts) (*addressLists, error) {
+	next := &addressLists{
+		Number: header.Number.Uint64(),
+		Hash:   header.Hash(),
+		Root:   l.Root,
+		Blacks: l.Blacks,
+		Rules:  l.Rules,
+	}
+	var blacksCopied, rulesCopied bool
+	for _, receipt := range receipts {
+		for _, lg := range receipt.Logs {
+			if lg.Address != systemcontract.AddressListContractAddr || len(lg.Topics) < 2 {
+				continue
+			}
+			switch lg.Topics[0] {
+			case blackAddrAddedTopic, blackAddrRemovedTopic:
+				if len(lg.Data) != 32 {
+					return nil, fmt.Errorf("invalid blacklist event data length %d", len(lg.Data))
+				}
+				if !blacksCopied {
+					blacks := make(map[common.Address]blacklistDirection, len(next.Blacks)+1)
+					for addr, d := range next.Blacks {
+						blacks[addr] = d
+					}
+					next.Blacks, blacksCopied = blacks, true
+				}
+				addr := common.BytesToAddress(lg.Topics[1].Bytes())
+				d := blacklistDirection(new(big.Int).SetBytes(lg.Data).Uint64())
+				if lg.Topics[0] == blackAddrAddedTopic {
+					addBlacklistDirection(next.Blacks, addr, d)
+				} else {
+					removeBlacklistDirection(next.Blacks, addr, d)
+				}
+
+			case ruleAddedTopic, ruleUpdatedTopic, ruleRemovedTopic:
+				if len(lg.Data) != 64 {
+					return nil, fmt.Errorf("invalid rule event data length %d", len(lg.Data))
+				}
+				if !rulesCopied {
+					rules := make(map[common.Hash]*EventCheckRule, len(next.Rules)+1)
+					for sig, rule := range next.Rules {
+						rules[sig] = rule
+					}
+					next.Rules, rulesCopied = rules, true
+				}
+				sig := lg.Topics[1]
+				idx := int(new(big.Int).SetBytes(lg.Data[:32]).Uint64())
+
+				// Copy the rule too, it might be shared with the previous lists
+				rule := &EventCheckRule{EventSig: sig, Checks: make(map[int]common.AddressCheckType)}
+				if old, ok := next.Rules[sig]; ok {
+					for i, ct := range old.Checks {
+						rule.Checks[i] = ct
+					}
+				}
+				if lg.Topics[0] == ruleRemovedTopic {
+					delete(rule.Checks, idx)
+				} else {
+					rule.Checks[idx] = common.AddressCheckType(new(big.Int).SetBytes(lg.Data[32:]).Uint64())
+				}
+				if len(rule.Checks) == 0 {
+					delete(next.Rules, sig)
+				} else {
+					next.Rules[sig] = rule
+				}
+			}
+		}
+	}
+	if blacksCopied || rulesCopied {
+		next.Root = common.Hash{}
+	}
+	return next, nil
+}
+
+// addBlacklistDirection adds the address to the blacklist in the given direction,
+// merging it with the direction the address is already blacklisted in.
+func addBlacklistDirection(blacks map[common.Address]blacklistDirection, addr common.Address, d blacklistDirection) {
+	if old, ok := blacks[addr]; ok && old != d {
+		d = DirectionBoth
+	}
+	blacks[addr] = d
+}
+
+// removeBlacklistDirection removes the address from the blacklist in the given
+// direction, keeping it blacklisted in the other one.
+func removeBlacklistDirection(blacks map[common.Address]blacklistDirection, addr common.Address, d blacklistDirection) {
+	old, ok := blacks[addr]
+	switch {
+	case !ok:
+	case d == DirectionBoth || old == d:
+		delete(blacks, addr)
+	case old == DirectionBoth && d == DirectionFrom:
+		blacks[addr] = DirectionTo
+	case old == DirectionBoth && d == DirectionTo:
+		blacks[addr] = DirectionFrom
+	}
+}
+
+// addressListRoot returns the storage root of the address list contract in the
+// given state, which commits to the full contents of the lists, whatever the
+// layout of the contract and whether their changes emitted events or not.
+func addressListRoot(statedb *state.StateDB) common.Hash {
+	if tr := statedb.StorageTrie(systemcontract.AddressListContractAddr); tr != nil {
+		return tr.Hash()
+	}
+	return common.Hash{}
+}
+
+// getAddressLists retrieves the address lists after the given block. They are
+// replayed from the events of the address list contract in the receipts of the
+// blocks since the closest lists cached in memory or stored in the database,
+// so the lists of a new block cost a bloom lookup and a storage root check
+// unless they change. As the lists are tracked by block hash, reorgs only
+// replay the blocks of the new branch.
+//
+// The replayed lists are only trusted if the storage of the contract in the
+// state of the block is the one they were loaded from, otherwise they are
+// reloaded from the contract, as they are if there are no lists close enough.
+// This catches the writes no event reports too, like governance actions, so
+// the lists are only trusted unchecked if the state is unavailable. The
+// fallback state is only used if the state of the block can't be retrieved, it
+// must be the state of the block too.
+func (c *Congress) getAddressLists(chain consensus.ChainHeaderReader, number uint64, hash common.Hash, fallback *state.StateDB) (*addressLists, error) {
+	if v, ok := c.addrLists.Get(hash); ok {
+		addressListsHitMeter.Mark(1)
+		return v.(*addressLists), nil
+	}
+	addressListsMissMeter.Mark(1)
+
+	c.addrListsLock.Lock()
+	defer c.addrListsLock.Unlock()
+
+	var (
+		lists   *addressLists
+		headers []*types.Header
+	)
+	for n, h := number, hash; lists == nil; {
+		if v, ok := c.addrLists.Get(h); ok {
+			lists = v.(*addressLists)
+			break
+		}
+		if n%CheckpointInterval == 0 {
+			if stored, err := loadAddressLists(c.db, h); err == nil {
+				lists = stored
+				break
+			}
+		}
+		// The contract is deployed at the RedCoast fork, the lists start empty
+		if n == 0 || !c.chainConfig.IsRedCoast(new(big.Int).SetUint64(n)) {
+			lists = newAddressLists(n, h)
+			break
+		}
+		// The Sophon fork initializes the rules without any events, so its
+		// block can't be replayed, nor can too many blocks
+		if len(headers) == CheckpointInterval || (c.chainConfig.SophonBlock != nil && c.chainConfig.SophonBlock.Uint64() == n) {
+			break
+		}
+		header := chain.GetHeader(h, n)
+		if header == nil {
+			return nil, consensus.ErrUnknownAncestor
+		}
+		headers = append(headers, header)
+		n, h = n-1, header.ParentHash
+	}
+	header := chain.GetHeader(hash, number)
+	if header == nil {
+		return nil, consensus.ErrUnknownAncestor
+	}
+	statedb := c.addressListState(header, fallback)
+	if lists != nil {
+		var err error
+		for i := len(headers) - 1; i >= 0 && err == nil; i-- {
+			lists, err = c.applyAddressLogs(lists, headers[i])
+		}
+		switch {
+		case err != nil:
+			log.Warn("Failed to replay address list events", "number", number, "hash", hash, "err", err)
+			lists = nil
+		case statedb != nil && lists.Root != addressListRoot(statedb):
+			if lists.Root != (common.Hash{}) {
+				log.Debug("Address list storage changed without events, reloading", "number", number, "hash", hash)
+			}
+			lists = nil
+		default:
+			return lists, nil
+		}
+	}
+	if statedb == nil {
+		return nil, errMissingState
+	}
+	lists, err := c.fetchAddressLists(header, statedb)
+	if err != nil {
+		return nil, err
+	}
+	c.addrLists.Add(lists.Hash, lists)
+	if lists.Number%CheckpointInterval == 0 {
+		if err := lists.store(c.db); err != nil {
+			log.Warn("Failed to store address lists", "number", lists.Number, "hash", lists.Hash, "err", err)
+		}
+	}
+	return lists, nil
+}
+
+// addressListState retrieves the state of the block to check or load the
+// address lists, or returns the fallback state if it's unavailable.
+func (c *Congress) addressListState(header *types.Header, fallback *state.StateDB) *state.StateDB {
+	if c.stateFn != nil {
+		if statedb, err := c.stateFn(header.Root); err == nil {
+			return statedb
+		}
+	}
+	return fallback
+}
+
+// applyAddressLogs creates the address lists after the given block from the
+// lists of its parent, caching them.
+func (c *Congress) applyAddressLogs(parent *addressLists, header *types.Header) (*addressLists, error) {
+	var receipts types.Receipts
+	if types.BloomLookup(header.Bloom, systemcontract.AddressListContractAddr) {
+		if receipts = rawdb.ReadRawReceipts(c.db, header.Hash(), header.Number.Uint64()); receipts == nil {
+			return nil, fmt.Errorf("missing receipts of block #%d [%x..]", header.Number, header.Hash().Bytes()[:4])
+		}
+	}
+	lists, err := parent.apply(header, receipts)
+	if err != nil {
+		return nil, err
+	}
+	c.addrLists.Add(lists.Hash, lists)
+	if lists.Number%CheckpointInterval == 0 {
+		if err := lists.store(c.db); err != nil {
+			log.Warn("Failed to store address lists", "number", lists.Number, "hash", lists.Hash, "err", err)
+		}
+	}
+	return lists, nil
+}
+
+// fetchAddressLists loads the address lists after the given block from the
+// contract, using the state of the block.
+func (c *Congress) fetchAddressLists(header *types.Header, statedb *state.StateDB) (*addressLists, error) {
+	lists := newAddressLists(header.Number.Uint64(), header.Hash())
+	lists.Root = addressListRoot(statedb)
+	if !c.chainConfig.IsRedCoast(header.Number) {
+		return lists, nil
+	}
+	blacks, err := c.loadBlacklist(header, statedb)
+	if err != nil {
+		return nil, err
+	}
+	lists.Blacks = blacks
+	if c.chainConfig.IsSophon(header.Number) {
+		rules, err := c.loadEventCheckRules(header, statedb)
+		if err != nil {
+			return nil, err
+		}
+		lists.Rules = rules
+	}
+	return lists, nil
+}
@@ -0,0 +1,210 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package congress
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/congress/systemcontract"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Tests that the address lists are replayed from the address list events, also
+// across reorgs, and that they are checked against the contract storage.
+func TestAddressListsReplay(t *testing.T) {
+	var (
+		a, b, c, d = common.Address{0xa}, common.Address{0xb}, common.Address{0xc}, common.Address{0xd}
+		sig        = common.Hash{0x51}
+	)
+	blackLog := func(topic common.Hash, addr common.Address, d blacklistDirection) *types.Log {
+		return &types.Log{
+			Address: systemcontract.AddressListContractAddr,
+			Topics:  []common.Hash{topic, common.BytesToHash(addr.Bytes())},
+			Data:    common.BigToHash(big.NewInt(int64(d))).Bytes(),
+		}
+	}
+	ruleLog := func(topic common.Hash, idx int, ct common.AddressCheckType) *types.Log {
+		return &types.Log{
+			Address: systemcontract.AddressListContractAddr,
+			Topics:  []common.Hash{topic, sig},
+			Data:    append(common.BigToHash(big.NewInt(int64(idx))).Bytes(), common.BigToHash(big.NewInt(int64(ct))).Bytes()...),
+		}
+	}
+	config := *params.AllCongressProtocolChanges
+	config.RedCoastBlock, config.SophonBlock = big.NewInt(3), nil
+	config.Congress = &params.CongressConfig{Epoch: 8}
+
+	db := rawdb.NewMemoryDatabase()
+	genesis := (&core.Genesis{Config: &config, ExtraData: make([]byte, extraVanity+extraSeal)}).MustCommit(db)
+
+	// makeBlock writes a block with the given logs on top of the parent
+	makeBlock := func(parent *types.Header, canonical bool, logs ...*types.Log) *types.Header {
+		receipts := types.Receipts{{Status: types.ReceiptStatusSuccessful, Logs: logs}}
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     new(big.Int).Add(parent.Number, common.Big1),
+			Bloom:      types.CreateBloom(receipts),
+			Extra:      []byte{byte(len(logs))},
+		}
+		rawdb.WriteHeader(db, header)
+		rawdb.WriteReceipts(db, header.Hash(), header.Number.Uint64(), receipts)
+		if canonical {
+			rawdb.WriteCanonicalHash(db, header.Hash(), header.Number.Uint64())
+		}
+		return header
+	}
+	// The contract is deployed at the third block, which has the first events
+	var headers []*types.Header
+	headers = append(headers, makeBlock(makeBlock(makeBlock(genesis.Header(), true), true), true, blackLog(blackAddrAddedTopic, a, DirectionFrom), blackLog(blackAddrAddedTopic, b, DirectionTo)))
+	headers = append(headers, makeBlock(headers[0], true, &types.Log{Address: common.Address{0x1}, Topics: []common.Hash{blackAddrAddedTopic, common.BytesToHash(d.Bytes())}, Data: make([]byte, 32)}))
+	headers = append(headers, makeBlock(headers[1], true, blackLog(blackAddrAddedTopic, a, DirectionTo), ruleLog(ruleAddedTopic, 1, common.CheckFrom), ruleLog(ruleAddedTopic, 2, common.CheckFrom), ruleLog(ruleUpdatedTopic, 2, common.CheckTo)))
+	headers = append(headers, makeBlock(headers[2], true, blackLog(blackAddrRemovedTopic, b, DirectionTo), blackLog(blackAddrRemovedTopic, a, DirectionFrom), ruleLog(ruleRemovedTopic, 1, common.CheckFrom)))
+	side := makeBlock(headers[1], false, blackLog(blackAddrAddedTopic, c, DirectionBoth))
+
+	engine := New(&config, db)
+	chain, err := core.NewHeaderChain(db, &config, engine, func() bool { return false })
+	if err != nil {
+		t.Fatalf("failed to create header chain: %v", err)
+	}
+	tests := []struct {
+		header *types.Header
+		blacks map[common.Address]blacklistDirection
+		rules  map[common.Hash]*EventCheckRule
+	}{
+		{headers[3], map[common.Address]blacklistDirection{a: DirectionTo}, map[common.Hash]*EventCheckRule{sig: {EventSig: sig, Checks: map[int]common.AddressCheckType{2: common.CheckTo}}}},
+		{side, map[common.Address]blacklistDirection{a: DirectionFrom, b: DirectionTo, c: DirectionBoth}, map[common.Hash]*EventCheckRule{}},
+		{headers[2], map[common.Address]blacklistDirection{a: DirectionBoth, b: DirectionTo}, map[common.Hash]*EventCheckRule{sig: {EventSig: sig, Checks: map[int]common.AddressCheckType{1: common.CheckFrom, 2: common.CheckTo}}}},
+		{headers[1], map[common.Address]blacklistDirection{a: DirectionFrom, b: DirectionTo}, map[common.Hash]*EventCheckRule{}},
+		{chain.GetHeaderByNumber(2), map[common.Address]blacklistDirection{}, map[common.Hash]*EventCheckRule{}},
+	}
+	for i, tt := range tests {
+		lists, err := engine.getAddressLists(chain, tt.header.Number.Uint64(), tt.header.Hash(), nil)
+		if err != nil {
+			t.Fatalf("test %d: failed to retrieve address lists: %v", i, err)
+		}
+		if !reflect.DeepEqual(lists.Blacks, tt.blacks) {
+			t.Errorf("test %d: blacklist mismatch: have %v, want %v", i, lists.Blacks, tt.blacks)
+		}
+		if !reflect.DeepEqual(lists.Rules, tt.rules) {
+			t.Errorf("test %d: rules mismatch: have %v, want %v", i, lists.Rules, tt.rules)
+		}
+	}
+	// Check that the replayed lists are only trusted on the contract storage
+	// they were loaded from, which no event reports the changes of
+	lists, _ := engine.getAddressLists(chain, headers[3].Number.Uint64(), headers[3].Hash(), nil)
+	if lists.Root != (common.Hash{}) {
+		t.Errorf("address lists updated by events kept the storage root %x", lists.Root)
+	}
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetState(systemcontract.AddressListContractAddr, common.BigToHash(big.NewInt(3)), common.BigToHash(common.Big1))
+
+	loaded := *lists
+	loaded.Root = addressListRoot(statedb)
+	fresh := New(&config, db)
+	fresh.addrLists.Add(loaded.Hash, &loaded)
+
+	quiet := makeBlock(headers[3], true)
+	replayed, err := fresh.getAddressLists(chain, quiet.Number.Uint64(), quiet.Hash(), statedb)
+	if err != nil {
+		t.Fatalf("failed to replay address lists: %v", err)
+	}
+	if replayed.Root != loaded.Root || !reflect.DeepEqual(replayed.Blacks, lists.Blacks) {
+		t.Errorf("address lists on unchanged storage not replayed: have %v, want %v", replayed, lists)
+	}
+	statedb.SetState(systemcontract.AddressListContractAddr, common.BigToHash(big.NewInt(3)), common.BigToHash(common.Big2))
+	if addressListRoot(statedb) == loaded.Root {
+		t.Fatalf("storage root unchanged by a storage write")
+	}
+	rewritten := makeBlock(quiet, true)
+	// The test contract has no code, so reloading the lists fails
+	if _, err := fresh.getAddressLists(chain, rewritten.Number.Uint64(), rewritten.Hash(), statedb); err == nil {
+		t.Errorf("address lists on rewritten storage replayed instead of reloaded")
+	}
+	// Check that the lists survive a round trip through the database
+	if err := lists.store(db); err != nil {
+		t.Fatalf("failed to store address lists: %v", err)
+	}
+	stored, err := loadAddressLists(db, lists.Hash)
+	if err != nil {
+		t.Fatalf("failed to load address lists: %v", err)
+	}
+	if !reflect.DeepEqual(stored, lists) {
+		t.Errorf("stored address lists mismatch: have %v, want %v", stored, lists)
+	}
+	// Check the membership reported over RPC
+	api := &API{chain: chain, congress: engine}
+	for number, want := range map[rpc.BlockNumber]string{3: "from", 5: "both", 6: "to"} {
+		number := number
+		entry, err := api.GetBlacklistEntry(a, &number)
+		if err != nil {
+			t.Fatalf("block #%d: failed to retrieve blacklist entry: %v", number, err)
+		}
+		if !entry.Blacklisted || entry.Direction != want {
+			t.Errorf("block #%d: blacklist entry mismatch: have %v, want %s", number, entry, want)
+		}
+	}
+	number := rpc.BlockNumber(6)
+	if entry, _ := api.GetBlacklistEntry(b, &number); entry.Blacklisted {
+		t.Errorf("removed address still blacklisted: %v", entry)
+	}
+}
+
+// Tests that the address list events are decoded as the contract ABI encodes
+// them.
+func TestAddressListEvents(t *testing.T) {
+	events := systemcontract.GetInteractiveABI()[systemcontract.AddressListContractName].Events
+	for name, want := range map[string]string{
+		"BlackAddrAdded":   "BlackAddrAdded(address,uint8)",
+		"BlackAddrRemoved": "BlackAddrRemoved(address,uint8)",
+		"RuleAdded":        "RuleAdded(bytes32,uint128,uint8)",
+		"RuleUpdated":      "RuleUpdated(bytes32,uint128,uint8)",
+		"RuleRemoved":      "RuleRemoved(bytes32,uint128,uint8)",
+	} {
+		if have := events[name].Sig; have != want {
+			t.Errorf("event %s signature mismatch: have %s, want %s", name, have, want)
+		}
+	}
+	var (
+		addr = common.Address{0xa}
+		sig  = common.Hash{0x51}
+	)
+	pack := func(name string, topic common.Hash, args ...interface{}) *types.Log {
+		data, err := events[name].Inputs.NonIndexed().Pack(args...)
+		if err != nil {
+			t.Fatalf("failed to pack %s: %v", name, err)
+		}
+		return &types.Log{
+			Address: systemcontract.AddressListContractAddr,
+			Topics:  []common.Hash{events[name].ID, topic},
+			Data:    data,
+		}
+	}
+	receipts := types.Receipts{{Logs: []*types.Log{
+		pack("BlackAddrAdded", common.BytesToHash(addr.Bytes()), uint8(DirectionBoth)),
+		pack("BlackAddrRemoved", common.BytesToHash(addr.Bytes()), uint8(DirectionFrom)),
+		pack("RuleAdded", sig, big.NewInt(1), uint8(common.CheckFrom)),
+		pack("RuleAdded", sig, big.NewInt(2), uint8(common.CheckFrom)),
+		pack("RuleUpdated", sig, big.NewInt(2), uint8(common.CheckBothInAny)),
+		pack("RuleRemoved", sig, big.NewInt(1), uint8(common.CheckFrom)),
+	}}}
+	header := &types.Header{Number: common.Big1}
+	lists, err := newAddressLists(0, common.Hash{}).apply(header, receipts)
+	if err != nil {
+		t.Fatalf("failed to apply events: %v", err)
+	}
+	if want := map[common.Address]blacklistDirection{addr: DirectionTo}; !reflect.DeepEqual(lists.Blacks, want) {
+		t.Errorf("blacklist mismatch: have %v, want %v", lists.Blacks, want)
+	}
+	want := map[common.Hash]*EventCheckRule{sig: {EventSig: sig, Checks: map[int]common.AddressCheckType{2: common.CheckBothInAny}}}
+	if !reflect.DeepEqual(lists.Rules, want) {
+		t.Errorf("rules mismatch: have %v, want %v", lists.Rules, want)
+	}
+}
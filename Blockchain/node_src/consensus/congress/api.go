@@ -3,11 +3,20 @@
 package congress
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math/big"
+	"sort"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/congress/systemcontract"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
@@ -76,58 +85,339 @@ func (api *API) GetValidatorsAtHash(hash common.Hash) ([]common.Address, error)
 	return snap.validators(), nil
 }
 
-type status struct {
-	InturnPercent float64                `json:"inturnPercent"`
-	SigningStatus map[common.Address]int `json:"sealerActivity"`
-	NumBlocks     uint64                 `json:"numBlocks"`
-}
-
-// Status returns the status of the last N blocks,
-// - the number of active validators,
-// - the number of validators,
-// - the percentage of in-turn blocks
-func (api *API) Status() (*status, error) {
-	var (
-		numBlocks = uint64(64)
-		header    = api.chain.CurrentHeader()
-		diff      = uint64(0)
-		optimals  = 0
-	)
-	snap, err := api.congress.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
+// recentValidator is a validator in the recent signing window of a snapshot.
+type recentValidator struct {
+	Validator common.Address `json:"validator"`
+	Signed    uint64         `json:"signed"`     // Block sealed by the validator
+	Sealable  uint64         `json:"sealableAt"` // First block the validator may seal again
+}
+
+type recents struct {
+	Number  uint64            `json:"number"`
+	Hash    common.Hash       `json:"hash"`
+	Limit   uint64            `json:"limit"`   // Number of blocks a validator has to wait between seals
+	Recents []recentValidator `json:"recents"` // Validators disallowed from sealing the next block
+}
+
+// GetRecents retrieves the recent validators at the specified block, which are
+// disallowed from sealing the next block until their signing window expires.
+func (api *API) GetRecents(number *rpc.BlockNumber) (*recents, error) {
+	snap, err := api.GetSnapshot(number)
 	if err != nil {
 		return nil, err
 	}
-	var (
-		validators = snap.validators()
-		end        = header.Number.Uint64()
-		start      = end - numBlocks
-	)
-	if numBlocks > end {
-		start = 1
-		numBlocks = end - start
-	}
-	signStatus := make(map[common.Address]int)
-	for _, s := range validators {
-		signStatus[s] = 0
+	limit := uint64(len(snap.Validators)/2 + 1)
+	result := &recents{
+		Number:  snap.Number,
+		Hash:    snap.Hash,
+		Limit:   limit,
+		Recents: make([]recentValidator, 0, len(snap.Recents)),
 	}
-	for n := start; n < end; n++ {
-		h := api.chain.GetHeaderByNumber(n)
-		if h == nil {
-			return nil, fmt.Errorf("missing block %d", n)
+	for signed, validator := range snap.Recents {
+		// Mirror verifySeal, a validator which sealed a block within the limit
+		// of the next one is rejected
+		if signed+limit > snap.Number+1 {
+			result.Recents = append(result.Recents, recentValidator{Validator: validator, Signed: signed, Sealable: signed + limit})
 		}
+	}
+	sort.Slice(result.Recents, func(i, j int) bool { return result.Recents[i].Signed < result.Recents[j].Signed })
+	return result, nil
+}
+
+// blacklistEntry is the blacklist membership of an address at a block.
+type blacklistEntry struct {
+	Number      uint64         `json:"number"`
+	Hash        common.Hash    `json:"hash"`
+	Address     common.Address `json:"address"`
+	Blacklisted bool           `json:"blacklisted"`
+	Direction   string         `json:"direction,omitempty"` // "from", "to" or "both"
+}
+
+// GetBlacklistEntry retrieves whether the address is blacklisted after the
+// specified block, and in which direction.
+func (api *API) GetBlacklistEntry(address common.Address, number *rpc.BlockNumber) (*blacklistEntry, error) {
+	var header *types.Header
+	if number == nil || *number == rpc.LatestBlockNumber {
+		header = api.chain.CurrentHeader()
+	} else {
+		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
+	}
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	lists, err := api.congress.getAddressLists(api.chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	entry := &blacklistEntry{Number: lists.Number, Hash: lists.Hash, Address: address}
+	if d, ok := lists.Blacks[address]; ok {
+		entry.Blacklisted, entry.Direction = true, d.String()
+	}
+	return entry, nil
+}
+
+// ScreenTransaction executes the transaction on top of the specified block, and
+// reports every blacklisted address it involves along with the check or event
+// check rule matching it.
+func (api *API) ScreenTransaction(args screenArgs, number *rpc.BlockNumber) (*screenResult, error) {
+	header, err := api.proposalHeader(number)
+	if err != nil {
+		return nil, err
+	}
+	return api.congress.screenTransaction(api.chain, header, &args)
+}
+
+// developerEntry reports whether an address is an allowed developer.
+type developerEntry struct {
+	Number       uint64         `json:"number"`
+	Hash         common.Hash    `json:"hash"`
+	Address      common.Address `json:"address"`
+	Developer    bool           `json:"developer"`    // Whether the address is on the developer allowlist
+	Verification bool           `json:"verification"` // Whether the developer verification is enabled
+	CanCreate    bool           `json:"canCreate"`    // Whether the address may create contracts in the next block
+}
+
+// IsDeveloper retrieves whether the address is on the developer allowlist after
+// the specified block, and whether it may create contracts.
+func (api *API) IsDeveloper(address common.Address, number *rpc.BlockNumber) (*developerEntry, error) {
+	header, err := api.proposalHeader(number)
+	if err != nil {
+		return nil, err
+	}
+	statedb, err := api.congress.proposalState(header)
+	if err != nil {
+		return nil, err
+	}
+	list := systemcontract.NewAddressListReader(statedb, api.congress.chainConfig, header.Number)
+	return &developerEntry{
+		Number:       header.Number.Uint64(),
+		Hash:         header.Hash(),
+		Address:      address,
+		Developer:    list.IsDeveloper(address),
+		Verification: api.congress.config.EnableDevVerification && list.DevVerifyEnabled(),
+		CanCreate:    api.congress.CanCreate(statedb, address, new(big.Int).Add(header.Number, common.Big1)),
+	}, nil
+}
+
+// GetDeployments lists the contracts the developer deployed in the specified
+// range of canonical blocks, which defaults to the whole chain. Contracts
+// created by other contracts are not listed.
+func (api *API) GetDeployments(developer common.Address, from *rpc.BlockNumber, to *rpc.BlockNumber) ([]*deployment, error) {
+	head := api.chain.CurrentHeader().Number.Uint64()
+	first, last := uint64(0), head
+	if from != nil && *from >= 0 {
+		first = uint64(from.Int64())
+	}
+	if to != nil && *to >= 0 && uint64(to.Int64()) < head {
+		last = uint64(to.Int64())
+	}
+	if first > last {
+		return []*deployment{}, nil
+	}
+	return api.congress.getDeployments(developer, first, last)
+}
+
+// proposalArgs is a system governance proposal to simulate.
+type proposalArgs struct {
+	Id     *hexutil.Big   `json:"id"`
+	Action hexutil.Uint64 `json:"action"` // 0 calls the target, 1 erases its code
+	From   common.Address `json:"from"`
+	To     common.Address `json:"to"`
+	Value  *hexutil.Big   `json:"value"`
+	Data   hexutil.Bytes  `json:"data"`
+}
+
+// SimulateProposal executes the proposal on top of the specified block without
+// committing anything, and reports its state changes, logs and revert reason.
+func (api *API) SimulateProposal(args proposalArgs, number *rpc.BlockNumber) (*proposalSimulation, error) {
+	header, err := api.proposalHeader(number)
+	if err != nil {
+		return nil, err
+	}
+	prop := &Proposal{
+		Id:     (*big.Int)(args.Id),
+		Action: new(big.Int).SetUint64(uint64(args.Action)),
+		From:   args.From,
+		To:     args.To,
+		Value:  (*big.Int)(args.Value),
+		Data:   args.Data,
+	}
+	if prop.Id == nil {
+		prop.Id = new(big.Int)
+	}
+	if prop.Value == nil {
+		prop.Value = new(big.Int)
+	}
+	return api.congress.simulateProposal(api.chain, header, prop)
+}
+
+// GetProposals lists the system governance proposals as of the specified block,
+// along with the receipts of the executed ones.
+func (api *API) GetProposals(number *rpc.BlockNumber) ([]*proposalInfo, error) {
+	header, err := api.proposalHeader(number)
+	if err != nil {
+		return nil, err
+	}
+	return api.congress.getProposals(api.chain, header)
+}
+
+// ExportSigningProtection returns the highest block signed by each validator
+// sealing on this node, to be imported on the host its key is moved to.
+func (api *API) ExportSigningProtection() (*signingInterchange, error) {
+	genesis := api.chain.GetHeaderByNumber(0)
+	if genesis == nil {
+		return nil, errUnknownBlock
+	}
+	return api.congress.exportSigned(genesis.Hash())
+}
+
+// ImportSigningProtection merges the highest blocks signed by validators on
+// another host, so that none of them signs a conflicting block here.
+func (api *API) ImportSigningProtection(interchange signingInterchange) error {
+	genesis := api.chain.GetHeaderByNumber(0)
+	if genesis == nil {
+		return errUnknownBlock
+	}
+	return api.congress.importSigned(genesis.Hash(), &interchange)
+}
+
+// proposalHeader retrieves the header of the specified block, or the head if
+// none is specified.
+func (api *API) proposalHeader(number *rpc.BlockNumber) (*types.Header, error) {
+	var header *types.Header
+	if number == nil || *number == rpc.LatestBlockNumber {
+		header = api.chain.CurrentHeader()
+	} else {
+		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
+	}
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	return header, nil
+}
+
+type status struct {
+	InturnPercent   float64                             `json:"inturnPercent"`
+	SigningStatus   map[common.Address]int              `json:"sealerActivity"`
+	NumBlocks       uint64                              `json:"numBlocks"`
+	Validators      map[common.Address]*validatorStatus `json:"validators"`
+	PunishThreshold uint64                              `json:"punishThreshold"` // Missed blocks after which a validator is jailed
+	RemoveThreshold uint64                              `json:"removeThreshold"` // Missed blocks after which a validator is removed
+}
+
+// Status returns the status of the last N blocks, 64 unless specified,
+// - the number of blocks sealed by each validator,
+// - the percentage of in-turn blocks,
+// - the in-turn slots each validator had and missed, and when it last sealed,
+// - the punish counter, status and jail state of each validator at the head.
+func (api *API) Status(blocks *hexutil.Uint64) (*status, error) {
+	numBlocks := uint64(defaultStatusBlocks)
+	if blocks != nil {
+		numBlocks = uint64(*blocks)
+	}
+	if numBlocks == 0 || numBlocks > maxStatusBlocks {
+		return nil, fmt.Errorf("number of blocks must be between 1 and %d", maxStatusBlocks)
+	}
+	header := api.chain.CurrentHeader()
+	validators, headers, err := api.congress.sealingWindow(api.chain, header, numBlocks)
+	if err != nil {
+		return nil, err
+	}
+	optimals := 0
+	for _, h := range headers {
 		if h.Difficulty.Cmp(diffInTurn) == 0 {
 			optimals++
 		}
-		diff += h.Difficulty.Uint64()
-		sealer, err := api.congress.Author(h)
-		if err != nil {
+	}
+	signStatus := make(map[common.Address]int)
+	for addr, v := range validators {
+		signStatus[addr] = int(v.Sealed)
+	}
+	result := &status{
+		SigningStatus: signStatus,
+		NumBlocks:     uint64(len(headers)),
+		Validators:    validators,
+	}
+	if len(headers) > 0 {
+		result.InturnPercent = float64(100*optimals) / float64(len(headers))
+	}
+	// Report the state of the validators in the system contracts, if any
+	if api.congress.stateFn == nil {
+		return result, nil
+	}
+	statedb, err := api.congress.proposalState(header)
+	if err != nil {
+		return nil, err
+	}
+	if result.PunishThreshold, result.RemoveThreshold, err = api.congress.punishThresholds(header, statedb); err != nil {
+		return nil, err
+	}
+	for addr, v := range validators {
+		if err := api.congress.fillContractStatus(header, statedb, addr, v); err != nil {
 			return nil, err
 		}
-		signStatus[sealer]++
 	}
-	return &status{
-		InturnPercent: float64(100*optimals) / float64(numBlocks),
-		SigningStatus: signStatus,
-		NumBlocks:     numBlocks,
-	}, nil
+	return result, nil
+}
+
+// chainHeadSubscriber is the chain the API notifies the missed blocks of.
+type chainHeadSubscriber interface {
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+}
+
+// MissedBlocks creates a subscription notified of every block sealed out of
+// turn, with the validator which missed it and its punish counter, so that the
+// validators can be alerted before they are jailed.
+func (api *API) MissedBlocks(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	chain, ok := api.chain.(chainHeadSubscriber)
+	if !ok {
+		return &rpc.Subscription{}, errors.New("chain head events unavailable")
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		heads := make(chan core.ChainHeadEvent, 16)
+		headSub := chain.SubscribeChainHeadEvent(heads)
+		defer headSub.Unsubscribe()
+
+		last := api.chain.CurrentHeader().Number.Uint64()
+		for {
+			select {
+			case ev := <-heads:
+				// Several blocks may be imported at once, notify all of them
+				header := ev.Block.Header()
+				var headers []*types.Header
+				for number := header.Number.Uint64(); header != nil && len(headers) < maxStatusBlocks; number-- {
+					headers = append(headers, header)
+					if number <= last+1 {
+						break
+					}
+					header = api.chain.GetHeader(header.ParentHash, number-1)
+				}
+				last = ev.Block.NumberU64()
+				for i := len(headers) - 1; i >= 0; i-- {
+					missed, err := api.congress.missedBlock(api.chain, headers[i])
+					if err != nil {
+						log.Warn("Failed to check missed block", "number", headers[i].Number, "err", err)
+						continue
+					}
+					if missed != nil {
+						notifier.Notify(rpcSub.ID, missed)
+					}
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			case <-headSub.Err():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
 }
@@ -0,0 +1,44 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package congress
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// outOfTurnDelay returns how long the validator waits, on top of the block
+// time, before sealing the block at the given height out of turn.
+//
+// With a backoff configured, the backup validators take turns in the in-turn
+// order from the in-turn validator on: each waits one step more than the one
+// before it, so that its block only competes with another one if that one
+// didn't reach it within a step, plus a random jitter. Since Kleros the backoff
+// is used by default with a step and a jitter of wiggleTime. Otherwise every
+// backup validator waits a random delay within a wiggle growing with the number
+// of validators, as in Clique. See params.CongressConfig for the formula.
+func outOfTurnDelay(config *params.CongressConfig, snap *Snapshot, number uint64, validator common.Address) time.Duration {
+	return drawOutOfTurnDelay(config, snap, number, validator, rand.Int63n)
+}
+
+// drawOutOfTurnDelay is outOfTurnDelay drawing the random part of the delay from
+// the given source.
+func drawOutOfTurnDelay(config *params.CongressConfig, snap *Snapshot, number uint64, validator common.Address, int63n func(int64) int64) time.Duration {
+	step := time.Duration(config.BackoffStep) * time.Millisecond
+	jitter := time.Duration(config.BackoffJitter) * time.Millisecond
+	if step == 0 {
+		if snap.Seed == (common.Hash{}) {
+			wiggle := time.Duration(len(snap.Validators)/2+1) * wiggleTime
+			return time.Duration(int63n(int64(wiggle)))
+		}
+		step, jitter = wiggleTime, wiggleTime
+	}
+	delay := time.Duration(snap.backupRank(number, validator)) * step
+	if jitter > 0 {
+		delay += time.Duration(int63n(int64(jitter)))
+	}
+	return delay
+}
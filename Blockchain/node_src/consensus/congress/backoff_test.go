@@ -0,0 +1,134 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package congress
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// simulateForks seals the given number of blocks with validators which are
+// each offline with the given probability at every height, and returns the
+// fraction of heights at which competing blocks were sealed: a validator seals
+// its block unless the first block of the height reached it before its delay
+// ran out. The block which ends up canonical is the in-turn one if it was
+// sealed, the first one otherwise. The validators going offline and the random
+// delays are drawn in the in-turn order from distinct sources seeded with seed,
+// so the rate is reproducible.
+func simulateForks(config *params.CongressConfig, validators int, latency time.Duration, offline float64, blocks uint64, seed int64) float64 {
+	snap := &Snapshot{
+		Validators: make(map[common.Address]struct{}),
+		Recents:    make(map[uint64]common.Address),
+	}
+	for i := 0; i < validators; i++ {
+		snap.Validators[common.Address{byte(i), byte(i >> 8), 0x1}] = struct{}{}
+	}
+	limit := uint64(validators/2 + 1)
+
+	var (
+		down  = rand.New(rand.NewSource(seed))
+		delay = rand.New(rand.NewSource(seed + 1))
+	)
+
+	var forks int
+	for number := uint64(1); number <= blocks; number++ {
+		if number >= limit {
+			delete(snap.Recents, number-limit)
+		}
+		recent := make(map[common.Address]bool)
+		for _, validator := range snap.Recents {
+			recent[validator] = true
+		}
+		order := snap.inturnOrder()
+		inturn := order[number%uint64(len(order))]
+
+		delays := make(map[common.Address]time.Duration)
+		for _, validator := range order {
+			if recent[validator] || down.Float64() < offline {
+				continue
+			}
+			if validator == inturn {
+				delays[validator] = 0
+			} else {
+				delays[validator] = drawOutOfTurnDelay(config, snap, number, validator, delay.Int63n)
+			}
+		}
+		if len(delays) == 0 {
+			continue
+		}
+		var (
+			first  common.Address
+			sealed = time.Duration(-1)
+		)
+		for validator, delay := range delays {
+			if sealed < 0 || delay < sealed {
+				first, sealed = validator, delay
+			}
+		}
+		var competing int
+		for _, delay := range delays {
+			if delay < sealed+latency {
+				competing++
+			}
+		}
+		if competing > 1 {
+			forks++
+		}
+		if _, ok := delays[inturn]; ok {
+			first = inturn
+		}
+		snap.Recents[number] = first
+	}
+	return float64(forks) / float64(blocks)
+}
+
+// Tests that backing off out-of-turn validators by their distance from the
+// in-turn one, with a jitter past the random wiggle of small validator sets and
+// a step past the jitter, seals fewer competing blocks than the wiggle does,
+// whatever the number of validators and the latency of the network.
+func TestOutOfTurnBackoff(t *testing.T) {
+	var (
+		wiggle  = &params.CongressConfig{Period: 1, Epoch: 200}
+		backoff = &params.CongressConfig{Period: 1, Epoch: 200, BackoffStep: 1500, BackoffJitter: 1000}
+	)
+	tests := []struct {
+		validators int
+		latency    time.Duration
+		wiggle     float64 // Expected fork rate with the random wiggle
+		backoff    float64 // Expected fork rate with the backoff
+	}{
+		{3, 50 * time.Millisecond, 0.056, 0.031},
+		{3, 200 * time.Millisecond, 0.183, 0.101},
+		{3, 500 * time.Millisecond, 0.378, 0.228},
+		{7, 50 * time.Millisecond, 0.059, 0.004},
+		{7, 200 * time.Millisecond, 0.239, 0.014},
+		{7, 500 * time.Millisecond, 0.504, 0.027},
+		{21, 50 * time.Millisecond, 0.075, 0.001},
+		{21, 200 * time.Millisecond, 0.277, 0.004},
+		{21, 500 * time.Millisecond, 0.541, 0.005},
+		{51, 50 * time.Millisecond, 0.075, 0.001},
+		{51, 200 * time.Millisecond, 0.257, 0.007},
+		{51, 500 * time.Millisecond, 0.551, 0.014},
+	}
+	for _, tt := range tests {
+		// Both configs draw from their own sources, seeded alike
+		seed := int64(tt.validators)
+		wiggleRate := simulateForks(wiggle, tt.validators, tt.latency, 0.2, 1000, seed)
+		backoffRate := simulateForks(backoff, tt.validators, tt.latency, 0.2, 1000, seed)
+
+		if math.Abs(wiggleRate-tt.wiggle) > 0.0005 {
+			t.Errorf("validators %d, latency %v: wiggle fork rate mismatch: have %.3f, want %.3f", tt.validators, tt.latency, wiggleRate, tt.wiggle)
+		}
+		if math.Abs(backoffRate-tt.backoff) > 0.0005 {
+			t.Errorf("validators %d, latency %v: backoff fork rate mismatch: have %.3f, want %.3f", tt.validators, tt.latency, backoffRate, tt.backoff)
+		}
+		if backoffRate >= wiggleRate {
+			t.Errorf("validators %d, latency %v: backoff fork rate %.3f, want below wiggle rate %.3f", tt.validators, tt.latency, backoffRate, wiggleRate)
+		}
+	}
+}
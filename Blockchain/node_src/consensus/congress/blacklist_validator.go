@@ -1,9 +1,12 @@
 package congress
 
 import (
+	"sort"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
 )
 
 type EventCheckRule struct {
@@ -11,12 +14,55 @@ type EventCheckRule struct {
 	Checks   map[int]common.AddressCheckType
 }
 
+var (
+	blacklistFromHitMeter = metrics.NewRegisteredMeter("congress/blacklist/hit/from", nil)
+	blacklistToHitMeter   = metrics.NewRegisteredMeter("congress/blacklist/hit/to", nil)
+	blacklistAnyHitMeter  = metrics.NewRegisteredMeter("congress/blacklist/hit/any", nil)
+)
+
+// markBlacklistHit counts an address denied by the blacklist.
+func markBlacklistHit(cType common.AddressCheckType) {
+	switch cType {
+	case common.CheckFrom:
+		blacklistFromHitMeter.Mark(1)
+	case common.CheckTo:
+		blacklistToHitMeter.Mark(1)
+	case common.CheckBothInAny:
+		blacklistAnyHitMeter.Mark(1)
+	}
+}
+
+// markRuleHit counts a log denied by the event check rule.
+func markRuleHit(sig common.Hash) {
+	metrics.GetOrRegisterMeter("congress/eventcheckrules/hit/"+sig.Hex(), nil).Mark(1)
+}
+
 type blacklistValidator struct {
 	blacks map[common.Address]blacklistDirection
 	rules  map[common.Hash]*EventCheckRule
 }
 
 func (b *blacklistValidator) IsAddressDenied(address common.Address, cType common.AddressCheckType) (hit bool) {
+	d, hit := b.matchAddress(address, cType)
+	if hit {
+		log.Trace("Hit blacklist", "addr", address.String(), "direction", d, "checkType", cType)
+		markBlacklistHit(cType)
+	}
+	return
+}
+
+func (b *blacklistValidator) IsLogDenied(evLog *types.Log) bool {
+	if matches := b.matchLog(evLog); len(matches) > 0 {
+		log.Trace("Hit event check rule", "sig", evLog.Topics[0], "addr", matches[0].address, "topic", matches[0].topic)
+		markRuleHit(evLog.Topics[0])
+		return true
+	}
+	return false
+}
+
+// matchAddress returns whether the address is denied for the check, and the
+// direction it is blacklisted in.
+func (b *blacklistValidator) matchAddress(address common.Address, cType common.AddressCheckType) (d blacklistDirection, hit bool) {
 	d, exist := b.blacks[address]
 	if exist {
 		switch cType {
@@ -32,28 +78,39 @@ func (b *blacklistValidator) IsAddressDenied(address common.Address, cType commo
 			hit = false
 		}
 	}
-	if hit {
-		log.Trace("Hit blacklist", "addr", address.String(), "direction", d, "checkType", cType)
-	}
-	return
+	return d, hit
 }
 
-func (b *blacklistValidator) IsLogDenied(evLog *types.Log) bool {
+// logMatch is a denied address found in a topic of a log.
+type logMatch struct {
+	topic     int
+	address   common.Address
+	check     common.AddressCheckType
+	direction blacklistDirection
+}
+
+// matchLog returns the denied addresses the event check rule of the log finds
+// in its topics, ordered by topic.
+func (b *blacklistValidator) matchLog(evLog *types.Log) []logMatch {
 	if nil == evLog || len(evLog.Topics) <= 1 {
-		return false
+		return nil
 	}
-	if rule, exist := b.rules[evLog.Topics[0]]; exist {
-		for idx, checkType := range rule.Checks {
-			// do a basic check
-			if idx >= len(evLog.Topics) {
-				log.Error("check index in rule out to range", "sig", rule.EventSig.String(), "checkIdx", idx, "topicsLen", len(evLog.Topics))
-				continue
-			}
-			addr := common.BytesToAddress(evLog.Topics[idx].Bytes())
-			if b.IsAddressDenied(addr, checkType) {
-				return true
-			}
+	rule, exist := b.rules[evLog.Topics[0]]
+	if !exist {
+		return nil
+	}
+	var matches []logMatch
+	for idx, checkType := range rule.Checks {
+		// do a basic check
+		if idx >= len(evLog.Topics) {
+			log.Error("check index in rule out to range", "sig", rule.EventSig.String(), "checkIdx", idx, "topicsLen", len(evLog.Topics))
+			continue
+		}
+		addr := common.BytesToAddress(evLog.Topics[idx].Bytes())
+		if d, hit := b.matchAddress(addr, checkType); hit {
+			matches = append(matches, logMatch{topic: idx, address: addr, check: checkType, direction: d})
 		}
 	}
-	return false
+	sort.Slice(matches, func(i, j int) bool { return matches[i].topic < matches[j].topic })
+	return matches
 }
@@ -5,14 +5,12 @@ package congress
 
 import (
 	"bytes"
-	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"math"
 	"math/big"
-	"math/rand"
 	"sort"
 	"sync"
 	"time"
@@ -42,14 +40,14 @@ import (
 )
 
 const (
-	checkpointInterval = 1024 // Number of blocks after which to save the vote snapshot to the database
+	CheckpointInterval = 1024 // Number of blocks after which to save the vote snapshot to the database
 	inmemorySnapshots  = 128  // Number of recent vote snapshots to keep in memory
 	inmemorySignatures = 4096 // Number of recent block signatures to keep in memory
 
 	wiggleTime    = 500 * time.Millisecond // Random delay (per validator) to allow concurrent validators
 	maxValidators = 10000                  // Max validators allowed to seal.
 
-	inmemoryBlacklist = 10000 // Number of recent blacklist snapshots to keep in memory
+	inmemoryAddressLists = 10000 // Number of recent address lists to keep in memory
 )
 
 type blacklistDirection uint
@@ -62,7 +60,8 @@ const (
 
 // Congress proof-of-stake-authority protocol constants.
 var (
-	epochLength = uint64(30000) // Default number of blocks after which to checkpoint and reset the pending votes
+	epochLength      = uint64(30000) // Default number of blocks after which to checkpoint and reset the pending votes
+	maxGovernedEpoch = epochLength   // Longest epoch system governance may set since Agora
 
 	extraVanity = 32                     // Fixed number of extra-data prefix bytes reserved for validator vanity
 	extraSeal   = crypto.SignatureLength // Fixed number of extra-data suffix bytes reserved for validator seal
@@ -130,11 +129,30 @@ var (
 	errInvalidCoinbase = errors.New("Invalid coin base")
 
 	errInvalidSysGovCount = errors.New("invalid system governance tx count")
+
+	// errInvalidEpochParams is returned if the parameters carried by a checkpoint
+	// header since Agora aren't the ones in effect after it.
+	errInvalidEpochParams = errors.New("invalid epoch parameters")
+
+	// errRemovedValidator is returned if a block is sealed by a validator removed
+	// through system governance.
+	errRemovedValidator = errors.New("validator removed by governance")
+
+	// errConflictingSeal is returned when sealing a block would sign another
+	// block than the one the validator signed at that height, or one below it.
+	errConflictingSeal = errors.New("refusing to sign conflicting block")
+
+	// errNoSigningProtection is returned when sealing a block without a signing
+	// protection database to record it in.
+	errNoSigningProtection = errors.New("signing protection database unavailable")
 )
 
 var (
 	getblacklistTimer = metrics.NewRegisteredTimer("congress/blacklist/get", nil)
 	getRulesTimer     = metrics.NewRegisteredTimer("congress/eventcheckrules/get", nil)
+
+	addressListsHitMeter  = metrics.NewRegisteredMeter("congress/addresslists/cache/hit", nil)
+	addressListsMissMeter = metrics.NewRegisteredMeter("congress/addresslists/cache/miss", nil)
 )
 
 // StateFn gets state by the state root hash.
@@ -179,10 +197,8 @@ type Congress struct {
 	recents    *lru.ARCCache // Snapshots for recent block to speed up reorgs
 	signatures *lru.ARCCache // Signatures of recent blocks to speed up mining
 
-	blacklists      *lru.Cache // blacklists caches recent blacklist to speed up transactions validation
-	blLock          sync.Mutex // Make sure only get blacklist once for each block
-	eventCheckRules *lru.Cache // eventCheckRules caches recent EventCheckRules to speed up log validation
-	rulesLock       sync.Mutex // Make sure only get eventCheckRules once for each block
+	addrLists     *lru.Cache // Blacklists and event check rules of recent blocks to speed up validation
+	addrListsLock sync.Mutex // Make sure only get the address lists once for each block
 
 	proposals map[common.Address]bool // Current list of proposals we are pushing
 
@@ -193,6 +209,9 @@ type Congress struct {
 	signTxFn  SignTxFn
 	lock      sync.RWMutex // Protects the validator fields
 
+	protection ethdb.KeyValueStore // Database of the highest blocks signed by the validators, outside the chain database
+	signedLock sync.Mutex          // Protects the highest blocks signed by the validators
+
 	stateFn StateFn // Function to get state by state root
 
 	abi map[string]abi.ABI // Interactive with system contracts
@@ -214,8 +233,7 @@ func New(chainConfig *params.ChainConfig, db ethdb.Database) *Congress {
 	// Allocate the snapshot caches and create the engine
 	recents, _ := lru.NewARC(inmemorySnapshots)
 	signatures, _ := lru.NewARC(inmemorySignatures)
-	blacklists, _ := lru.New(inmemoryBlacklist)
-	rules, _ := lru.New(inmemoryBlacklist)
+	addrLists, _ := lru.New(inmemoryAddressLists)
 
 	abi := systemcontract.GetInteractiveABI()
 
@@ -225,8 +243,7 @@ func New(chainConfig *params.ChainConfig, db ethdb.Database) *Congress {
 		db:              db,
 		recents:         recents,
 		signatures:      signatures,
-		blacklists:      blacklists,
-		eventCheckRules: rules,
+		addrLists:       addrLists,
 		proposals:       make(map[common.Address]bool),
 		abi:             abi,
 		signer:          types.LatestSignerForChainID(chainConfig.ChainID),
@@ -237,6 +254,16 @@ func (c *Congress) SetChain(chain consensus.ChainHeaderReader) {
 	c.chain = chain
 }
 
+// SetSigningProtection sets the database to record the highest blocks signed by
+// the local validators in. It must outlive the chain database, which may be
+// restored from a backup or resynced; without it, no block is signed.
+func (c *Congress) SetSigningProtection(db ethdb.KeyValueStore) {
+	c.signedLock.Lock()
+	defer c.signedLock.Unlock()
+
+	c.protection = db
+}
+
 // SetStateFn sets the function to get state.
 func (c *Congress) SetStateFn(fn StateFn) {
 	c.stateFn = fn
@@ -296,23 +323,6 @@ func (c *Congress) verifyHeader(chain consensus.ChainHeaderReader, header *types
 	if len(header.Extra) < extraVanity+extraSeal {
 		return errMissingSignature
 	}
-	// check extra data
-	isEpoch := number%c.config.Epoch == 0
-
-	// Ensure that the extra-data contains a validator list on checkpoint, but none otherwise
-	validatorsBytes := len(header.Extra) - extraVanity - extraSeal
-	if !isEpoch && validatorsBytes != 0 {
-		return errExtraValidators
-	}
-	// Ensure that the validator bytes length is valid
-	if isEpoch && validatorsBytes%common.AddressLength != 0 {
-		return errExtraValidators
-	}
-
-	// Ensure that the mix digest is zero as we don't have fork protection currently
-	if header.MixDigest != (common.Hash{}) {
-		return errInvalidMixDigest
-	}
 	// Ensure that the block doesn't contain any uncles which are meaningless in PoA
 	if header.UncleHash != uncleHash {
 		return errInvalidUncleHash
@@ -354,8 +364,34 @@ func (c *Congress) verifyCascadingFields(chain consensus.ChainHeaderReader, head
 	if parent == nil || parent.Number.Uint64() != number-1 || parent.Hash() != header.ParentHash {
 		return consensus.ErrUnknownAncestor
 	}
+	// The checkpoints and the block period depend on the parameters in effect,
+	// which can change through system governance since Agora
+	snap, err := c.snapshot(chain, number-1, header.ParentHash, parents)
+	if err != nil {
+		return err
+	}
+	isEpoch := number%snap.Epoch == 0
 
-	if parent.Time+c.config.Period > header.Time {
+	// Ensure that the extra-data contains a validator list on checkpoint, but none otherwise
+	validatorsBytes := len(header.Extra) - extraVanity - extraSeal
+	if !isEpoch && validatorsBytes != 0 {
+		return errExtraValidators
+	}
+	// Ensure that the validator bytes length is valid
+	if isEpoch && validatorsBytes%common.AddressLength != 0 {
+		return errExtraValidators
+	}
+	// Ensure that the mix digest is zero as we don't have fork protection currently,
+	// except for the parameters carried by the checkpoints since Agora
+	if isEpoch && chain.Config().IsAgora(header.Number) {
+		if _, _, err := decodeEpochParams(header.MixDigest); err != nil {
+			return err
+		}
+	} else if header.MixDigest != (common.Hash{}) {
+		return errInvalidMixDigest
+	}
+
+	if parent.Time+snap.Period > header.Time {
 		return ErrInvalidTimestamp
 	}
 
@@ -394,7 +430,7 @@ func (c *Congress) snapshot(chain consensus.ChainHeaderReader, number uint64, ha
 			break
 		}
 		// If an on-disk checkpoint snapshot can be found, use that
-		if number%checkpointInterval == 0 {
+		if number%CheckpointInterval == 0 {
 			if s, err := loadSnapshot(c.config, c.signatures, c.db, hash); err == nil {
 				log.Trace("Loaded voting snapshot from disk", "number", number, "hash", hash)
 				snap = s
@@ -405,16 +441,28 @@ func (c *Congress) snapshot(chain consensus.ChainHeaderReader, number uint64, ha
 		// at a checkpoint block without a parent (light client CHT), or we have piled
 		// up more headers than allowed to be reorged (chain reinit from a freezer),
 		// consider the checkpoint trusted and snapshot it.
-		if number == 0 || (number%c.config.Epoch == 0 && (len(headers) > params.FullImmutabilityThreshold || chain.GetHeaderByNumber(number-1) == nil)) {
+		// Since Agora the epoch length may have changed, so the checkpoints are
+		// told apart by their validator list instead.
+		maybeCheckpoint := number%c.config.Epoch == 0 || c.chainConfig.IsAgora(new(big.Int).SetUint64(number))
+		if number == 0 || (maybeCheckpoint && (len(headers) > params.FullImmutabilityThreshold || chain.GetHeaderByNumber(number-1) == nil)) {
 			checkpoint := chain.GetHeaderByNumber(number)
-			if checkpoint != nil {
+			if checkpoint != nil && (number == 0 || len(checkpoint.Extra) > extraVanity+extraSeal) {
 				hash := checkpoint.Hash()
 
-				validators := make([]common.Address, (len(checkpoint.Extra)-extraVanity-extraSeal)/common.AddressLength)
-				for i := 0; i < len(validators); i++ {
-					copy(validators[i][:], checkpoint.Extra[extraVanity+i*common.AddressLength:])
+				var err error
+				if snap, err = c.checkpointSnapshot(checkpoint); err != nil {
+					return nil, err
+				}
+
+				// The signer of a trusted checkpoint is the only recent validator
+				// known without its ancestors, keep it out of the next blocks
+				if number > 0 {
+					if signer, err := ecrecover(checkpoint, c.signatures); err == nil {
+						if _, ok := snap.Validators[signer]; ok {
+							snap.Recents[number] = signer
+						}
+					}
 				}
-				snap = newSnapshot(c.config, c.signatures, number, hash, validators)
 				if err := snap.store(c.db); err != nil {
 					return nil, err
 				}
@@ -452,7 +500,7 @@ func (c *Congress) snapshot(chain consensus.ChainHeaderReader, number uint64, ha
 	c.recents.Add(snap.Hash, snap)
 
 	// If we've generated a new checkpoint snapshot, save to disk
-	if snap.Number%checkpointInterval == 0 && len(headers) > 0 {
+	if snap.Number%CheckpointInterval == 0 && len(headers) > 0 {
 		if err = snap.store(c.db); err != nil {
 			return nil, err
 		}
@@ -461,6 +509,28 @@ func (c *Congress) snapshot(chain consensus.ChainHeaderReader, number uint64, ha
 	return snap, err
 }
 
+// checkpointSnapshot creates the snapshot of a trusted checkpoint from the
+// validators and, since Agora, the parameters carried by its header. Since
+// Kleros, its hash also seeds the in-turn order.
+func (c *Congress) checkpointSnapshot(checkpoint *types.Header) (*Snapshot, error) {
+	validators := make([]common.Address, (len(checkpoint.Extra)-extraVanity-extraSeal)/common.AddressLength)
+	for i := 0; i < len(validators); i++ {
+		copy(validators[i][:], checkpoint.Extra[extraVanity+i*common.AddressLength:])
+	}
+	snap := newSnapshot(c.config, c.signatures, checkpoint.Number.Uint64(), checkpoint.Hash(), validators)
+	if checkpoint.Number.Sign() > 0 && c.chainConfig.IsAgora(checkpoint.Number) {
+		period, epoch, err := decodeEpochParams(checkpoint.MixDigest)
+		if err != nil {
+			return nil, err
+		}
+		snap.Period, snap.Epoch = period, epoch
+	}
+	if c.chainConfig.IsKleros(checkpoint.Number) {
+		snap.Seed = checkpoint.Hash()
+	}
+	return snap, nil
+}
+
 // VerifyUncles implements consensus.Engine, always returning an error for any
 // uncles as this consensus mechanism doesn't permit uncles.
 func (c *Congress) VerifyUncles(chain consensus.ChainReader, block *types.Block) error {
@@ -550,7 +620,30 @@ func (c *Congress) Prepare(chain consensus.ChainHeaderReader, header *types.Head
 	}
 	header.Extra = header.Extra[:extraVanity]
 
-	if number%c.config.Epoch == 0 {
+	// Ensure the parent is known, its state is needed since Agora
+	parent := chain.GetHeader(header.ParentHash, number-1)
+	if parent == nil {
+		return consensus.ErrUnknownAncestor
+	}
+	// Mix digest is reserved for now, set to empty
+	header.MixDigest = common.Hash{}
+
+	isEpoch := number%snap.Epoch == 0
+	if c.chainConfig.IsAgora(header.Number) {
+		statedb, err := c.stateFn(parent.Root)
+		if err != nil {
+			return err
+		}
+		if isRemovedValidator(statedb, c.validator) {
+			return errRemovedValidator
+		}
+		// Checkpoints carry the parameters of the following blocks
+		if isEpoch {
+			period, epoch, _ := epochParams(header, statedb, snap)
+			header.MixDigest = encodeEpochParams(period, epoch)
+		}
+	}
+	if isEpoch {
 		newSortedValidators, err := c.getTopValidators(chain, header)
 		if err != nil {
 			return err
@@ -562,15 +655,8 @@ func (c *Congress) Prepare(chain consensus.ChainHeaderReader, header *types.Head
 	}
 	header.Extra = append(header.Extra, make([]byte, extraSeal)...)
 
-	// Mix digest is reserved for now, set to empty
-	header.MixDigest = common.Hash{}
-
 	// Ensure the timestamp has the correct delay
-	parent := chain.GetHeader(header.ParentHash, number-1)
-	if parent == nil {
-		return consensus.ErrUnknownAncestor
-	}
-	header.Time = parent.Time + c.config.Period
+	header.Time = parent.Time + snap.Period
 	if header.Time < uint64(time.Now().Unix()) {
 		header.Time = uint64(time.Now().Unix())
 	}
@@ -580,6 +666,17 @@ func (c *Congress) Prepare(chain consensus.ChainHeaderReader, header *types.Head
 // Finalize implements consensus.Engine, ensuring no uncles are set, nor block
 // rewards given.
 func (c *Congress) Finalize(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs *[]*types.Transaction, uncles []*types.Header, receipts *[]*types.Receipt, systemTxs []*types.Transaction) error {
+	return c.finalize(chain, header, state, txs, receipts, systemTxs, nil)
+}
+
+// TraceFinalize implements consensus.PoSA, running Finalize with the system
+// calls for rewards, punishments, epochs and proposals traced by the loggers
+// returned by tracerFn.
+func (c *Congress) TraceFinalize(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs *[]*types.Transaction, uncles []*types.Header, receipts *[]*types.Receipt, systemTxs []*types.Transaction, tracerFn consensus.SysCallTracerFn) error {
+	return c.finalize(chain, header, state, txs, receipts, systemTxs, tracerFn)
+}
+
+func (c *Congress) finalize(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs *[]*types.Transaction, receipts *[]*types.Receipt, systemTxs []*types.Transaction, tracerFn consensus.SysCallTracerFn) error {
 	// Initialize all system contracts at block 1.
 	if header.Number.Cmp(common.Big1) == 0 {
 		if err := c.initializeSystemContracts(chain, header, state); err != nil {
@@ -588,8 +685,16 @@ func (c *Congress) Finalize(chain consensus.ChainHeaderReader, header *types.Hea
 		}
 	}
 
+	snap, err := c.snapshot(chain, header.Number.Uint64()-1, header.ParentHash, nil)
+	if err != nil {
+		return err
+	}
+	if c.chainConfig.IsAgora(header.Number) && isRemovedValidator(state, header.Coinbase) {
+		return errRemovedValidator
+	}
+
 	if header.Difficulty.Cmp(diffInTurn) != 0 {
-		if err := c.tryPunishValidator(chain, header, state); err != nil {
+		if err := c.tryPunishValidator(chain, header, state, tracerFn); err != nil {
 			return err
 		}
 	}
@@ -665,15 +770,15 @@ func (c *Congress) Finalize(chain consensus.ChainHeaderReader, header *types.Hea
 	    log.Info("REQUIRED GAS INFO FOR TEST 2 >> " + string(out1))
 
 
-		if err := c.trySendBlockReward(chain, header, state,addr,gass); err != nil {
+		if err := c.trySendBlockReward(chain, header, state,addr,gass, tracerFn); err != nil {
 			//panic(err)
 			log.Info(err.Error())
 		}
 	}
 
 	// do epoch thing at the end, because it will update active validators
-	if header.Number.Uint64()%c.config.Epoch == 0 {
-		newValidators, err := c.doSomethingAtEpoch(chain, header, state)
+	if header.Number.Uint64()%snap.Epoch == 0 {
+		newValidators, err := c.doSomethingAtEpoch(chain, header, state, snap.Epoch, tracerFn)
 		if err != nil {
 			return err
 		}
@@ -687,6 +792,9 @@ func (c *Congress) Finalize(chain consensus.ChainHeaderReader, header *types.Hea
 		if !bytes.Equal(header.Extra[extraVanity:extraSuffix], validatorsBytes) {
 			return errInvalidExtraValidators
 		}
+		if c.chainConfig.IsAgora(header.Number) && applyEpochParams(header, state, snap) != header.MixDigest {
+			return errInvalidEpochParams
+		}
 	}
 
 	//handle system governance Proposal
@@ -709,7 +817,7 @@ func (c *Congress) Finalize(chain consensus.ChainHeaderReader, header *types.Hea
 			}
 			// execute the system governance Proposal
 			tx := systemTxs[int(i)]
-			receipt, err := c.replayProposal(chain, header, state, prop, len(*txs), tx)
+			receipt, err := c.replayProposal(chain, header, state, prop, len(*txs), tx, tracerFn)
 			if err != nil {
 				return err
 			}
@@ -720,7 +828,7 @@ func (c *Congress) Finalize(chain consensus.ChainHeaderReader, header *types.Hea
 		}
 		// Finish all proposal
 		for i := uint32(0); i < proposalCount; i++ {
-			err = c.finishProposalById(chain, header, state, pIds[i])
+			err = c.finishProposalById(chain, header, state, pIds[i], tracerFn)
 			if err != nil {
 				return err
 			}
@@ -751,7 +859,7 @@ func (c *Congress) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header
 
 	// punish validator if necessary
 	if header.Difficulty.Cmp(diffInTurn) != 0 {
-		if err := c.tryPunishValidator(chain, header, state); err != nil {
+		if err := c.tryPunishValidator(chain, header, state, nil); err != nil {
 			panic(err)
 		}
 	}
@@ -816,7 +924,7 @@ func (c *Congress) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header
 	    log.Info("REQUIRED TO ADDRESS FOR TEST >> " + string(out))
 	    log.Info("REQUIRED GAS INFO FOR TEST >> " + string(out1))
 
-		if err := c.trySendBlockReward(chain, header, state,addr,gass); err != nil {
+		if err := c.trySendBlockReward(chain, header, state,addr,gass, nil); err != nil {
 			//panic(err)
 			log.Info(err.Error())
 
@@ -824,11 +932,18 @@ func (c *Congress) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header
 	}
 
 	// do epoch thing at the end, because it will update active validators
-	if header.Number.Uint64()%c.config.Epoch == 0 {
-		if _, err := c.doSomethingAtEpoch(chain, header, state); err != nil {
+	snap, err := c.snapshot(chain, header.Number.Uint64()-1, header.ParentHash, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if header.Number.Uint64()%snap.Epoch == 0 {
+		if _, err := c.doSomethingAtEpoch(chain, header, state, snap.Epoch, nil); err != nil {
 			//panic(err)
 			log.Info(err.Error())
 		}
+		if c.chainConfig.IsAgora(header.Number) {
+			header.MixDigest = applyEpochParams(header, state, snap)
+		}
 	}
 
 	//handle system governance Proposal
@@ -862,7 +977,7 @@ func (c *Congress) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header
 		}
 		// Finish all proposal
 		for i := uint32(0); i < proposalCount; i++ {
-			err = c.finishProposalById(chain, header, state, pIds[i])
+			err = c.finishProposalById(chain, header, state, pIds[i], nil)
 			if err != nil {
 				return nil, nil, err
 			}
@@ -877,7 +992,7 @@ func (c *Congress) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header
 	return types.NewBlock(header, txs, nil, receipts, new(trie.Trie)), receipts, nil
 }
 
-func (c *Congress) trySendBlockReward(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, addr [] common.Address,gass [] uint64) error {
+func (c *Congress) trySendBlockReward(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, addr [] common.Address,gass [] uint64, tracerFn consensus.SysCallTracerFn) error {
 	fee := state.GetBalance(consensus.FeeRecoder)
 	if fee.Cmp(common.Big0) <= 0 {
 		return nil
@@ -905,31 +1020,23 @@ func (c *Congress) trySendBlockReward(chain consensus.ChainHeaderReader, header
 	nonce := state.GetNonce(header.Coinbase)
 	msg := vmcaller.NewLegacyMessage(header.Coinbase, systemcontract.GetValidatorAddr(header.Number, c.chainConfig), nonce, fee, math.MaxUint64, new(big.Int), data, true)
 
-	if _, err := vmcaller.ExecuteMsg(msg, state, header, newChainContext(chain, c), c.chainConfig); err != nil {
+	if _, err := vmcaller.ExecuteMsgWithConfig(msg, state, header, newChainContext(chain, c), c.chainConfig, sysCallConfig(tracerFn, consensus.SysCallReward)); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (c *Congress) tryPunishValidator(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB) error {
+func (c *Congress) tryPunishValidator(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, tracerFn consensus.SysCallTracerFn) error {
 	number := header.Number.Uint64()
 	snap, err := c.snapshot(chain, number-1, header.ParentHash, nil)
 	if err != nil {
 		return err
 	}
-	validators := snap.validators()
-	outTurnValidator := validators[number%uint64(len(validators))]
-	// check sigend recently or not
-	signedRecently := false
-	for _, recent := range snap.Recents {
-		if recent == outTurnValidator {
-			signedRecently = true
-			break
-		}
-	}
-	if !signedRecently {
-		if err := c.punishValidator(outTurnValidator, chain, header, state); err != nil {
+	// validators which signed recently are not punished
+	outTurnValidator, punishable := inturnValidator(snap, number)
+	if punishable {
+		if err := c.punishValidator(outTurnValidator, chain, header, state, tracerFn); err != nil {
 			return err
 		}
 	}
@@ -937,18 +1044,18 @@ func (c *Congress) tryPunishValidator(chain consensus.ChainHeaderReader, header
 	return nil
 }
 
-func (c *Congress) doSomethingAtEpoch(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB) ([]common.Address, error) {
+func (c *Congress) doSomethingAtEpoch(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, epoch uint64, tracerFn consensus.SysCallTracerFn) ([]common.Address, error) {
 	newSortedValidators, err := c.getTopValidators(chain, header)
 	if err != nil {
 		return []common.Address{}, err
 	}
 
 	// update contract new validators if new set exists
-	if err := c.updateValidators(newSortedValidators, chain, header, state); err != nil {
+	if err := c.updateValidators(newSortedValidators, chain, header, state, epoch, tracerFn); err != nil {
 		return []common.Address{}, err
 	}
 	//  decrease validator missed blocks counter at epoch
-	if err := c.decreaseMissedBlocksCounter(chain, header, state); err != nil {
+	if err := c.decreaseMissedBlocksCounter(chain, header, state, epoch, tracerFn); err != nil {
 		return []common.Address{}, err
 	}
 
@@ -1037,13 +1144,29 @@ func (c *Congress) getTopValidators(chain consensus.ChainHeaderReader, header *t
 		return []common.Address{}, errors.New("Invalid validators format")
 	}
 	sort.Sort(validatorsAscending(validators))
+
+	// Leave out the validators removed through system governance, unless none
+	// would be left to seal
+	if c.chainConfig.IsAgora(header.Number) {
+		kept := make([]common.Address, 0, len(validators))
+		for _, validator := range validators {
+			if !isRemovedValidator(statedb, validator) {
+				kept = append(kept, validator)
+			}
+		}
+		if len(kept) == 0 {
+			log.Warn("All top validators removed by governance, keeping them", "number", header.Number)
+		} else {
+			validators = kept
+		}
+	}
 	return validators, err
 }
 
-func (c *Congress) updateValidators(vals []common.Address, chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB) error {
+func (c *Congress) updateValidators(vals []common.Address, chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, epoch uint64, tracerFn consensus.SysCallTracerFn) error {
 	// method
 	method := "updateActiveValidatorSet"
-	data, err := c.abi[systemcontract.ValidatorsContractName].Pack(method, vals, new(big.Int).SetUint64(c.config.Epoch))
+	data, err := c.abi[systemcontract.ValidatorsContractName].Pack(method, vals, new(big.Int).SetUint64(epoch))
 	if err != nil {
 		log.Error("Can't pack data for updateActiveValidatorSet", "error", err)
 		return err
@@ -1052,7 +1175,7 @@ func (c *Congress) updateValidators(vals []common.Address, chain consensus.Chain
 	// call contract
 	nonce := state.GetNonce(header.Coinbase)
 	msg := vmcaller.NewLegacyMessage(header.Coinbase, systemcontract.GetValidatorAddr(header.Number, c.chainConfig), nonce, new(big.Int), math.MaxUint64, new(big.Int), data, true)
-	if _, err := vmcaller.ExecuteMsg(msg, state, header, newChainContext(chain, c), c.chainConfig); err != nil {
+	if _, err := vmcaller.ExecuteMsgWithConfig(msg, state, header, newChainContext(chain, c), c.chainConfig, sysCallConfig(tracerFn, consensus.SysCallEpoch)); err != nil {
 		log.Error("Can't update validators to contract", "err", err)
 		return err
 	}
@@ -1060,7 +1183,7 @@ func (c *Congress) updateValidators(vals []common.Address, chain consensus.Chain
 	return nil
 }
 
-func (c *Congress) punishValidator(val common.Address, chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB) error {
+func (c *Congress) punishValidator(val common.Address, chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, tracerFn consensus.SysCallTracerFn) error {
 	// method
 	method := "punish"
 	data, err := c.abi[systemcontract.PunishContractName].Pack(method, val)
@@ -1072,7 +1195,7 @@ func (c *Congress) punishValidator(val common.Address, chain consensus.ChainHead
 	// call contract
 	nonce := state.GetNonce(header.Coinbase)
 	msg := vmcaller.NewLegacyMessage(header.Coinbase, systemcontract.GetPunishAddr(header.Number, c.chainConfig), nonce, new(big.Int), math.MaxUint64, new(big.Int), data, true)
-	if _, err := vmcaller.ExecuteMsg(msg, state, header, newChainContext(chain, c), c.chainConfig); err != nil {
+	if _, err := vmcaller.ExecuteMsgWithConfig(msg, state, header, newChainContext(chain, c), c.chainConfig, sysCallConfig(tracerFn, consensus.SysCallPunish)); err != nil {
 		log.Error("Can't punish validator", "err", err)
 		return err
 	}
@@ -1080,10 +1203,10 @@ func (c *Congress) punishValidator(val common.Address, chain consensus.ChainHead
 	return nil
 }
 
-func (c *Congress) decreaseMissedBlocksCounter(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB) error {
+func (c *Congress) decreaseMissedBlocksCounter(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, epoch uint64, tracerFn consensus.SysCallTracerFn) error {
 	// method
 	method := "decreaseMissedBlocksCounter"
-	data, err := c.abi[systemcontract.PunishContractName].Pack(method, new(big.Int).SetUint64(c.config.Epoch))
+	data, err := c.abi[systemcontract.PunishContractName].Pack(method, new(big.Int).SetUint64(epoch))
 	if err != nil {
 		log.Error("Can't pack data for decreaseMissedBlocksCounter", "error", err)
 		return err
@@ -1092,7 +1215,7 @@ func (c *Congress) decreaseMissedBlocksCounter(chain consensus.ChainHeaderReader
 	// call contract
 	nonce := state.GetNonce(header.Coinbase)
 	msg := vmcaller.NewLegacyMessage(header.Coinbase, systemcontract.GetPunishAddr(header.Number, c.chainConfig), nonce, new(big.Int), math.MaxUint64, new(big.Int), data, true)
-	if _, err := vmcaller.ExecuteMsg(msg, state, header, newChainContext(chain, c), c.chainConfig); err != nil {
+	if _, err := vmcaller.ExecuteMsgWithConfig(msg, state, header, newChainContext(chain, c), c.chainConfig, sysCallConfig(tracerFn, consensus.SysCallEpoch)); err != nil {
 		log.Error("Can't decrease missed blocks counter for validator", "err", err)
 		return err
 	}
@@ -1120,8 +1243,12 @@ func (c *Congress) Seal(chain consensus.ChainHeaderReader, block *types.Block, r
 	if number == 0 {
 		return errUnknownBlock
 	}
+	snap, err := c.snapshot(chain, number-1, header.ParentHash, nil)
+	if err != nil {
+		return err
+	}
 	// For 0-period chains, refuse to seal empty blocks (no reward but would spin sealing)
-	if c.config.Period == 0 && len(block.Transactions()) == 0 {
+	if snap.Period == 0 && len(block.Transactions()) == 0 {
 		log.Info("Sealing paused, waiting for transactions")
 		return nil
 	}
@@ -1131,10 +1258,6 @@ func (c *Congress) Seal(chain consensus.ChainHeaderReader, block *types.Block, r
 	c.lock.RUnlock()
 
 	// Bail out if we're unauthorized to sign a block
-	snap, err := c.snapshot(chain, number-1, header.ParentHash, nil)
-	if err != nil {
-		return err
-	}
 	if _, authorized := snap.Validators[val]; !authorized {
 		return errUnauthorizedValidator
 	}
@@ -1215,19 +1338,16 @@ func (c *Congress) Seal(chain consensus.ChainHeaderReader, block *types.Block, r
 	// Sweet, the protocol permits us to sign the block, wait for our time
 	delay := time.Unix(int64(header.Time), 0).Sub(time.Now()) // nolint: gosimple
 	if header.Difficulty.Cmp(diffNoTurn) == 0 {
-		// It's not our turn explicitly to sign, delay it a bit
-		wiggle := time.Duration(len(snap.Validators)/2+1) * wiggleTime
-		delay += time.Duration(rand.Int63n(int64(wiggle)))
+		// It's not our turn explicitly to sign, back off behind the validators
+		// before us
+		wiggle := outOfTurnDelay(c.config, snap, number, val)
+		delay += wiggle
 
 		log.Trace("Out-of-turn signing requested", "wiggle", common.PrettyDuration(wiggle))
 	}
-	// Sign all the things!
-	sighash, err := signFn(accounts.Account{Address: val}, accounts.MimetypeCongress, CongressRLP(header))
-	if err != nil {
-		return err
-	}
-	copy(header.Extra[len(header.Extra)-extraSeal:], sighash)
-	// Wait until sealing is terminated or delay timeout.
+	// Wait until sealing is terminated or delay timeout, and only sign the block
+	// then: the miner seals updated blocks at the same height meanwhile, while a
+	// validator must never sign two blocks at the same height.
 	log.Trace("Waiting for slot to sign and propagate", "delay", common.PrettyDuration(delay))
 	go func() {
 		select {
@@ -1235,6 +1355,22 @@ func (c *Congress) Seal(chain consensus.ChainHeaderReader, block *types.Block, r
 			return
 		case <-time.After(delay):
 		}
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		if err := c.protectSeal(val, number, SealHash(header)); err != nil {
+			log.Error("Refusing to sign block", "validator", val, "number", number, "sealhash", SealHash(header), "err", err)
+			return
+		}
+		// Sign all the things!
+		sighash, err := signFn(accounts.Account{Address: val}, accounts.MimetypeCongress, CongressRLP(header))
+		if err != nil {
+			log.Error("Failed to sign block", "number", number, "err", err)
+			return
+		}
+		copy(header.Extra[len(header.Extra)-extraSeal:], sighash)
 
 		select {
 		case results <- block.WithSeal(header):
@@ -1250,6 +1386,8 @@ func (c *Congress) Seal(chain consensus.ChainHeaderReader, block *types.Block, r
 // that a new block should have:
 // * DIFF_NOTURN(2) if BLOCK_NUMBER % validator_COUNT != validator_INDEX
 // * DIFF_INTURN(1) if BLOCK_NUMBER % validator_COUNT == validator_INDEX
+// where validator_INDEX is the index in the in-turn order of the epoch, which
+// is shuffled since Kleros.
 func (c *Congress) CalcDifficulty(chain consensus.ChainHeaderReader, time uint64, parent *types.Header) *big.Int {
 	snap, err := c.snapshot(chain, parent.Number.Uint64(), parent.Hash(), nil)
 	if err != nil {
@@ -1286,6 +1424,12 @@ func (c *Congress) APIs(chain consensus.ChainHeaderReader) []rpc.API {
 	}}
 }
 
+// HasValidators reports whether the header carries a validator list, which only
+// the epoch checkpoints and the genesis do.
+func HasValidators(header *types.Header) bool {
+	return len(header.Extra) > extraVanity+extraSeal
+}
+
 // SealHash returns the hash of a block prior to it being sealed.
 func SealHash(header *types.Header) (hash common.Hash) {
 	hasher := sha3.NewLegacyKeccak256()
@@ -1361,11 +1505,8 @@ func (c *Congress) IsSysTransaction(sender common.Address, tx *types.Transaction
 // it means that it's strongly relative to the layout of the Developers contract's state variables
 func (c *Congress) CanCreate(state consensus.StateReader, addr common.Address, height *big.Int) bool {
 	if c.chainConfig.IsRedCoast(height) && c.config.EnableDevVerification {
-		if isDeveloperVerificationEnabled(state) {
-			slot := calcSlotOfDevMappingKey(addr)
-			valueHash := state.GetState(systemcontract.AddressListContractAddr, slot)
-			// none zero value means true
-			return valueHash.Big().Sign() > 0
+		if list := systemcontract.NewAddressListReader(state, c.chainConfig, height); list.DevVerifyEnabled() {
+			return list.IsDeveloper(addr)
 		}
 	}
 	return true
@@ -1382,12 +1523,14 @@ func (c *Congress) ValidateTx(sender common.Address, tx *types.Transaction, head
 		}
 		if d, exist := m[sender]; exist && (d != DirectionTo) {
 			log.Trace("Hit blacklist", "tx", tx.Hash().String(), "addr", sender.String(), "direction", d)
-			return types.ErrAddressDenied
+			markBlacklistHit(common.CheckFrom)
+			return &types.DeniedAddressError{Address: sender, Check: common.CheckFrom}
 		}
 		if to := tx.To(); to != nil {
 			if d, exist := m[*to]; exist && (d != DirectionFrom) {
 				log.Trace("Hit blacklist", "tx", tx.Hash().String(), "addr", to.String(), "direction", d)
-				return types.ErrAddressDenied
+				markBlacklistHit(common.CheckTo)
+				return &types.DeniedAddressError{Address: *to, Check: common.CheckTo}
 			}
 		}
 	}
@@ -1399,35 +1542,15 @@ func (c *Congress) getBlacklist(header *types.Header, parentState *state.StateDB
 		getblacklistTimer.UpdateSince(start)
 	}(time.Now())
 
-	if v, ok := c.blacklists.Get(header.ParentHash); ok {
-		return v.(map[common.Address]blacklistDirection), nil
-	}
-
-	c.blLock.Lock()
-	defer c.blLock.Unlock()
-	if v, ok := c.blacklists.Get(header.ParentHash); ok {
-		return v.(map[common.Address]blacklistDirection), nil
-	}
-
-	// if the last updates is long ago, we don't need to get blacklist from the contract.
-	if c.chainConfig.SophonBlock != nil && header.Number.Cmp(c.chainConfig.SophonBlock) > 0 {
-		num := header.Number.Uint64()
-		lastUpdated := lastBlacklistUpdatedNumber(parentState)
-		if num >= 2 && num > lastUpdated+1 {
-			parent := c.chain.GetHeader(header.ParentHash, num-1)
-			if parent != nil {
-				if v, ok := c.blacklists.Get(parent.ParentHash); ok {
-					m := v.(map[common.Address]blacklistDirection)
-					c.blacklists.Add(header.ParentHash, m)
-					return m, nil
-				}
-			} else {
-				log.Error("Unexpected error when getBlacklist, can not get parent from chain", "number", num, "blockHash", header.Hash(), "parentHash", header.ParentHash)
-			}
-		}
+	lists, err := c.getAddressLists(c.chain, header.Number.Uint64()-1, header.ParentHash, parentState)
+	if err != nil {
+		return nil, err
 	}
+	return lists.Blacks, nil
+}
 
-	// can't get blacklist from cache, try to call the contract
+// loadBlacklist reads the blacklist from the address list contract, bypassing the cache.
+func (c *Congress) loadBlacklist(header *types.Header, parentState *state.StateDB) (map[common.Address]blacklistDirection, error) {
 	alABI := c.abi[systemcontract.AddressListContractName]
 	get := func(method string) ([]common.Address, error) {
 		ret, err := c.commonCallContract(header, parentState, alABI, systemcontract.AddressListContractAddr, method, 1)
@@ -1462,7 +1585,6 @@ func (c *Congress) getBlacklist(header *types.Header, parentState *state.StateDB
 			m[to] = DirectionTo
 		}
 	}
-	c.blacklists.Add(header.ParentHash, m)
 	return m, nil
 }
 
@@ -1491,33 +1613,16 @@ func (c *Congress) getEventCheckRules(header *types.Header, parentState *state.S
 		getRulesTimer.UpdateSince(start)
 	}(time.Now())
 
-	if v, ok := c.eventCheckRules.Get(header.ParentHash); ok {
-		return v.(map[common.Hash]*EventCheckRule), nil
-	}
-
-	c.rulesLock.Lock()
-	defer c.rulesLock.Unlock()
-	if v, ok := c.eventCheckRules.Get(header.ParentHash); ok {
-		return v.(map[common.Hash]*EventCheckRule), nil
+	lists, err := c.getAddressLists(c.chain, header.Number.Uint64()-1, header.ParentHash, parentState)
+	if err != nil {
+		return nil, err
 	}
+	return lists.Rules, nil
+}
 
-	// if the last updates is long ago, we don't need to get blacklist from the contract.
+// loadEventCheckRules reads the event check rules from the address list contract, bypassing the cache.
+func (c *Congress) loadEventCheckRules(header *types.Header, parentState *state.StateDB) (map[common.Hash]*EventCheckRule, error) {
 	num := header.Number.Uint64()
-	lastUpdated := lastRulesUpdatedNumber(parentState)
-	if num >= 2 && num > lastUpdated+1 {
-		parent := c.chain.GetHeader(header.ParentHash, num-1)
-		if parent != nil {
-			if v, ok := c.eventCheckRules.Get(parent.ParentHash); ok {
-				m := v.(map[common.Hash]*EventCheckRule)
-				c.eventCheckRules.Add(header.ParentHash, m)
-				return m, nil
-			}
-		} else {
-			log.Error("Unexpected error when getEventCheckRules, can not get parent from chain", "number", num, "blockHash", header.Hash(), "parentHash", header.ParentHash)
-		}
-	}
-
-	// can't get blacklist from cache, try to call the contract
 	alABI := c.abi[systemcontract.AddressListContractName]
 	method := "getRuleByIndex"
 	get := func(i uint32) (common.Hash, int, common.AddressCheckType, error) {
@@ -1554,8 +1659,6 @@ func (c *Congress) getEventCheckRules(header *types.Header, parentState *state.S
 		}
 		rule.Checks[idx] = ct
 	}
-
-	c.eventCheckRules.Add(header.ParentHash, rules)
 	return rules, nil
 }
 
@@ -1596,37 +1699,3 @@ func (c *Congress) commonCallContract(header *types.Header, statedb *state.State
 	}
 	return ret, nil
 }
-
-// Since the state variables are as follow:
-//    bool public initialized;
-//    bool public enabled;
-//    address public admin;
-//    address public pendingAdmin;
-//    mapping(address => bool) private devs;
-// according to [Layout of State Variables in Storage](https://docs.soliditylang.org/en/v0.8.4/internals/layout_in_storage.html),
-// and after optimizer enabled, the `initialized`, `enabled` and `admin` will be packed, and stores at slot 0,
-// `pendingAdmin` stores at slot 1, and the position for `devs` is 2.
-func isDeveloperVerificationEnabled(state consensus.StateReader) bool {
-	compactValue := state.GetState(systemcontract.AddressListContractAddr, common.Hash{})
-	// Layout of slot 0:
-	// [0   -    9][10-29][  30   ][    31     ]
-	// [zero bytes][admin][enabled][initialized]
-	enabledByte := compactValue.Bytes()[common.HashLength-2]
-	return enabledByte == 0x01
-}
-
-func calcSlotOfDevMappingKey(addr common.Address) common.Hash {
-	p := make([]byte, common.HashLength)
-	binary.BigEndian.PutUint16(p[common.HashLength-2:], uint16(systemcontract.DevMappingPosition))
-	return crypto.Keccak256Hash(addr.Hash().Bytes(), p)
-}
-
-func lastBlacklistUpdatedNumber(state consensus.StateReader) uint64 {
-	value := state.GetState(systemcontract.AddressListContractAddr, systemcontract.BlackLastUpdatedNumberPosition)
-	return value.Big().Uint64()
-}
-
-func lastRulesUpdatedNumber(state consensus.StateReader) uint64 {
-	value := state.GetState(systemcontract.AddressListContractAddr, systemcontract.RulesLastUpdatedNumberPosition)
-	return value.Big().Uint64()
-}
@@ -90,7 +90,7 @@ func (c *Congress) getPassedProposalByIndex(chain consensus.ChainHeaderReader, h
 }
 
 //finishProposalById
-func (c *Congress) finishProposalById(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, id *big.Int) error {
+func (c *Congress) finishProposalById(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, id *big.Int, tracerFn consensus.SysCallTracerFn) error {
 	method := "finishProposalById"
 	data, err := c.abi[systemcontract.SysGovContractName].Pack(method, id)
 	if err != nil {
@@ -102,7 +102,7 @@ func (c *Congress) finishProposalById(chain consensus.ChainHeaderReader, header
 
 	// execute message without a transaction
 	state.Prepare(common.Hash{}, 0)
-	_, err = vmcaller.ExecuteMsg(msg, state, header, newChainContext(chain, c), c.chainConfig)
+	_, err = vmcaller.ExecuteMsgWithConfig(msg, state, header, newChainContext(chain, c), c.chainConfig, sysCallConfig(tracerFn, consensus.SysCallProposal))
 	if err != nil {
 		return err
 	}
@@ -135,12 +135,15 @@ func (c *Congress) executeProposal(chain consensus.ChainHeaderReader, header *ty
 	}
 	//add nonce for validator
 	state.SetNonce(c.validator, nonce+1)
-	receipt := c.executeProposalMsg(chain, header, state, prop, totalTxIndex, tx.Hash(), common.Hash{})
+	receipt := c.executeProposalMsg(chain, header, state, prop, totalTxIndex, tx.Hash(), common.Hash{}, nil)
+	if err := writeProposalTx(c.db, prop.Id, tx.Hash()); err != nil {
+		log.Warn("Failed to index system governance transaction", "id", prop.Id, "err", err)
+	}
 
 	return tx, receipt, nil
 }
 
-func (c *Congress) replayProposal(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, prop *Proposal, totalTxIndex int, tx *types.Transaction) (*types.Receipt, error) {
+func (c *Congress) replayProposal(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, prop *Proposal, totalTxIndex int, tx *types.Transaction, tracerFn consensus.SysCallTracerFn) (*types.Receipt, error) {
 	sender, err := types.Sender(c.signer, tx)
 	if err != nil {
 		return nil, err
@@ -159,19 +162,22 @@ func (c *Congress) replayProposal(chain consensus.ChainHeaderReader, header *typ
 	nonce := state.GetNonce(sender)
 	//add nonce for validator
 	state.SetNonce(sender, nonce+1)
-	receipt := c.executeProposalMsg(chain, header, state, prop, totalTxIndex, tx.Hash(), header.Hash())
+	receipt := c.executeProposalMsg(chain, header, state, prop, totalTxIndex, tx.Hash(), header.Hash(), tracerFn)
+	if err := writeProposalTx(c.db, prop.Id, tx.Hash()); err != nil {
+		log.Warn("Failed to index system governance transaction", "id", prop.Id, "err", err)
+	}
 
 	return receipt, nil
 }
 
-func (c *Congress) executeProposalMsg(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, prop *Proposal, totalTxIndex int, txHash, bHash common.Hash) *types.Receipt {
+func (c *Congress) executeProposalMsg(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, prop *Proposal, totalTxIndex int, txHash, bHash common.Hash, tracerFn consensus.SysCallTracerFn) *types.Receipt {
 	var receipt *types.Receipt
 	action := prop.Action.Uint64()
-	switch action {
-	case 0:
+	switch {
+	case action == ActionEvmCall:
 		// evm action.
-		receipt = c.executeEvmCallProposal(chain, header, state, prop, totalTxIndex, txHash, bHash)
-	case 1:
+		receipt = c.executeEvmCallProposal(chain, header, state, prop, totalTxIndex, txHash, bHash, tracerFn)
+	case action == ActionErase:
 		// delete code action
 		ok := state.Erase(prop.To)
 		receipt = &types.Receipt{
@@ -184,6 +190,20 @@ func (c *Congress) executeProposalMsg(chain consensus.ChainHeaderReader, header
 			receipt.Status = types.ReceiptStatusSuccessful
 		}
 		log.Info("executeProposalMsg", "action", "erase", "id", prop.Id.String(), "to", prop.To, "txHash", txHash.String(), "success", ok)
+	case c.chainConfig.IsAgora(header.Number) && action <= ActionRemoveValidator:
+		state.Prepare(txHash, totalTxIndex)
+		err := c.execGovAction(chain, header.Number, header.ParentHash, state, prop)
+		receipt = &types.Receipt{
+			Type:              types.LegacyTxType,
+			PostState:         []byte{},
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: header.GasUsed,
+		}
+		if err != nil {
+			receipt.Status = types.ReceiptStatusFailed
+		}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+		log.Info("executeProposalMsg", "action", action, "id", prop.Id.String(), "to", prop.To, "data", hexutil.Encode(prop.Data), "txHash", txHash.String(), "err", err)
 	default:
 		receipt = &types.Receipt{
 			Type:              types.LegacyTxType,
@@ -202,11 +222,11 @@ func (c *Congress) executeProposalMsg(chain consensus.ChainHeaderReader, header
 	return receipt
 }
 
-func (c *Congress) executeEvmCallProposal(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, prop *Proposal, totalTxIndex int, txHash, bHash common.Hash) *types.Receipt {
+func (c *Congress) executeEvmCallProposal(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, prop *Proposal, totalTxIndex int, txHash, bHash common.Hash, tracerFn consensus.SysCallTracerFn) *types.Receipt {
 	// actually run the governance message
 	msg := vmcaller.NewLegacyMessage(prop.From, &prop.To, 0, prop.Value, header.GasLimit, new(big.Int), prop.Data, false)
 	state.Prepare(txHash, totalTxIndex)
-	_, err := vmcaller.ExecuteMsg(msg, state, header, newChainContext(chain, c), c.chainConfig)
+	_, err := vmcaller.ExecuteMsgWithConfig(msg, state, header, newChainContext(chain, c), c.chainConfig, sysCallConfig(tracerFn, consensus.SysCallProposal))
 
 	// governance message will not actually consumes gas
 	receipt := &types.Receipt{
@@ -227,8 +247,36 @@ func (c *Congress) executeEvmCallProposal(chain consensus.ChainHeaderReader, hea
 	return receipt
 }
 
+// execGovAction applies the governance action of the proposal in the block with
+// the given number and parent, resolving the validator set sealing the block
+// for the actions bound by it.
+func (c *Congress) execGovAction(chain consensus.ChainHeaderReader, number *big.Int, parent common.Hash, state *state.StateDB, prop *Proposal) error {
+	var validators int
+	if prop.Action.Uint64() == ActionSetParams {
+		snap, err := c.snapshot(chain, number.Uint64()-1, parent, nil)
+		if err != nil {
+			return err
+		}
+		validators = len(snap.Validators)
+	}
+	return applyGovAction(number, state, prop, validators)
+}
+
 // Methods for debug trace
 
+// sysCallConfig returns the evm config for executing a system call of the given
+// kind, with the tracer returned by tracerFn attached if any.
+func sysCallConfig(tracerFn consensus.SysCallTracerFn, kind string) vm.Config {
+	if tracerFn == nil {
+		return vm.Config{}
+	}
+	tracer := tracerFn(kind)
+	if tracer == nil {
+		return vm.Config{}
+	}
+	return vm.Config{Debug: true, Tracer: tracer}
+}
+
 // ApplySysTx applies a system-transaction using a given evm,
 func (c *Congress) ApplySysTx(evm *vm.EVM, state *state.StateDB, txIndex int, sender common.Address, tx *types.Transaction) (ret []byte, vmerr error, err error) {
 	var prop = &Proposal{}
@@ -241,23 +289,31 @@ func (c *Congress) ApplySysTx(evm *vm.EVM, state *state.StateDB, txIndex int, se
 	evm.StateDB.SetNonce(sender, nonce+1)
 
 	action := prop.Action.Uint64()
-	switch action {
-	case 0:
+	switch {
+	case action == ActionEvmCall:
 		// evm action.
 		// actually run the governance message
 		msg := vmcaller.NewLegacyMessage(prop.From, &prop.To, 0, prop.Value, tx.Gas(), new(big.Int), prop.Data, false)
 		state.Prepare(tx.Hash(), txIndex)
+		// Set up the access list as executing the proposal message does
+		if rules := evm.ChainConfig().Rules(evm.Context.BlockNumber); rules.IsBerlin {
+			state.PrepareAccessList(msg.From(), msg.To(), vm.ActivePrecompiles(rules), nil)
+		}
 		evm.TxContext = vm.TxContext{
 			Origin:   msg.From(),
 			GasPrice: new(big.Int).Set(msg.GasPrice()),
 		}
 		ret, _, vmerr = evm.Call(vm.AccountRef(msg.From()), *msg.To(), msg.Data(), msg.Gas(), msg.Value())
 		state.Finalise(true)
-	case 1:
+	case action == ActionErase:
 		// delete code action
 		_ = state.Erase(prop.To)
+	case c.chainConfig.IsAgora(evm.Context.BlockNumber) && action <= ActionRemoveValidator:
+		state.Prepare(tx.Hash(), txIndex)
+		parent := evm.Context.GetHash(evm.Context.BlockNumber.Uint64() - 1)
+		vmerr = c.execGovAction(c.chain, evm.Context.BlockNumber, parent, state, prop)
 	default:
-		vmerr = errors.New("unsupported action")
+		vmerr = errUnsupportedAction
 	}
 	return
 }
@@ -0,0 +1,32 @@
+package congress
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+func TestSysCallConfig(t *testing.T) {
+	if cfg := sysCallConfig(nil, consensus.SysCallReward); cfg.Debug || cfg.Tracer != nil {
+		t.Fatalf("untraced call has tracing enabled: %+v", cfg)
+	}
+	var kinds []string
+	tracer := vm.NewStructLogger(nil)
+	tracerFn := func(kind string) vm.EVMLogger {
+		kinds = append(kinds, kind)
+		if kind == consensus.SysCallPunish {
+			return nil
+		}
+		return tracer
+	}
+	if cfg := sysCallConfig(tracerFn, consensus.SysCallReward); !cfg.Debug || cfg.Tracer != tracer {
+		t.Fatalf("traced call has tracing disabled: %+v", cfg)
+	}
+	if cfg := sysCallConfig(tracerFn, consensus.SysCallPunish); cfg.Debug || cfg.Tracer != nil {
+		t.Fatalf("skipped call has tracing enabled: %+v", cfg)
+	}
+	if len(kinds) != 2 || kinds[0] != consensus.SysCallReward || kinds[1] != consensus.SysCallPunish {
+		t.Fatalf("tracer requested for wrong kinds: %v", kinds)
+	}
+}
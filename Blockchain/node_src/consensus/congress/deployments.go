@@ -0,0 +1,120 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package congress
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// deploymentPrefix is the database prefix of the index of the contracts each
+// developer deployed, keyed by developer, block number and transaction index.
+var deploymentPrefix = []byte("congress-deployment-")
+
+// deploymentKey returns the database key of the contract deployed by the
+// transaction at the given position.
+func deploymentKey(developer common.Address, number uint64, index uint32) []byte {
+	key := make([]byte, 0, len(deploymentPrefix)+common.AddressLength+8+4)
+	key = append(append(key, deploymentPrefix...), developer.Bytes()...)
+	key = binary.BigEndian.AppendUint64(key, number)
+	return binary.BigEndian.AppendUint32(key, index)
+}
+
+// deploymentEntry is the indexed deployment of a contract.
+type deploymentEntry struct {
+	Contract common.Address
+	TxHash   common.Hash
+}
+
+// deployment is a contract deployed by a developer, as reported by the API.
+type deployment struct {
+	Contract    common.Address `json:"contract"`
+	TxHash      common.Hash    `json:"transactionHash"`
+	TxIndex     hexutil.Uint   `json:"transactionIndex"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+}
+
+// IndexBlock implements consensus.BlockIndexer, recording the contracts
+// successfully deployed by the transactions of the block. Only contracts created
+// by the transactions themselves are indexed, the ones created by other
+// contracts are not.
+//
+// The blocks are indexed as they are written, whether or not they are canonical,
+// so the entries are checked against the canonical chain when read.
+func (c *Congress) IndexBlock(db ethdb.KeyValueWriter, block *types.Block, receipts []*types.Receipt) {
+	signer := types.MakeSigner(c.chainConfig, block.Number())
+	for i, tx := range block.Transactions() {
+		if tx.To() != nil || i >= len(receipts) || receipts[i].Status != types.ReceiptStatusSuccessful {
+			continue
+		}
+		developer, err := types.Sender(signer, tx)
+		if err != nil {
+			continue
+		}
+		blob, err := rlp.EncodeToBytes(&deploymentEntry{Contract: receipts[i].ContractAddress, TxHash: tx.Hash()})
+		if err != nil {
+			continue
+		}
+		if err := db.Put(deploymentKey(developer, block.NumberU64(), uint32(i)), blob); err != nil {
+			log.Warn("Failed to index contract deployment", "number", block.Number(), "index", i, "err", err)
+		}
+	}
+}
+
+// getDeployments retrieves the contracts deployed by the developer in the
+// canonical blocks of the given range.
+func (c *Congress) getDeployments(developer common.Address, from, to uint64) ([]*deployment, error) {
+	var (
+		prefix = append(append([]byte{}, deploymentPrefix...), developer.Bytes()...)
+		start  = binary.BigEndian.AppendUint64(nil, from)
+		it     = c.db.NewIterator(prefix, start)
+
+		deployments = []*deployment{}
+		bodyHash    common.Hash
+		body        *types.Body
+	)
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()[len(prefix):]
+		if len(key) != 12 {
+			continue
+		}
+		number, index := binary.BigEndian.Uint64(key[:8]), binary.BigEndian.Uint32(key[8:])
+		if number > to {
+			break
+		}
+		var entry deploymentEntry
+		if err := rlp.DecodeBytes(it.Value(), &entry); err != nil {
+			log.Warn("Invalid contract deployment entry", "developer", developer, "number", number, "err", err)
+			continue
+		}
+		// Skip the deployments of blocks which didn't make it to the chain
+		hash := rawdb.ReadCanonicalHash(c.db, number)
+		if hash == (common.Hash{}) {
+			continue
+		}
+		if hash != bodyHash {
+			bodyHash, body = hash, rawdb.ReadBody(c.db, hash, number)
+		}
+		if body == nil || int(index) >= len(body.Transactions) || body.Transactions[index].Hash() != entry.TxHash {
+			continue
+		}
+		deployments = append(deployments, &deployment{
+			Contract:    entry.Contract,
+			TxHash:      entry.TxHash,
+			TxIndex:     hexutil.Uint(index),
+			BlockNumber: hexutil.Uint64(number),
+			BlockHash:   hash,
+		})
+	}
+	return deployments, it.Error()
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package congress
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the contract deployments are indexed per developer, and that only
+// the successful ones of canonical blocks are reported.
+func TestDeploymentIndex(t *testing.T) {
+	var (
+		key, _    = crypto.GenerateKey()
+		developer = crypto.PubkeyToAddress(key.PublicKey)
+		config    = params.AllCongressProtocolChanges
+		signer    = types.MakeSigner(config, common.Big1)
+		db        = rawdb.NewMemoryDatabase()
+		engine    = New(config, db)
+	)
+	sign := func(nonce uint64, to *common.Address) *types.Transaction {
+		tx, err := types.SignTx(types.NewTx(&types.LegacyTx{Nonce: nonce, To: to, Gas: 100000, GasPrice: new(big.Int)}), signer, key)
+		if err != nil {
+			t.Fatalf("failed to sign transaction: %v", err)
+		}
+		return tx
+	}
+	// block writes a block with the given transactions, all creating contracts
+	// but the calls, and indexes its deployments
+	block := func(number int64, canonical bool, failed map[int]bool, txs ...*types.Transaction) *types.Header {
+		header := &types.Header{Number: big.NewInt(number), Extra: []byte{byte(len(txs))}}
+		receipts := make([]*types.Receipt, len(txs))
+		for i, tx := range txs {
+			receipts[i] = &types.Receipt{Status: types.ReceiptStatusSuccessful}
+			if failed[i] {
+				receipts[i].Status = types.ReceiptStatusFailed
+			}
+			if tx.To() == nil {
+				receipts[i].ContractAddress = crypto.CreateAddress(developer, tx.Nonce())
+			}
+		}
+		engine.IndexBlock(db, types.NewBlockWithHeader(header).WithBody(txs, nil), receipts)
+		rawdb.WriteBody(db, header.Hash(), header.Number.Uint64(), &types.Body{Transactions: txs})
+		if canonical {
+			rawdb.WriteCanonicalHash(db, header.Hash(), header.Number.Uint64())
+		}
+		return header
+	}
+	to := common.Address{0x1}
+	block(1, true, nil, sign(0, nil), sign(1, &to), sign(2, nil))
+	block(2, false, nil, sign(3, nil))
+	block(2, true, map[int]bool{1: true}, sign(3, &to), sign(4, nil))
+	block(3, true, nil, sign(5, nil))
+
+	tests := []struct {
+		from, to uint64
+		nonces   []uint64
+	}{
+		{0, 10, []uint64{0, 2, 5}},
+		{2, 2, nil},
+		{2, 3, []uint64{5}},
+		{0, 1, []uint64{0, 2}},
+	}
+	for i, tt := range tests {
+		deployments, err := engine.getDeployments(developer, tt.from, tt.to)
+		if err != nil {
+			t.Fatalf("test %d: failed to retrieve deployments: %v", i, err)
+		}
+		if len(deployments) != len(tt.nonces) {
+			t.Fatalf("test %d: deployments mismatch: have %d, want %d", i, len(deployments), len(tt.nonces))
+		}
+		for j, nonce := range tt.nonces {
+			if want := crypto.CreateAddress(developer, nonce); deployments[j].Contract != want {
+				t.Errorf("test %d, deployment %d: contract mismatch: have %x, want %x", i, j, deployments[j].Contract, want)
+			}
+		}
+	}
+	if deployments, _ := engine.getDeployments(common.Address{0x2}, 0, 10); len(deployments) != 0 {
+		t.Errorf("deployments of an unknown developer: %v", deployments)
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package congress
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/congress/systemcontract"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Actions of the system governance proposals. The actions following the erasure
+// are only supported since Agora.
+const (
+	ActionEvmCall         = 0 // Calls the target with the proposal data
+	ActionErase           = 1 // Erases the code and storage of the target
+	ActionSetCode         = 2 // Replaces the code of the target by the proposal data
+	ActionSetStorage      = 3 // Writes the 64 byte (slot, value) pairs of the proposal data to the target storage
+	ActionSetParams       = 4 // Schedules the RLP encoded ParamsChange of the proposal data
+	ActionRemoveValidator = 5 // Bars the target from sealing and from the validator set
+)
+
+var (
+	errUnsupportedAction   = errors.New("unsupported action")
+	errInvalidProposalData = errors.New("invalid proposal data")
+	errMissingContract     = errors.New("no contract at target")
+)
+
+// ParamsChange is the data of a proposal changing the block period and the
+// epoch length. The new parameters take effect after the first checkpoint at
+// or after Block.
+type ParamsChange struct {
+	Block  uint64
+	Period uint64
+	Epoch  uint64
+}
+
+// MaxEpochLength returns the longest distance between two checkpoints, which
+// is the configured epoch length, or since Agora the longest one governance may
+// set if it's longer.
+func MaxEpochLength(config *params.CongressConfig) uint64 {
+	if config.Epoch > maxGovernedEpoch {
+		return config.Epoch
+	}
+	return maxGovernedEpoch
+}
+
+// readParamsChange retrieves the scheduled parameters change, if any.
+func readParamsChange(statedb *state.StateDB) *ParamsChange {
+	slot := statedb.GetState(systemcontract.SysGovContractAddr, systemcontract.ParamsSchedulePosition)
+	if slot == (common.Hash{}) {
+		return nil
+	}
+	return &ParamsChange{
+		Block:  binary.BigEndian.Uint64(slot[:8]),
+		Period: binary.BigEndian.Uint64(slot[8:16]),
+		Epoch:  binary.BigEndian.Uint64(slot[16:24]),
+	}
+}
+
+// writeParamsChange schedules the parameters change, or clears the scheduled
+// one if nil.
+func writeParamsChange(statedb *state.StateDB, change *ParamsChange) {
+	var slot common.Hash
+	if change != nil {
+		binary.BigEndian.PutUint64(slot[:8], change.Block)
+		binary.BigEndian.PutUint64(slot[8:16], change.Period)
+		binary.BigEndian.PutUint64(slot[16:24], change.Epoch)
+	}
+	statedb.SetState(systemcontract.SysGovContractAddr, systemcontract.ParamsSchedulePosition, slot)
+}
+
+// encodeEpochParams packs the parameters in effect after a checkpoint into the
+// mix digest of its header.
+func encodeEpochParams(period, epoch uint64) common.Hash {
+	var digest common.Hash
+	binary.BigEndian.PutUint64(digest[:8], period)
+	binary.BigEndian.PutUint64(digest[8:16], epoch)
+	return digest
+}
+
+// decodeEpochParams unpacks the parameters carried by the mix digest of a
+// checkpoint header.
+func decodeEpochParams(digest common.Hash) (period uint64, epoch uint64, err error) {
+	period, epoch = binary.BigEndian.Uint64(digest[:8]), binary.BigEndian.Uint64(digest[8:16])
+	if epoch == 0 || common.BytesToHash(digest[16:]) != (common.Hash{}) {
+		return 0, 0, errInvalidEpochParams
+	}
+	return period, epoch, nil
+}
+
+// epochParams returns the parameters in effect after the checkpoint header on
+// top of the given snapshot, and whether they are scheduled ones taking effect
+// with it.
+func epochParams(header *types.Header, statedb *state.StateDB, snap *Snapshot) (period uint64, epoch uint64, scheduled bool) {
+	if change := readParamsChange(statedb); change != nil && change.Block <= header.Number.Uint64() {
+		return change.Period, change.Epoch, true
+	}
+	return snap.Period, snap.Epoch, false
+}
+
+// applyEpochParams returns the mix digest of the checkpoint header on top of
+// the given snapshot, consuming the scheduled parameters change taking effect.
+func applyEpochParams(header *types.Header, statedb *state.StateDB, snap *Snapshot) common.Hash {
+	period, epoch, scheduled := epochParams(header, statedb, snap)
+	if scheduled {
+		writeParamsChange(statedb, nil)
+	}
+	return encodeEpochParams(period, epoch)
+}
+
+// isRemovedValidator returns whether the validator was removed through system
+// governance.
+func isRemovedValidator(statedb *state.StateDB, validator common.Address) bool {
+	return statedb.GetState(systemcontract.SysGovContractAddr, systemcontract.RemovedValidatorPosition(validator)) != (common.Hash{})
+}
+
+// applyGovAction executes the actions introduced by Agora, which modify the
+// state directly instead of running any code. Nothing is modified if the
+// proposal is rejected. The number of validators sealing the block bounds the
+// epoch length, which must exceed the window of recent signers for the
+// checkpoints to be sealable, and must not exceed maxGovernedEpoch for light
+// clients to find them.
+func applyGovAction(number *big.Int, statedb *state.StateDB, prop *Proposal, validators int) error {
+	switch prop.Action.Uint64() {
+	case ActionSetCode:
+		if len(prop.Data) == 0 {
+			return errInvalidProposalData
+		}
+		statedb.SetCode(prop.To, prop.Data)
+
+	case ActionSetStorage:
+		if len(prop.Data) == 0 || len(prop.Data)%(2*common.HashLength) != 0 {
+			return errInvalidProposalData
+		}
+		// Accounts without code nor balance would be wiped along with their storage
+		if statedb.GetCodeSize(prop.To) == 0 {
+			return errMissingContract
+		}
+		for i := 0; i < len(prop.Data); i += 2 * common.HashLength {
+			statedb.SetState(prop.To, common.BytesToHash(prop.Data[i:i+common.HashLength]), common.BytesToHash(prop.Data[i+common.HashLength:i+2*common.HashLength]))
+		}
+
+	case ActionSetParams:
+		change := new(ParamsChange)
+		if err := rlp.DecodeBytes(prop.Data, change); err != nil {
+			return err
+		}
+		if change.Block <= number.Uint64() || change.Epoch <= uint64(validators/2+1) || change.Epoch > maxGovernedEpoch {
+			return errInvalidProposalData
+		}
+		writeParamsChange(statedb, change)
+
+	case ActionRemoveValidator:
+		if prop.To == (common.Address{}) {
+			return errInvalidProposalData
+		}
+		statedb.SetState(systemcontract.SysGovContractAddr, systemcontract.RemovedValidatorPosition(prop.To), common.BigToHash(common.Big1))
+
+	default:
+		return errUnsupportedAction
+	}
+	return nil
+}
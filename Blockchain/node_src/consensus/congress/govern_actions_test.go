@@ -0,0 +1,169 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package congress
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/congress/systemcontract"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Tests that the governance actions introduced by Agora modify the state as
+// proposed, and reject malformed proposals without touching it.
+func TestApplyGovAction(t *testing.T) {
+	var (
+		contract  = common.Address{0xc}
+		validator = common.Address{0x1}
+		number    = big.NewInt(100)
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetCode(contract, []byte{0x00})
+	statedb.SetCode(systemcontract.SysGovContractAddr, []byte{0x00})
+
+	apply := func(action uint64, to common.Address, data []byte) error {
+		return applyGovAction(number, statedb, &Proposal{Id: new(big.Int), Action: new(big.Int).SetUint64(action), To: to, Value: new(big.Int), Data: data}, 21)
+	}
+	// Replace the code of the contract
+	if err := apply(ActionSetCode, contract, []byte{0x60, 0x00}); err != nil {
+		t.Fatalf("failed to set code: %v", err)
+	}
+	if code := statedb.GetCode(contract); !bytes.Equal(code, []byte{0x60, 0x00}) {
+		t.Errorf("code mismatch: have %x", code)
+	}
+	if err := apply(ActionSetCode, contract, nil); err != errInvalidProposalData {
+		t.Errorf("empty code error mismatch: have %v, want %v", err, errInvalidProposalData)
+	}
+	// Write storage slots of the contract
+	slots := append(append(common.Hash{0x1}.Bytes(), common.Hash{0x2}.Bytes()...), append(common.Hash{0x3}.Bytes(), common.Hash{0x4}.Bytes()...)...)
+	if err := apply(ActionSetStorage, contract, slots); err != nil {
+		t.Fatalf("failed to set storage: %v", err)
+	}
+	if statedb.GetState(contract, common.Hash{0x1}) != (common.Hash{0x2}) || statedb.GetState(contract, common.Hash{0x3}) != (common.Hash{0x4}) {
+		t.Errorf("storage not written")
+	}
+	if err := apply(ActionSetStorage, contract, slots[:40]); err != errInvalidProposalData {
+		t.Errorf("truncated storage error mismatch: have %v, want %v", err, errInvalidProposalData)
+	}
+	if err := apply(ActionSetStorage, common.Address{0xd}, slots); err != errMissingContract {
+		t.Errorf("missing contract error mismatch: have %v, want %v", err, errMissingContract)
+	}
+	// Schedule new parameters, which must be in the future
+	past, _ := rlp.EncodeToBytes(&ParamsChange{Block: 100, Period: 5, Epoch: 50})
+	if err := apply(ActionSetParams, common.Address{}, past); err != errInvalidProposalData {
+		t.Errorf("past schedule error mismatch: have %v, want %v", err, errInvalidProposalData)
+	}
+	short, _ := rlp.EncodeToBytes(&ParamsChange{Block: 150, Period: 5, Epoch: 11})
+	if err := apply(ActionSetParams, common.Address{}, short); err != errInvalidProposalData {
+		t.Errorf("short epoch error mismatch: have %v, want %v", err, errInvalidProposalData)
+	}
+	long, _ := rlp.EncodeToBytes(&ParamsChange{Block: 150, Period: 5, Epoch: maxGovernedEpoch + 1})
+	if err := apply(ActionSetParams, common.Address{}, long); err != errInvalidProposalData {
+		t.Errorf("long epoch error mismatch: have %v, want %v", err, errInvalidProposalData)
+	}
+	future, _ := rlp.EncodeToBytes(&ParamsChange{Block: 150, Period: 5, Epoch: 50})
+	if err := apply(ActionSetParams, common.Address{}, future); err != nil {
+		t.Fatalf("failed to schedule parameters: %v", err)
+	}
+	if change := readParamsChange(statedb); change == nil || *change != (ParamsChange{Block: 150, Period: 5, Epoch: 50}) {
+		t.Errorf("scheduled parameters mismatch: have %+v", change)
+	}
+	// Remove a validator
+	if isRemovedValidator(statedb, validator) {
+		t.Errorf("validator removed before the proposal")
+	}
+	if err := apply(ActionRemoveValidator, validator, nil); err != nil {
+		t.Fatalf("failed to remove validator: %v", err)
+	}
+	if !isRemovedValidator(statedb, validator) || isRemovedValidator(statedb, contract) {
+		t.Errorf("removed validators mismatch")
+	}
+	if err := apply(6, contract, nil); err != errUnsupportedAction {
+		t.Errorf("unknown action error mismatch: have %v, want %v", err, errUnsupportedAction)
+	}
+}
+
+// Tests that the scheduled parameters take effect at the first checkpoint at or
+// after their block, and that checkpoints carry the parameters in effect.
+func TestEpochParams(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetCode(systemcontract.SysGovContractAddr, []byte{0x00})
+	writeParamsChange(statedb, &ParamsChange{Block: 130, Period: 5, Epoch: 50})
+
+	snap := newSnapshot(&params.CongressConfig{Period: 3, Epoch: 20}, nil, 119, common.Hash{}, nil)
+	if digest := applyEpochParams(&types.Header{Number: big.NewInt(120)}, statedb, snap); digest != encodeEpochParams(3, 20) {
+		t.Errorf("parameters changed before their block: %x", digest)
+	}
+	if readParamsChange(statedb) == nil {
+		t.Fatalf("scheduled parameters consumed before their block")
+	}
+	digest := applyEpochParams(&types.Header{Number: big.NewInt(140)}, statedb, snap)
+	if period, epoch, err := decodeEpochParams(digest); err != nil || period != 5 || epoch != 50 {
+		t.Errorf("parameters mismatch: have %d/%d (%v), want 5/50", period, epoch, err)
+	}
+	if change := readParamsChange(statedb); change != nil {
+		t.Errorf("scheduled parameters not consumed: %+v", change)
+	}
+	if _, _, err := decodeEpochParams(common.Hash{}); err != errInvalidEpochParams {
+		t.Errorf("zero epoch error mismatch: have %v, want %v", err, errInvalidEpochParams)
+	}
+	// Checkpoints since Agora bootstrap snapshots with their parameters
+	config := *params.AllCongressProtocolChanges
+	config.AgoraBlock = big.NewInt(4)
+	config.Congress = &params.CongressConfig{Period: 3, Epoch: 20}
+	engine := New(&config, rawdb.NewMemoryDatabase())
+
+	for _, tt := range []struct {
+		number        int64
+		period, epoch uint64
+	}{{0, 3, 20}, {140, 5, 50}} {
+		checkpoint := &types.Header{Number: big.NewInt(tt.number), Extra: make([]byte, extraVanity+common.AddressLength+extraSeal)}
+		if tt.number > 0 {
+			checkpoint.MixDigest = digest
+		}
+		snap, err := engine.checkpointSnapshot(checkpoint)
+		if err != nil {
+			t.Fatalf("block #%d: failed to create snapshot: %v", tt.number, err)
+		}
+		if snap.Period != tt.period || snap.Epoch != tt.epoch || len(snap.Validators) != 1 {
+			t.Errorf("block #%d: snapshot mismatch: have %d/%d, want %d/%d", tt.number, snap.Period, snap.Epoch, tt.period, tt.epoch)
+		}
+	}
+}
+
+// Tests that the system transactions only run the new actions since Agora.
+func TestApplySysTxAgora(t *testing.T) {
+	contract := common.Address{0xc}
+	config := *params.AllCongressProtocolChanges
+	config.AgoraBlock = big.NewInt(10)
+	engine := New(&config, rawdb.NewMemoryDatabase())
+
+	data, _ := rlp.EncodeToBytes(&Proposal{Id: new(big.Int), Action: big.NewInt(ActionSetCode), To: contract, Value: new(big.Int), Data: []byte{0x60, 0x00}})
+	tx := types.NewTransaction(0, systemcontract.SysGovToAddr, new(big.Int), 1000000, new(big.Int), data)
+
+	for _, tt := range []struct {
+		number int64
+		err    error
+	}{{9, errUnsupportedAction}, {10, nil}} {
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		evm := vm.NewEVM(vm.BlockContext{BlockNumber: big.NewInt(tt.number), GetHash: func(uint64) common.Hash { return common.Hash{} }}, vm.TxContext{}, statedb, &config, vm.Config{})
+		_, vmerr, err := engine.ApplySysTx(evm, statedb, 0, common.Address{0x1}, tx)
+		if err != nil {
+			t.Fatalf("block #%d: failed to apply system transaction: %v", tt.number, err)
+		}
+		if vmerr != tt.err {
+			t.Errorf("block #%d: error mismatch: have %v, want %v", tt.number, vmerr, tt.err)
+		}
+		if have := statedb.GetCodeSize(contract) > 0; have != (tt.err == nil) {
+			t.Errorf("block #%d: code set mismatch: have %v", tt.number, have)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package congress
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/congress/systemcontract"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// PrefetchSystemState implements consensus.SystemStatePrefetcher, reading the
+// address list storage root checked for every block and distributing the block reward
+// on the throwaway state to warm up the storage of the system contracts. The
+// results are dropped, as the state might not be the block's parent.
+func (c *Congress) PrefetchSystemState(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction) {
+	if c.chainConfig.IsRedCoast(header.Number) {
+		// The full lists are only loaded when the storage of the contract
+		// changes, see getAddressLists
+		state.StorageTrie(systemcontract.AddressListContractAddr)
+	}
+	if len(txs) == 0 {
+		return
+	}
+	addrs := make([]common.Address, 0, len(txs))
+	fees := make([]uint64, 0, len(txs))
+	for _, tx := range txs {
+		if tx.To() == nil {
+			addrs = append(addrs, common.Address{})
+		} else {
+			addrs = append(addrs, *tx.To())
+		}
+		fees = append(fees, tx.Gas()*tx.GasPrice().Uint64())
+	}
+	if err := c.trySendBlockReward(chain, header, state, addrs, fees, nil); err != nil {
+		log.Debug("Failed to prefetch block reward", "number", header.Number, "err", err)
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package congress
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/congress/systemcontract"
+	"github.com/ethereum/go-ethereum/consensus/congress/vmcaller"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Statuses of the system governance proposals. The contract passes proposals
+// as soon as they are committed, and the next block executes them.
+const (
+	ProposalPassed   = "passed"   // Committed, waiting to be executed by the next block
+	ProposalExecuted = "executed" // Executed and finished
+)
+
+// proposalTxKey returns the database key of the system governance transaction
+// executing the proposal with the given id.
+func proposalTxKey(id *big.Int) []byte {
+	return append([]byte("congress-proposal-"), common.BigToHash(id).Bytes()...)
+}
+
+// writeProposalTx records the system governance transaction executing the
+// proposal, the last block executing it wins in case of reorgs.
+func writeProposalTx(db ethdb.KeyValueWriter, id *big.Int, hash common.Hash) error {
+	return db.Put(proposalTxKey(id), hash.Bytes())
+}
+
+// readProposalTx retrieves the system governance transaction which executed
+// the proposal, if any is known.
+func readProposalTx(db ethdb.KeyValueReader, id *big.Int) (common.Hash, bool) {
+	blob, err := db.Get(proposalTxKey(id))
+	if err != nil || len(blob) != common.HashLength {
+		return common.Hash{}, false
+	}
+	return common.BytesToHash(blob), true
+}
+
+// proposalInfo is a system governance proposal along with its status.
+type proposalInfo struct {
+	Id      *hexutil.Big   `json:"id"`
+	Action  *hexutil.Big   `json:"action"`
+	From    common.Address `json:"from"`
+	To      common.Address `json:"to"`
+	Value   *hexutil.Big   `json:"value"`
+	Data    hexutil.Bytes  `json:"data"`
+	Status  string         `json:"status"`
+	Receipt *types.Receipt `json:"receipt,omitempty"` // Receipt of the system governance transaction executing the proposal
+}
+
+// getProposals lists the proposals committed to the system governance contract
+// as of the given block, with the receipts of the executed ones if they are
+// known to the node.
+func (c *Congress) getProposals(chain consensus.ChainHeaderReader, header *types.Header) ([]*proposalInfo, error) {
+	if !c.chainConfig.IsRedCoast(header.Number) {
+		return []*proposalInfo{}, nil
+	}
+	statedb, err := c.proposalState(header)
+	if err != nil {
+		return nil, err
+	}
+	passed := make(map[string]bool)
+	count, err := c.getPassedProposalCount(chain, header, statedb)
+	if err != nil {
+		return nil, err
+	}
+	for i := uint32(0); i < count; i++ {
+		prop, err := c.getPassedProposalByIndex(chain, header, statedb, i)
+		if err != nil {
+			return nil, err
+		}
+		passed[prop.Id.String()] = true
+	}
+	gov := c.abi[systemcontract.SysGovContractName]
+	ret, err := c.commonCallContract(header, statedb, gov, systemcontract.SysGovContractAddr, "getProposalsTotalCount", 1)
+	if err != nil {
+		return nil, err
+	}
+	total, ok := ret[0].(*big.Int)
+	if !ok || !total.IsUint64() {
+		return nil, fmt.Errorf("unexpected output type, value: %v", ret[0])
+	}
+	proposals := make([]*proposalInfo, 0, total.Uint64())
+	for id := uint64(0); id < total.Uint64(); id++ {
+		data, err := gov.Pack("getProposalById", new(big.Int).SetUint64(id))
+		if err != nil {
+			return nil, err
+		}
+		msg := vmcaller.NewLegacyMessage(header.Coinbase, &systemcontract.SysGovContractAddr, 0, new(big.Int), math.MaxUint64, new(big.Int), data, false)
+		result, err := vmcaller.ExecuteMsg(msg, statedb, header, newChainContext(chain, c), c.chainConfig)
+		if err != nil {
+			return nil, err
+		}
+		prop := new(Proposal)
+		if err := gov.UnpackIntoInterface(prop, "getProposalById", result); err != nil {
+			return nil, err
+		}
+		info := &proposalInfo{
+			Id:     (*hexutil.Big)(prop.Id),
+			Action: (*hexutil.Big)(prop.Action),
+			From:   prop.From,
+			To:     prop.To,
+			Value:  (*hexutil.Big)(prop.Value),
+			Data:   prop.Data,
+			Status: ProposalPassed,
+		}
+		if !passed[prop.Id.String()] {
+			info.Status = ProposalExecuted
+			info.Receipt = c.proposalReceipt(chain, header, prop.Id)
+		}
+		proposals = append(proposals, info)
+	}
+	return proposals, nil
+}
+
+// proposalReceipt retrieves the receipt of the canonical system governance
+// transaction executing the proposal no later than the given block.
+func (c *Congress) proposalReceipt(chain consensus.ChainHeaderReader, header *types.Header, id *big.Int) *types.Receipt {
+	hash, ok := readProposalTx(c.db, id)
+	if !ok {
+		return nil
+	}
+	_, blockHash, number, index := rawdb.ReadTransaction(c.db, hash)
+	if blockHash == (common.Hash{}) || number > header.Number.Uint64() {
+		return nil
+	}
+	receipts := rawdb.ReadReceipts(c.db, blockHash, number, chain.Config())
+	if index >= uint64(len(receipts)) {
+		return nil
+	}
+	return receipts[index]
+}
+
+// proposalState retrieves the state of the given block.
+func (c *Congress) proposalState(header *types.Header) (*state.StateDB, error) {
+	if c.stateFn == nil {
+		return nil, errors.New("state unavailable")
+	}
+	return c.stateFn(header.Root)
+}
+
+// accountState is the state of an account, with the storage slots which changed.
+type accountState struct {
+	Balance  *hexutil.Big                `json:"balance"`
+	Nonce    hexutil.Uint64              `json:"nonce"`
+	CodeHash common.Hash                 `json:"codeHash"`
+	Storage  map[common.Hash]common.Hash `json:"storage,omitempty"`
+}
+
+// proposalSimulation is the outcome of simulating a proposal.
+type proposalSimulation struct {
+	Success      bool                             `json:"success"`
+	ReturnData   hexutil.Bytes                    `json:"returnData,omitempty"`
+	Error        string                           `json:"error,omitempty"`
+	RevertReason string                           `json:"revertReason,omitempty"`
+	Logs         []*types.Log                     `json:"logs"`
+	Pre          map[common.Address]*accountState `json:"pre"`  // Changed accounts before the proposal
+	Post         map[common.Address]*accountState `json:"post"` // Changed accounts after the proposal, missing if deleted
+}
+
+// simulateProposal executes the proposal on a copy of the state of the given
+// block, as the system governance transaction of the next block sealed by the
+// in-turn validator would, and reports its effects.
+func (c *Congress) simulateProposal(chain consensus.ChainHeaderReader, parent *types.Header, prop *Proposal) (*proposalSimulation, error) {
+	statedb, err := c.proposalState(parent)
+	if err != nil {
+		return nil, err
+	}
+	header, err := c.nextHeader(chain, parent)
+	if err != nil {
+		return nil, err
+	}
+	propRLP, err := rlp.EncodeToBytes(prop)
+	if err != nil {
+		return nil, err
+	}
+	tx := types.NewTransaction(statedb.GetNonce(header.Coinbase), systemcontract.SysGovToAddr, new(big.Int), header.GasLimit, new(big.Int), propRLP)
+
+	// Execute the proposal, leaving the original state intact for the diff
+	pre := statedb.Copy()
+	evm := vm.NewEVM(core.NewEVMBlockContext(header, newChainContext(chain, c), nil), vm.TxContext{}, statedb, c.chainConfig, vm.Config{})
+	ret, vmerr, err := c.ApplySysTx(evm, statedb, 0, header.Coinbase, tx)
+	if err != nil {
+		return nil, err
+	}
+	statedb.Finalise(true)
+
+	result := &proposalSimulation{
+		Success:    vmerr == nil,
+		ReturnData: ret,
+		Logs:       statedb.GetLogs(tx.Hash(), common.Hash{}),
+		Pre:        make(map[common.Address]*accountState),
+		Post:       make(map[common.Address]*accountState),
+	}
+	if result.Logs == nil {
+		result.Logs = []*types.Log{}
+	}
+	if vmerr != nil {
+		result.Error = vmerr.Error()
+		if errors.Is(vmerr, vm.ErrExecutionReverted) {
+			result.RevertReason, _ = abi.UnpackRevert(ret)
+		}
+	}
+	for addr, slots := range statedb.PendingChanges() {
+		before, after := dumpAccountState(pre, addr, slots), dumpAccountState(statedb, addr, slots)
+		for _, slot := range slots {
+			if before != nil && after != nil && before.Storage[slot] == after.Storage[slot] {
+				delete(before.Storage, slot)
+				delete(after.Storage, slot)
+			}
+		}
+		if before != nil && after != nil && before.Balance.ToInt().Cmp(after.Balance.ToInt()) == 0 &&
+			before.Nonce == after.Nonce && before.CodeHash == after.CodeHash && len(before.Storage) == 0 {
+			continue
+		}
+		if before != nil {
+			result.Pre[addr] = before
+		}
+		if after != nil {
+			result.Post[addr] = after
+		}
+	}
+	return result, nil
+}
+
+// nextHeader creates the header of the next block sealed in turn on top of the
+// given one, to simulate transactions with.
+func (c *Congress) nextHeader(chain consensus.ChainHeaderReader, parent *types.Header) (*types.Header, error) {
+	snap, err := c.snapshot(chain, parent.Number.Uint64(), parent.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	validators := snap.inturnOrder()
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number, common.Big1),
+		Coinbase:   validators[(parent.Number.Uint64()+1)%uint64(len(validators))],
+		Difficulty: new(big.Int).Set(diffInTurn),
+		GasLimit:   parent.GasLimit,
+		Time:       parent.Time + snap.Period,
+	}
+	if c.chainConfig.IsLondon(header.Number) {
+		header.BaseFee = misc.CalcBaseFee(c.chainConfig, parent)
+	}
+	return header, nil
+}
+
+// dumpAccountState retrieves the state of the account with the given storage
+// slots, or nil if the account doesn't exist.
+func dumpAccountState(statedb *state.StateDB, addr common.Address, slots []common.Hash) *accountState {
+	if !statedb.Exist(addr) {
+		return nil
+	}
+	account := &accountState{
+		Balance:  (*hexutil.Big)(statedb.GetBalance(addr)),
+		Nonce:    hexutil.Uint64(statedb.GetNonce(addr)),
+		CodeHash: statedb.GetCodeHash(addr),
+		Storage:  make(map[common.Hash]common.Hash, len(slots)),
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Big().Cmp(slots[j].Big()) < 0 })
+	for _, slot := range slots {
+		account.Storage[slot] = statedb.GetState(addr, slot)
+	}
+	return account
+}
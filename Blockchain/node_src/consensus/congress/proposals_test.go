@@ -0,0 +1,100 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package congress
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that simulated proposals report their effects without touching the
+// state they run on.
+func TestSimulateProposal(t *testing.T) {
+	var (
+		validator = common.Address{0x1}
+		writer    = common.Address{0xa} // Stores 1 at slot 0 and emits a log
+		reverter  = common.Address{0xb} // Reverts with "nope"
+	)
+	reason, _ := abi.NewType("string", "", nil)
+	payload, _ := abi.Arguments{{Type: reason}}.Pack("nope")
+	payload = append(crypto.Keccak256([]byte("Error(string)"))[:4], payload...)
+	revertCode := append(common.FromHex("6064600c60003960646000fd"), payload...)
+
+	config := *params.AllCongressProtocolChanges
+	config.RedCoastBlock, config.SophonBlock = nil, nil
+	config.Congress = &params.CongressConfig{Period: 3, Epoch: 8}
+
+	db := rawdb.NewMemoryDatabase()
+	extra := append(make([]byte, extraVanity), append(validator.Bytes(), make([]byte, extraSeal)...)...)
+	genesis := (&core.Genesis{
+		Config:    &config,
+		ExtraData: extra,
+		GasLimit:  10_000_000,
+		Alloc: core.GenesisAlloc{
+			writer:   {Balance: new(big.Int), Code: common.FromHex("600160005560006000a000"), Storage: map[common.Hash]common.Hash{{0x1}: {0x1}}},
+			reverter: {Balance: new(big.Int), Code: revertCode},
+		},
+	}).MustCommit(db)
+
+	engine := New(&config, db)
+	sdb := state.NewDatabase(db)
+	engine.SetStateFn(func(root common.Hash) (*state.StateDB, error) { return state.New(root, sdb, nil) })
+	chain, err := core.NewHeaderChain(db, &config, engine, func() bool { return false })
+	if err != nil {
+		t.Fatalf("failed to create header chain: %v", err)
+	}
+	simulate := func(action int64, to common.Address) *proposalSimulation {
+		t.Helper()
+		prop := &Proposal{Id: new(big.Int), Action: big.NewInt(action), From: common.Address{0xf}, To: to, Value: new(big.Int)}
+		result, err := engine.simulateProposal(chain, genesis.Header(), prop)
+		if err != nil {
+			t.Fatalf("failed to simulate proposal: %v", err)
+		}
+		return result
+	}
+	// A successful call reports its storage changes and logs
+	result := simulate(0, writer)
+	if !result.Success || len(result.Logs) != 1 || result.Logs[0].Address != writer {
+		t.Fatalf("call result mismatch: %+v", result)
+	}
+	if have := result.Post[writer].Storage[common.Hash{}]; have != common.BigToHash(common.Big1) {
+		t.Errorf("post storage mismatch: have %x, want 1", have)
+	}
+	if have, ok := result.Pre[writer].Storage[common.Hash{}]; !ok || have != (common.Hash{}) {
+		t.Errorf("pre storage mismatch: have %x, want 0", have)
+	}
+	if pre, post := result.Pre[validator], result.Post[validator]; pre != nil || post == nil || post.Nonce != 1 {
+		t.Errorf("validator nonce not bumped: pre %+v, post %+v", pre, post)
+	}
+	// A reverting call reports the reason
+	result = simulate(0, reverter)
+	if result.Success || result.RevertReason != "nope" || len(result.Logs) != 0 {
+		t.Errorf("revert result mismatch: %+v", result)
+	}
+	// Erasing a contract without balance reports it deleted
+	result = simulate(1, writer)
+	if !result.Success || result.Pre[writer] == nil || result.Post[writer] != nil {
+		t.Errorf("erase result mismatch: %+v", result)
+	}
+	// None of the simulations may have modified the state
+	statedb, _ := engine.proposalState(genesis.Header())
+	if statedb.GetNonce(validator) != 0 || len(statedb.GetCode(writer)) == 0 || statedb.GetState(writer, common.Hash{}) != (common.Hash{}) {
+		t.Errorf("simulation modified the state")
+	}
+	// Check the transaction index round trip
+	if _, ok := readProposalTx(db, common.Big1); ok {
+		t.Errorf("unknown proposal indexed")
+	}
+	writeProposalTx(db, common.Big1, common.Hash{0x1})
+	if hash, ok := readProposalTx(db, common.Big1); !ok || hash != (common.Hash{0x1}) {
+		t.Errorf("proposal index mismatch: have %x, want %x", hash, common.Hash{0x1})
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package congress
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// signedPrefix is the signing protection database prefix of the highest block
+// each local validator signed, keyed by validator.
+var signedPrefix = []byte("congress-signed-")
+
+// signingInterchangeVersion is the version of the format the signing protection
+// is exported in.
+const signingInterchangeVersion = "1"
+
+// signedBlock is the highest block a validator signed.
+type signedBlock struct {
+	Number   uint64
+	SealHash common.Hash
+}
+
+func signedKey(validator common.Address) []byte {
+	return append(append([]byte{}, signedPrefix...), validator.Bytes()...)
+}
+
+// readSigned retrieves the highest block the validator signed, or nil if it
+// never signed any. A failure to read the database is an error, as it must not
+// be mistaken for a validator which never signed.
+func (c *Congress) readSigned(validator common.Address) (*signedBlock, error) {
+	if c.protection == nil {
+		return nil, errNoSigningProtection
+	}
+	key := signedKey(validator)
+	if has, err := c.protection.Has(key); err != nil || !has {
+		return nil, err
+	}
+	blob, err := c.protection.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	signed := new(signedBlock)
+	if err := rlp.DecodeBytes(blob, signed); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+func (c *Congress) writeSigned(validator common.Address, signed *signedBlock) error {
+	blob, err := rlp.EncodeToBytes(signed)
+	if err != nil {
+		return err
+	}
+	return c.protection.Put(signedKey(validator), blob)
+}
+
+// protectSeal records that the validator is about to sign the block, unless it
+// conflicts with the highest block the validator signed: only blocks above it,
+// or that very block again, may be signed. The block is recorded before it is
+// signed, so a signature never goes unrecorded.
+func (c *Congress) protectSeal(validator common.Address, number uint64, sealHash common.Hash) error {
+	c.signedLock.Lock()
+	defer c.signedLock.Unlock()
+
+	signed, err := c.readSigned(validator)
+	if err != nil {
+		return err
+	}
+	if signed != nil && number <= signed.Number {
+		if number == signed.Number && sealHash == signed.SealHash {
+			return nil
+		}
+		return fmt.Errorf("%w: #%d, already signed #%d [%x]", errConflictingSeal, number, signed.Number, signed.SealHash)
+	}
+	return c.writeSigned(validator, &signedBlock{Number: number, SealHash: sealHash})
+}
+
+// signingInterchange is the JSON format the signing protection of the local
+// validators is exported to and imported from, so that their keys can be moved
+// to another host along with it.
+type signingInterchange struct {
+	Metadata signingMetadata `json:"metadata"`
+	Data     []signedEntry   `json:"data"`
+}
+
+type signingMetadata struct {
+	Version     string      `json:"interchangeFormatVersion"`
+	GenesisHash common.Hash `json:"genesisHash"` // Chain the blocks were signed on
+}
+
+// signedEntry is the highest block signed by a validator.
+type signedEntry struct {
+	Validator common.Address `json:"validator"`
+	Number    hexutil.Uint64 `json:"number"`
+	SealHash  common.Hash    `json:"sealHash"`
+}
+
+// exportSigned returns the highest block signed by every local validator.
+func (c *Congress) exportSigned(genesis common.Hash) (*signingInterchange, error) {
+	c.signedLock.Lock()
+	defer c.signedLock.Unlock()
+
+	if c.protection == nil {
+		return nil, errNoSigningProtection
+	}
+	interchange := &signingInterchange{
+		Metadata: signingMetadata{Version: signingInterchangeVersion, GenesisHash: genesis},
+		Data:     []signedEntry{},
+	}
+	it := c.protection.NewIterator(signedPrefix, nil)
+	defer it.Release()
+
+	for it.Next() {
+		if len(it.Key()) != len(signedPrefix)+common.AddressLength {
+			continue
+		}
+		var signed signedBlock
+		if err := rlp.DecodeBytes(it.Value(), &signed); err != nil {
+			return nil, err
+		}
+		interchange.Data = append(interchange.Data, signedEntry{
+			Validator: common.BytesToAddress(it.Key()[len(signedPrefix):]),
+			Number:    hexutil.Uint64(signed.Number),
+			SealHash:  signed.SealHash,
+		})
+	}
+	return interchange, it.Error()
+}
+
+// importSigned merges the exported signing protection into the local one. The
+// highest block of each validator is kept; at the same height the local record
+// is, as the validator may not sign any other block there either way.
+func (c *Congress) importSigned(genesis common.Hash, interchange *signingInterchange) error {
+	if interchange.Metadata.Version != signingInterchangeVersion {
+		return fmt.Errorf("unsupported interchange format version %q", interchange.Metadata.Version)
+	}
+	if interchange.Metadata.GenesisHash != genesis {
+		return fmt.Errorf("genesis mismatch: have %x, want %x", interchange.Metadata.GenesisHash, genesis)
+	}
+	c.signedLock.Lock()
+	defer c.signedLock.Unlock()
+
+	for _, entry := range interchange.Data {
+		signed, err := c.readSigned(entry.Validator)
+		if err != nil {
+			return err
+		}
+		if signed != nil && signed.Number >= uint64(entry.Number) {
+			continue
+		}
+		if err := c.writeSigned(entry.Validator, &signedBlock{Number: uint64(entry.Number), SealHash: entry.SealHash}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package congress
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that validators only sign blocks above the highest one they signed,
+// and that the protection moves along with them to another host.
+func TestSigningProtection(t *testing.T) {
+	var (
+		validator = common.Address{0x1}
+		other     = common.Address{0x2}
+		genesis   = common.Hash{0x9}
+	)
+	engine := New(params.AllCongressProtocolChanges, rawdb.NewMemoryDatabase())
+	engine.SetSigningProtection(rawdb.NewMemoryDatabase())
+	for i, tt := range []struct {
+		validator common.Address
+		number    uint64
+		sealHash  common.Hash
+		ok        bool
+	}{
+		{validator, 5, common.Hash{0x5}, true},
+		{validator, 5, common.Hash{0x5}, true},  // The same block again
+		{validator, 5, common.Hash{0x6}, false}, // Another block at the same height
+		{validator, 4, common.Hash{0x4}, false}, // A block below
+		{other, 4, common.Hash{0x4}, true},      // Another validator
+		{validator, 7, common.Hash{0x7}, true},
+	} {
+		err := engine.protectSeal(tt.validator, tt.number, tt.sealHash)
+		if tt.ok && err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+		if !tt.ok && !errors.Is(err, errConflictingSeal) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, errConflictingSeal)
+		}
+	}
+	exported, err := engine.exportSigned(genesis)
+	if err != nil {
+		t.Fatalf("failed to export signing protection: %v", err)
+	}
+	blob, err := json.Marshal(exported)
+	if err != nil {
+		t.Fatalf("failed to encode signing protection: %v", err)
+	}
+	// Import it on a host where the validator signed lower and higher blocks
+	var interchange signingInterchange
+	if err := json.Unmarshal(blob, &interchange); err != nil {
+		t.Fatalf("failed to decode signing protection: %v", err)
+	}
+	host := New(params.AllCongressProtocolChanges, rawdb.NewMemoryDatabase())
+	host.SetSigningProtection(rawdb.NewMemoryDatabase())
+	host.protectSeal(validator, 3, common.Hash{0x3})
+	host.protectSeal(other, 9, common.Hash{0x9})
+
+	if err := host.importSigned(common.Hash{0x8}, &interchange); err == nil {
+		t.Errorf("signing protection of another chain imported")
+	}
+	if err := host.importSigned(genesis, &interchange); err != nil {
+		t.Fatalf("failed to import signing protection: %v", err)
+	}
+	for addr, want := range map[common.Address]signedBlock{validator: {7, common.Hash{0x7}}, other: {9, common.Hash{0x9}}} {
+		if have, err := host.readSigned(addr); err != nil || have == nil || *have != want {
+			t.Errorf("validator %x: signed block mismatch: have %v, want %v", addr, have, want)
+		}
+	}
+	if err := host.protectSeal(validator, 6, common.Hash{0x6}); !errors.Is(err, errConflictingSeal) {
+		t.Errorf("imported protection not enforced: %v", err)
+	}
+}
+
+// failingDatabase is a database failing every read.
+type failingDatabase struct {
+	ethdb.Database
+}
+
+var errReadFailure = errors.New("read failure")
+
+func (db failingDatabase) Has(key []byte) (bool, error)   { return false, errReadFailure }
+func (db failingDatabase) Get(key []byte) ([]byte, error) { return nil, errReadFailure }
+
+// Tests that a validator doesn't sign if its highest signed block can't be read.
+func TestSigningProtectionReadFailure(t *testing.T) {
+	engine := New(params.AllCongressProtocolChanges, rawdb.NewMemoryDatabase())
+	engine.SetSigningProtection(failingDatabase{rawdb.NewMemoryDatabase()})
+	if err := engine.protectSeal(common.Address{0x1}, 5, common.Hash{0x5}); !errors.Is(err, errReadFailure) {
+		t.Errorf("error mismatch: have %v, want %v", err, errReadFailure)
+	}
+}
+
+// Tests that the signing protection is kept out of the chain database, so that
+// it survives a resync, and that nothing is signed without it.
+func TestSigningProtectionDatabase(t *testing.T) {
+	var (
+		validator  = common.Address{0x1}
+		chaindb    = rawdb.NewMemoryDatabase()
+		protection = rawdb.NewMemoryDatabase()
+	)
+	engine := New(params.AllCongressProtocolChanges, chaindb)
+	if err := engine.protectSeal(validator, 5, common.Hash{0x5}); !errors.Is(err, errNoSigningProtection) {
+		t.Fatalf("error mismatch without protection: have %v, want %v", err, errNoSigningProtection)
+	}
+	engine.SetSigningProtection(protection)
+	if err := engine.protectSeal(validator, 5, common.Hash{0x5}); err != nil {
+		t.Fatalf("failed to protect seal: %v", err)
+	}
+	if has, _ := chaindb.Has(signedKey(validator)); has {
+		t.Errorf("signed block recorded in the chain database")
+	}
+	// Resync the chain into an empty database, keeping the protection
+	resynced := New(params.AllCongressProtocolChanges, rawdb.NewMemoryDatabase())
+	resynced.SetSigningProtection(protection)
+	if err := resynced.protectSeal(validator, 5, common.Hash{0x6}); !errors.Is(err, errConflictingSeal) {
+		t.Errorf("error mismatch after resync: have %v, want %v", err, errConflictingSeal)
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package congress
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// Where the denied addresses of a screened transaction are found.
+const (
+	ScreenSender    = "sender"    // Sender of the transaction
+	ScreenRecipient = "recipient" // Recipient of the transaction
+	ScreenCall      = "call"      // Caller or callee of an internal call
+	ScreenLog       = "log"       // Topic of a log matching an event check rule
+)
+
+// screenArgs is a transaction to screen against the blacklist.
+type screenArgs struct {
+	From  common.Address  `json:"from"`
+	To    *common.Address `json:"to"`
+	Gas   *hexutil.Uint64 `json:"gas"`
+	Value *hexutil.Big    `json:"value"`
+	Data  hexutil.Bytes   `json:"data"`
+}
+
+// screenMatch is a denied address involved in a screened transaction.
+type screenMatch struct {
+	Source    string          `json:"source"`
+	Address   common.Address  `json:"address"`
+	Direction string          `json:"direction"`          // Direction the address is blacklisted in
+	Check     string          `json:"check"`              // "from", "to" or "any"
+	Emitter   *common.Address `json:"emitter,omitempty"`  // Contract emitting the log
+	EventSig  *common.Hash    `json:"eventSig,omitempty"` // Event check rule matching the log
+	Topic     *hexutil.Uint   `json:"topic,omitempty"`    // Topic of the log holding the address
+}
+
+// screenResult is the outcome of screening a transaction.
+type screenResult struct {
+	Number  uint64         `json:"number"` // Block the transaction is screened on top of
+	Hash    common.Hash    `json:"hash"`
+	Denied  bool           `json:"denied"`
+	Matches []*screenMatch `json:"matches"`
+	Error   string         `json:"error,omitempty"` // Execution error, if any
+}
+
+// checkTypeString returns the name of the address check type.
+func checkTypeString(cType common.AddressCheckType) string {
+	switch cType {
+	case common.CheckFrom:
+		return "from"
+	case common.CheckTo:
+		return "to"
+	case common.CheckBothInAny:
+		return "any"
+	}
+	return "none"
+}
+
+// screenKey identifies a match, as the same addresses are usually checked
+// again by every call.
+type screenKey struct {
+	source  string
+	address common.Address
+	check   string
+	emitter common.Address
+	sig     common.Hash
+	topic   hexutil.Uint
+}
+
+// screeningValidator records every address the blacklist would deny during the
+// execution of a transaction, without denying any so all of them are found.
+type screeningValidator struct {
+	*blacklistValidator
+	matches []*screenMatch
+	seen    map[screenKey]bool
+}
+
+// add records the match unless it was already found.
+func (s *screeningValidator) add(match *screenMatch) {
+	key := screenKey{source: match.Source, address: match.Address, check: match.Check}
+	if match.Source == ScreenLog {
+		key.emitter, key.sig, key.topic = *match.Emitter, *match.EventSig, *match.Topic
+	}
+	if !s.seen[key] {
+		s.seen[key] = true
+		s.matches = append(s.matches, match)
+	}
+}
+
+func (s *screeningValidator) IsAddressDenied(address common.Address, cType common.AddressCheckType) bool {
+	if d, hit := s.matchAddress(address, cType); hit {
+		s.add(&screenMatch{Source: ScreenCall, Address: address, Direction: d.String(), Check: checkTypeString(cType)})
+	}
+	return false
+}
+
+func (s *screeningValidator) IsLogDenied(evLog *types.Log) bool {
+	for _, m := range s.matchLog(evLog) {
+		var (
+			emitter = evLog.Address
+			sig     = evLog.Topics[0]
+			topic   = hexutil.Uint(m.topic)
+		)
+		s.add(&screenMatch{Source: ScreenLog, Address: m.address, Direction: m.direction.String(), Check: checkTypeString(m.check), Emitter: &emitter, EventSig: &sig, Topic: &topic})
+	}
+	return false
+}
+
+// screenTransaction executes the transaction on a copy of the state of the
+// given block, as the next block would, and reports every address of the
+// blacklist it involves and why: as its sender or recipient like the
+// transaction pool checks, or in its internal calls and logs like the EVM
+// checks. Transactions are only screened by the checks enabled for the next
+// block.
+func (c *Congress) screenTransaction(chain consensus.ChainHeaderReader, parent *types.Header, args *screenArgs) (*screenResult, error) {
+	result := &screenResult{Number: parent.Number.Uint64(), Hash: parent.Hash(), Matches: []*screenMatch{}}
+	header, err := c.nextHeader(chain, parent)
+	if err != nil {
+		return nil, err
+	}
+	if c.chainConfig.RedCoastBlock == nil || c.chainConfig.RedCoastBlock.Cmp(header.Number) >= 0 {
+		return result, nil
+	}
+	statedb, err := c.proposalState(parent)
+	if err != nil {
+		return nil, err
+	}
+	lists, err := c.getAddressLists(chain, parent.Number.Uint64(), parent.Hash(), statedb)
+	if err != nil {
+		return nil, err
+	}
+	screen := &screeningValidator{
+		blacklistValidator: &blacklistValidator{blacks: lists.Blacks, rules: lists.Rules},
+		matches:            []*screenMatch{},
+		seen:               make(map[screenKey]bool),
+	}
+	if d, hit := screen.matchAddress(args.From, common.CheckFrom); hit {
+		screen.add(&screenMatch{Source: ScreenSender, Address: args.From, Direction: d.String(), Check: checkTypeString(common.CheckFrom)})
+	}
+	if args.To != nil {
+		if d, hit := screen.matchAddress(*args.To, common.CheckTo); hit {
+			screen.add(&screenMatch{Source: ScreenRecipient, Address: *args.To, Direction: d.String(), Check: checkTypeString(common.CheckTo)})
+		}
+	}
+	// Execute the transaction to find the addresses of its calls and logs
+	gas, value := header.GasLimit, new(big.Int)
+	if args.Gas != nil {
+		gas = uint64(*args.Gas)
+	}
+	if args.Value != nil {
+		value = args.Value.ToInt()
+	}
+	msg := types.NewMessage(args.From, args.To, statedb.GetNonce(args.From), value, gas, new(big.Int), new(big.Int), new(big.Int), args.Data, nil, true)
+
+	blockCtx := core.NewEVMBlockContext(header, newChainContext(chain, c), nil)
+	if c.chainConfig.SophonBlock != nil && c.chainConfig.SophonBlock.Cmp(header.Number) < 0 {
+		blockCtx.ExtraValidator = screen
+	}
+	evm := vm.NewEVM(blockCtx, core.NewEVMTxContext(msg), statedb, c.chainConfig, vm.Config{NoBaseFee: true})
+	res, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(gas))
+	switch {
+	case err != nil:
+		result.Error = err.Error()
+	case res.Err != nil:
+		result.Error = res.Err.Error()
+	}
+	result.Matches = screen.matches
+	result.Denied = len(result.Matches) > 0
+	return result, nil
+}
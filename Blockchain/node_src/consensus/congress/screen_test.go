@@ -0,0 +1,65 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package congress
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that screening reports the denied addresses of the internal calls and
+// logs of a transaction, which the blacklist validator denies.
+func TestScreeningValidator(t *testing.T) {
+	var (
+		sender   = common.Address{0x1}
+		contract = common.Address{0xc} // Calls callee, then emits a log with topics sig and holder
+		callee   = common.Address{0xd}
+		holder   = common.Address{0xe}
+		sig      = common.Hash{0x5}
+	)
+	code := append(append([]byte{0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x73}, callee.Bytes()...), 0x61, 0xff, 0xff, 0xf1, 0x50, 0x73)
+	code = append(append(code, holder.Bytes()...), 0x7f)
+	code = append(append(code, sig.Bytes()...), 0x60, 0x00, 0x60, 0x00, 0xa2, 0x00)
+
+	blacklist := &blacklistValidator{
+		blacks: map[common.Address]blacklistDirection{callee: DirectionTo, holder: DirectionBoth, contract: DirectionTo},
+		rules:  map[common.Hash]*EventCheckRule{sig: {EventSig: sig, Checks: map[int]common.AddressCheckType{1: common.CheckTo}}},
+	}
+	run := func(validator types.EvmExtraValidator) error {
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		statedb.SetCode(contract, code)
+		blockCtx := vm.BlockContext{CanTransfer: core.CanTransfer, Transfer: core.Transfer, BlockNumber: big.NewInt(1), ExtraValidator: validator}
+		evm := vm.NewEVM(blockCtx, vm.TxContext{Origin: sender}, statedb, params.TestChainConfig, vm.Config{})
+		for i := 0; i < 2; i++ {
+			if _, _, err := evm.Call(vm.AccountRef(sender), contract, nil, 1000000, new(big.Int)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := run(blacklist); !errors.Is(err, types.ErrAddressDenied) {
+		t.Fatalf("blacklist error mismatch: have %v, want %v", err, types.ErrAddressDenied)
+	}
+	screen := &screeningValidator{blacklistValidator: blacklist, seen: make(map[screenKey]bool)}
+	if err := run(screen); err != nil {
+		t.Fatalf("screened execution failed: %v", err)
+	}
+	if len(screen.matches) != 2 {
+		t.Fatalf("matches mismatch: have %d, want 2", len(screen.matches))
+	}
+	if m := screen.matches[0]; m.Source != ScreenCall || m.Address != callee || m.Check != "to" || m.Direction != DirectionTo.String() {
+		t.Errorf("call match mismatch: have %+v", m)
+	}
+	if m := screen.matches[1]; m.Source != ScreenLog || m.Address != holder || m.Check != "to" || *m.Emitter != contract || *m.EventSig != sig || *m.Topic != 1 {
+		t.Errorf("log match mismatch: have %+v", m)
+	}
+}
@@ -4,6 +4,7 @@ package congress
 
 import (
 	"bytes"
+	"encoding/binary"
 	"encoding/json"
 	"math"
 	"sort"
@@ -11,6 +12,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
@@ -26,6 +28,9 @@ type Snapshot struct {
 	Hash       common.Hash                 `json:"hash"`       // Block hash where the snapshot was created
 	Validators map[common.Address]struct{} `json:"validators"` // Set of authorized validators at this moment
 	Recents    map[uint64]common.Address   `json:"recents"`    // Set of recent validators for spam protections
+	Period     uint64                      `json:"period"`     // Seconds between the blocks after the snapshot
+	Epoch      uint64                      `json:"epoch"`      // Epoch length in effect after the snapshot
+	Seed       common.Hash                 `json:"seed"`       // Seed of the in-turn order of the epoch, zero before Kleros
 }
 
 // validatorsAscending implements the sort interface to allow sorting a list of addresses
@@ -45,6 +50,8 @@ func newSnapshot(config *params.CongressConfig, sigcache *lru.ARCCache, number u
 		Hash:       hash,
 		Validators: make(map[common.Address]struct{}),
 		Recents:    make(map[uint64]common.Address),
+		Period:     config.Period,
+		Epoch:      config.Epoch,
 	}
 	for _, validator := range validators {
 		snap.Validators[validator] = struct{}{}
@@ -65,6 +72,11 @@ func loadSnapshot(config *params.CongressConfig, sigcache *lru.ARCCache, db ethd
 	snap.config = config
 	snap.sigcache = sigcache
 
+	// Snapshots stored before Agora don't track the parameters, which could
+	// only be the configured ones back then
+	if snap.Epoch == 0 {
+		snap.Period, snap.Epoch = config.Period, config.Epoch
+	}
 	return snap, nil
 }
 
@@ -86,6 +98,9 @@ func (s *Snapshot) copy() *Snapshot {
 		Hash:       s.Hash,
 		Validators: make(map[common.Address]struct{}),
 		Recents:    make(map[uint64]common.Address),
+		Period:     s.Period,
+		Epoch:      s.Epoch,
+		Seed:       s.Seed,
 	}
 	for validator := range s.Validators {
 		cpy.Validators[validator] = struct{}{}
@@ -138,7 +153,7 @@ func (s *Snapshot) apply(headers []*types.Header, chain consensus.ChainHeaderRea
 		snap.Recents[number] = validator
 
 		// update validators at the first block at epoch
-		if number > 0 && number%s.config.Epoch == 0 {
+		if number > 0 && number%snap.Epoch == 0 {
 			checkpointHeader := header
 
 			// get validators from headers and use that for new validator set
@@ -284,6 +299,21 @@ func (s *Snapshot) apply(headers []*types.Header, chain consensus.ChainHeaderRea
 				"finalRecentCount", len(snap.Recents), "validatorCount", newValidatorCount)
 
 			snap.Validators = newValidators
+
+			// Since Agora, the checkpoint also carries the parameters of the
+			// blocks following it
+			if chain.Config().IsAgora(header.Number) {
+				period, epoch, err := decodeEpochParams(header.MixDigest)
+				if err != nil {
+					return nil, err
+				}
+				snap.Period, snap.Epoch = period, epoch
+			}
+			// Since Kleros, the checkpoint also seeds the in-turn order of the
+			// epoch it opens
+			if chain.Config().IsKleros(header.Number) {
+				snap.Seed = header.Hash()
+			}
 		}
 	}
 
@@ -303,9 +333,56 @@ func (s *Snapshot) validators() []common.Address {
 	return sigs
 }
 
+// inturnOrder retrieves the list of authorized validators in the order they
+// are in turn. Before Kleros it is the ascending one, afterwards it is shuffled
+// by the seed of the epoch so that the next validators in turn can't be told
+// before the checkpoint is sealed.
+func (s *Snapshot) inturnOrder() []common.Address {
+	validators := s.validators()
+	if s.Seed == (common.Hash{}) {
+		return validators
+	}
+	// Fisher-Yates shuffle, drawing each position from the hash of the seed
+	var index [8]byte
+	for i := len(validators) - 1; i > 0; i-- {
+		binary.BigEndian.PutUint64(index[:], uint64(i))
+		draw := crypto.Keccak256(s.Seed[:], index[:])
+		j := binary.BigEndian.Uint64(draw[:8]) % uint64(i+1)
+		validators[i], validators[j] = validators[j], validators[i]
+	}
+	return validators
+}
+
+// backupRank returns the position of the validator among the ones allowed to
+// seal the block at the given height out of turn, following the in-turn order
+// from the in-turn validator on: 0 for the first of them.
+func (s *Snapshot) backupRank(number uint64, validator common.Address) uint64 {
+	recent := make(map[common.Address]bool)
+	limit := uint64(len(s.Validators)/2 + 1)
+	for seen, recentValidator := range s.Recents {
+		if number < limit || seen > number-limit {
+			recent[recentValidator] = true
+		}
+	}
+	validators := s.inturnOrder()
+	count := uint64(len(validators))
+
+	var rank uint64
+	for i := uint64(1); i < count; i++ {
+		backup := validators[(number+i)%count]
+		if backup == validator {
+			break
+		}
+		if !recent[backup] {
+			rank++
+		}
+	}
+	return rank
+}
+
 // inturn returns if a validator at a given block height is in-turn or not.
 func (s *Snapshot) inturn(number uint64, validator common.Address) bool {
-	validators, offset := s.validators(), 0
+	validators, offset := s.inturnOrder(), 0
 	for offset < len(validators) && validators[offset] != validator {
 		offset++
 	}
@@ -0,0 +1,115 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package congress
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// StoredSnapshot retrieves the snapshot stored in the database for the block
+// with the given hash, or nil if none is stored. An error is returned if the
+// stored snapshot can't be decoded.
+func (c *Congress) StoredSnapshot(hash common.Hash) (*Snapshot, error) {
+	key := append([]byte("congress-"), hash[:]...)
+	if ok, _ := c.db.Has(key); !ok {
+		return nil, nil
+	}
+	blob, err := c.db.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	snap := new(Snapshot)
+	if err := json.Unmarshal(blob, snap); err != nil {
+		return nil, fmt.Errorf("corrupted snapshot: %v", err)
+	}
+	snap.config = c.config
+	snap.sigcache = c.signatures
+	return snap, nil
+}
+
+// RecomputeSnapshot recomputes the snapshot at the given block from the headers
+// alone, ignoring any cached or stored snapshots. The headers are replayed from
+// the checkpoint of the previous epoch: its validator set is taken from the
+// header, and the recent validators are fully determined again well before the
+// epoch of the block as long as the epoch is longer than the signing limit.
+func (c *Congress) RecomputeSnapshot(chain consensus.ChainHeaderReader, number uint64, hash common.Hash) (*Snapshot, error) {
+	// Walk back to the second checkpoint, told apart by its validator list as
+	// the epoch length may change since Agora
+	var (
+		headers     []*types.Header
+		checkpoint  *types.Header
+		checkpoints int
+	)
+	for {
+		header := chain.GetHeader(hash, number)
+		if header == nil {
+			return nil, fmt.Errorf("missing header #%d [%x..]", number, hash[:4])
+		}
+		if len(header.Extra) > extraVanity+extraSeal {
+			checkpoints++
+		}
+		if number == 0 || checkpoints == 2 {
+			checkpoint = header
+			break
+		}
+		headers = append(headers, header)
+		number, hash = number-1, header.ParentHash
+	}
+	for i := 0; i < len(headers)/2; i++ {
+		headers[i], headers[len(headers)-1-i] = headers[len(headers)-1-i], headers[i]
+	}
+	snap, err := c.checkpointSnapshot(checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	return snap.apply(headers, chain, nil)
+}
+
+// WriteSnapshot stores the snapshot in the database, replacing the one stored
+// for its block, and drops any copy cached in memory.
+func (c *Congress) WriteSnapshot(snap *Snapshot) error {
+	c.recents.Remove(snap.Hash)
+	return snap.store(c.db)
+}
+
+// Diff returns the differences of the snapshot from another one, or nil if
+// they are equal.
+func (s *Snapshot) Diff(other *Snapshot) []string {
+	var diffs []string
+	if s.Number != other.Number || s.Hash != other.Hash {
+		diffs = append(diffs, fmt.Sprintf("block: #%d [%x..] != #%d [%x..]", s.Number, s.Hash[:4], other.Number, other.Hash[:4]))
+	}
+	if s.Seed != other.Seed {
+		diffs = append(diffs, fmt.Sprintf("seed: %x != %x", s.Seed, other.Seed))
+	}
+	for validator := range s.Validators {
+		if _, ok := other.Validators[validator]; !ok {
+			diffs = append(diffs, fmt.Sprintf("validator %v: only in the first snapshot", validator))
+		}
+	}
+	for validator := range other.Validators {
+		if _, ok := s.Validators[validator]; !ok {
+			diffs = append(diffs, fmt.Sprintf("validator %v: only in the second snapshot", validator))
+		}
+	}
+	for number, validator := range s.Recents {
+		if recent, ok := other.Recents[number]; !ok {
+			diffs = append(diffs, fmt.Sprintf("recent #%d: %v only in the first snapshot", number, validator))
+		} else if recent != validator {
+			diffs = append(diffs, fmt.Sprintf("recent #%d: %v != %v", number, validator, recent))
+		}
+	}
+	for number, validator := range other.Recents {
+		if _, ok := s.Recents[number]; !ok {
+			diffs = append(diffs, fmt.Sprintf("recent #%d: %v only in the second snapshot", number, validator))
+		}
+	}
+	sort.Strings(diffs)
+	return diffs
+}
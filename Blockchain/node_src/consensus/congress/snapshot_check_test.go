@@ -0,0 +1,175 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package congress
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Tests that recomputed snapshots match the ones built while verifying the
+// chain, and that diverging stored snapshots are detected and repaired.
+func TestRecomputeSnapshot(t *testing.T) {
+	keys := make(map[common.Address]*ecdsa.PrivateKey)
+	var addrs []common.Address
+	for i := 0; i < 5; i++ {
+		key, _ := crypto.GenerateKey()
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		keys[addr] = key
+		addrs = append(addrs, addr)
+	}
+	sort.Sort(validatorsAscending(addrs))
+
+	// The validator set grows at the second epoch and shrinks at the fifth
+	validators := func(epoch uint64) []common.Address {
+		switch {
+		case epoch < 8:
+			return addrs[:3]
+		case epoch < 20:
+			return addrs
+		default:
+			return addrs[1:3]
+		}
+	}
+	config := *params.AllCongressProtocolChanges
+	config.RedCoastBlock, config.SophonBlock = nil, nil
+	config.Congress = &params.CongressConfig{Epoch: 8}
+
+	extra := make([]byte, extraVanity)
+	for _, addr := range validators(0) {
+		extra = append(extra, addr.Bytes()...)
+	}
+	gspec := &core.Genesis{
+		Config:    &config,
+		ExtraData: append(extra, make([]byte, extraSeal)...),
+		GasLimit:  params.GenesisGasLimit,
+		BaseFee:   big.NewInt(params.InitialBaseFee),
+	}
+	db := rawdb.NewMemoryDatabase()
+	genesis := gspec.MustCommit(db)
+
+	var (
+		parent  = genesis.Header()
+		headers []*types.Header
+		signers = make(map[uint64]common.Address)
+	)
+	for number := uint64(1); number <= 40; number++ {
+		active := validators((number - 1) / 8 * 8)
+
+		// Seal in turn, unless the in-turn validator signed recently
+		recent := func(addr common.Address) bool {
+			for seen := number - uint64(len(active)/2); seen < number; seen++ {
+				if signers[seen] == addr {
+					return true
+				}
+			}
+			return false
+		}
+		difficulty, signer := diffInTurn, active[number%uint64(len(active))]
+		if recent(signer) {
+			for _, addr := range active {
+				if !recent(addr) {
+					difficulty, signer = diffNoTurn, addr
+					break
+				}
+			}
+		}
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			UncleHash:  uncleHash,
+			Coinbase:   signer,
+			Difficulty: difficulty,
+			Number:     new(big.Int).SetUint64(number),
+			GasLimit:   parent.GasLimit,
+			Time:       parent.Time + 1,
+			BaseFee:    misc.CalcBaseFee(&config, parent),
+			Extra:      make([]byte, extraVanity),
+		}
+		if number%8 == 0 {
+			for _, addr := range validators(number) {
+				header.Extra = append(header.Extra, addr.Bytes()...)
+			}
+		}
+		header.Extra = append(header.Extra, make([]byte, extraSeal)...)
+		sig, _ := crypto.Sign(SealHash(header).Bytes(), keys[signer])
+		copy(header.Extra[len(header.Extra)-extraSeal:], sig)
+
+		signers[number] = signer
+		headers = append(headers, header)
+		parent = header
+	}
+	engine := New(&config, db)
+	chain, err := core.NewHeaderChain(db, &config, engine, func() bool { return false })
+	if err != nil {
+		t.Fatalf("failed to create header chain: %v", err)
+	}
+	if _, err := chain.ValidateHeaderChain(headers, 1); err != nil {
+		t.Fatalf("failed to verify headers: %v", err)
+	}
+	if _, err := chain.InsertHeaderChain(headers, time.Now()); err != nil {
+		t.Fatalf("failed to insert headers: %v", err)
+	}
+	for _, header := range append([]*types.Header{genesis.Header()}, headers...) {
+		number, hash := header.Number.Uint64(), header.Hash()
+		want, err := engine.snapshot(chain, number, hash, nil)
+		if err != nil {
+			t.Fatalf("block #%d: failed to retrieve snapshot: %v", number, err)
+		}
+		have, err := engine.RecomputeSnapshot(chain, number, hash)
+		if err != nil {
+			t.Fatalf("block #%d: failed to recompute snapshot: %v", number, err)
+		}
+		if diffs := have.Diff(want); len(diffs) != 0 {
+			t.Errorf("block #%d: recomputed snapshot diverges: %v", number, diffs)
+		}
+	}
+	// Tamper with the stored genesis snapshot and repair it
+	stored, err := engine.StoredSnapshot(genesis.Hash())
+	if err != nil || stored == nil {
+		t.Fatalf("genesis snapshot not stored: %v", err)
+	}
+	stored.Recents[0] = addrs[0]
+	delete(stored.Validators, addrs[1])
+	if err := stored.store(db); err != nil {
+		t.Fatalf("failed to store snapshot: %v", err)
+	}
+	snap, _ := engine.RecomputeSnapshot(chain, 0, genesis.Hash())
+	if stored, _ = engine.StoredSnapshot(genesis.Hash()); len(stored.Diff(snap)) != 2 {
+		t.Fatalf("divergence mismatch: have %v, want 2 differences", stored.Diff(snap))
+	}
+	if err := engine.WriteSnapshot(snap); err != nil {
+		t.Fatalf("failed to write snapshot: %v", err)
+	}
+	if stored, _ = engine.StoredSnapshot(genesis.Hash()); len(stored.Diff(snap)) != 0 {
+		t.Fatalf("repaired snapshot diverges: %v", stored.Diff(snap))
+	}
+	db.Put(append([]byte("congress-"), genesis.Hash().Bytes()...), []byte("{"))
+	if _, err := engine.StoredSnapshot(genesis.Hash()); err == nil {
+		t.Fatalf("corrupted snapshot accepted")
+	}
+	// Check that the recents explain who can't seal the next block
+	api := &API{chain: chain, congress: engine}
+	latest := rpc.LatestBlockNumber
+	recents, err := api.GetRecents(&latest)
+	if err != nil {
+		t.Fatalf("failed to retrieve recents: %v", err)
+	}
+	if recents.Limit != 2 || len(recents.Recents) != 1 {
+		t.Fatalf("recents mismatch: have limit %d, %d recents, want limit 2, 1 recent", recents.Limit, len(recents.Recents))
+	}
+	if recent := recents.Recents[0]; recent.Validator != signers[40] || recent.Signed != 40 || recent.Sealable != 42 {
+		t.Fatalf("recent mismatch: have %v, want %v signed #40 sealable at #42", recent, signers[40])
+	}
+}
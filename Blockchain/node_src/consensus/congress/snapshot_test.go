@@ -0,0 +1,187 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package congress
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the in-turn order is the ascending one without a seed, and a
+// permutation of it drawn from the seed otherwise.
+func TestInturnOrder(t *testing.T) {
+	snap := &Snapshot{Validators: make(map[common.Address]struct{}), Recents: make(map[uint64]common.Address)}
+	for i := byte(1); i <= 21; i++ {
+		snap.Validators[common.Address{i}] = struct{}{}
+	}
+	if order := snap.inturnOrder(); !reflect.DeepEqual(order, snap.validators()) {
+		t.Fatalf("unseeded order mismatch: have %x, want %x", order, snap.validators())
+	}
+	orders := make(map[common.Hash][]common.Address)
+	for _, seed := range []common.Hash{{0x1}, {0x2}} {
+		snap.Seed = seed
+		order := snap.inturnOrder()
+		if !reflect.DeepEqual(order, snap.inturnOrder()) {
+			t.Fatalf("seed %x: order not deterministic", seed)
+		}
+		sorted := append([]common.Address{}, order...)
+		sort.Sort(validatorsAscending(sorted))
+		if !reflect.DeepEqual(sorted, snap.validators()) {
+			t.Fatalf("seed %x: order is not a permutation of the validators: %x", seed, order)
+		}
+		orders[seed] = order
+	}
+	if reflect.DeepEqual(orders[common.Hash{0x1}], orders[common.Hash{0x2}]) || reflect.DeepEqual(orders[common.Hash{0x1}], snap.validators()) {
+		t.Errorf("order not shuffled by the seed")
+	}
+	// Backup validators are ranked after the in-turn one, skipping the ones
+	// which signed recently
+	order := snap.inturnOrder()
+	snap.Recents[99] = order[18]
+	for i, want := range map[int]uint64{17: 0, 19: 1, 20: 2, 0: 3} {
+		if rank := snap.backupRank(100, order[i]); rank != want {
+			t.Errorf("backup %d: rank mismatch: have %d, want %d", i, rank, want)
+		}
+	}
+}
+
+// Tests that the in-turn order is shuffled from the first checkpoint after the
+// Kleros fork on, and that blocks are verified and their difficulty calculated
+// against it.
+func TestShuffledInturnOrder(t *testing.T) {
+	keys := make(map[common.Address]*ecdsa.PrivateKey)
+	var addrs []common.Address
+	for i := 0; i < 4; i++ {
+		key, _ := crypto.GenerateKey()
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		keys[addr] = key
+		addrs = append(addrs, addr)
+	}
+	sort.Sort(validatorsAscending(addrs))
+
+	config := *params.AllCongressProtocolChanges
+	config.AgoraBlock, config.KlerosBlock = big.NewInt(4), big.NewInt(5)
+	config.Congress = &params.CongressConfig{Epoch: 8}
+
+	extra := make([]byte, extraVanity)
+	for _, addr := range addrs {
+		extra = append(extra, addr.Bytes()...)
+	}
+	gspec := &core.Genesis{
+		Config:    &config,
+		ExtraData: append(extra, make([]byte, extraSeal)...),
+		GasLimit:  params.GenesisGasLimit,
+		BaseFee:   big.NewInt(params.InitialBaseFee),
+	}
+	db := rawdb.NewMemoryDatabase()
+	genesis := gspec.MustCommit(db)
+
+	validators := make(map[common.Address]struct{})
+	for _, addr := range addrs {
+		validators[addr] = struct{}{}
+	}
+	var (
+		parent  = genesis.Header()
+		order   = addrs
+		headers []*types.Header
+		signers = make(map[uint64]common.Address)
+	)
+	seal := func(header *types.Header, signer common.Address) {
+		header.Coinbase = signer
+		sig, _ := crypto.Sign(SealHash(header).Bytes(), keys[signer])
+		copy(header.Extra[len(header.Extra)-extraSeal:], sig)
+	}
+	for number := uint64(1); number <= 24; number++ {
+		// Seal in turn, unless the in-turn validator signed recently
+		difficulty, signer := diffInTurn, order[number%uint64(len(order))]
+		if signedRecently(signers, number, signer) {
+			difficulty = diffNoTurn
+			for _, addr := range order {
+				if addr != signer && !signedRecently(signers, number, addr) {
+					signer = addr
+					break
+				}
+			}
+		}
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			UncleHash:  uncleHash,
+			Difficulty: difficulty,
+			Number:     new(big.Int).SetUint64(number),
+			GasLimit:   parent.GasLimit,
+			Time:       parent.Time + 1,
+			BaseFee:    misc.CalcBaseFee(&config, parent),
+			Extra:      make([]byte, extraVanity),
+		}
+		if number%8 == 0 {
+			header.Extra = append(header.Extra, extra[extraVanity:]...)
+			header.MixDigest = encodeEpochParams(0, 8)
+		}
+		header.Extra = append(header.Extra, make([]byte, extraSeal)...)
+		seal(header, signer)
+
+		headers = append(headers, header)
+		signers[number] = signer
+		parent = header
+
+		if number%8 == 0 {
+			order = (&Snapshot{Validators: validators, Seed: header.Hash()}).inturnOrder()
+		}
+	}
+	engine := New(&config, db)
+	chain, err := core.NewHeaderChain(db, &config, engine, func() bool { return false })
+	if err != nil {
+		t.Fatalf("failed to create header chain: %v", err)
+	}
+	if _, err := chain.InsertHeaderChain(headers[:20], time.Now()); err != nil {
+		t.Fatalf("failed to insert headers: %v", err)
+	}
+	snap, err := engine.snapshot(chain, 20, headers[19].Hash(), nil)
+	if err != nil {
+		t.Fatalf("failed to retrieve snapshot: %v", err)
+	}
+	if snap.Seed != headers[15].Hash() {
+		t.Errorf("seed mismatch: have %x, want %x", snap.Seed, headers[15].Hash())
+	}
+	order = (&Snapshot{Validators: validators, Seed: headers[15].Hash()}).inturnOrder()
+	for _, addr := range addrs {
+		want := diffNoTurn
+		if addr == order[21%len(order)] {
+			want = diffInTurn
+		}
+		engine.validator = addr
+		if diff := engine.CalcDifficulty(chain, headers[19].Time+1, headers[19]); diff.Cmp(want) != 0 {
+			t.Errorf("validator %x: difficulty mismatch: have %v, want %v", addr, diff, want)
+		}
+	}
+	// A block sealed in turn of the ascending order is rejected
+	if ascending := addrs[21%len(addrs)]; ascending != order[21%len(order)] && !signedRecently(signers, 21, ascending) {
+		header := types.CopyHeader(headers[20])
+		header.Difficulty = diffInTurn
+		seal(header, ascending)
+		if err := engine.VerifyHeader(chain, header, false); err != errWrongDifficulty {
+			t.Errorf("ascending in-turn block error mismatch: have %v, want %v", err, errWrongDifficulty)
+		}
+	}
+	if _, err := chain.InsertHeaderChain(headers[20:], time.Now()); err != nil {
+		t.Fatalf("failed to insert headers: %v", err)
+	}
+}
+
+// signedRecently returns whether the validator signed one of the two blocks
+// before the given one.
+func signedRecently(signers map[uint64]common.Address, number uint64, validator common.Address) bool {
+	return signers[number-1] == validator || signers[number-2] == validator
+}
@@ -3,6 +3,7 @@ package systemcontract
 import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
 	"math/big"
 	"strings"
@@ -2429,6 +2430,63 @@ const SysGovInteractiveABI = `
 		"stateMutability": "view",
 		"type": "function"
 	},
+	{
+		"inputs": [
+			{
+				"internalType": "uint256",
+				"name": "id",
+				"type": "uint256"
+			}
+		],
+		"name": "getProposalById",
+		"outputs": [
+			{
+				"internalType": "uint256",
+				"name": "id",
+				"type": "uint256"
+			},
+			{
+				"internalType": "uint256",
+				"name": "action",
+				"type": "uint256"
+			},
+			{
+				"internalType": "address",
+				"name": "from",
+				"type": "address"
+			},
+			{
+				"internalType": "address",
+				"name": "to",
+				"type": "address"
+			},
+			{
+				"internalType": "uint256",
+				"name": "value",
+				"type": "uint256"
+			},
+			{
+				"internalType": "bytes",
+				"name": "data",
+				"type": "bytes"
+			}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "getProposalsTotalCount",
+		"outputs": [
+			{
+				"internalType": "uint256",
+				"name": "",
+				"type": "uint256"
+			}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	},
 	{
 		"inputs": [
 			{
@@ -2446,6 +2504,119 @@ const SysGovInteractiveABI = `
 
 const AddrListInteractiveABI = `
 [
+	{
+	  "anonymous": false,
+	  "inputs": [
+		{
+		  "indexed": true,
+		  "internalType": "address",
+		  "name": "addr",
+		  "type": "address"
+		},
+		{
+		  "indexed": false,
+		  "internalType": "enum AddressList.Direction",
+		  "name": "d",
+		  "type": "uint8"
+		}
+	  ],
+	  "name": "BlackAddrAdded",
+	  "type": "event"
+	},
+	{
+	  "anonymous": false,
+	  "inputs": [
+		{
+		  "indexed": true,
+		  "internalType": "address",
+		  "name": "addr",
+		  "type": "address"
+		},
+		{
+		  "indexed": false,
+		  "internalType": "enum AddressList.Direction",
+		  "name": "d",
+		  "type": "uint8"
+		}
+	  ],
+	  "name": "BlackAddrRemoved",
+	  "type": "event"
+	},
+	{
+	  "anonymous": false,
+	  "inputs": [
+		{
+		  "indexed": true,
+		  "internalType": "bytes32",
+		  "name": "eventSig",
+		  "type": "bytes32"
+		},
+		{
+		  "indexed": false,
+		  "internalType": "uint128",
+		  "name": "checkIdx",
+		  "type": "uint128"
+		},
+		{
+		  "indexed": false,
+		  "internalType": "enum AddressList.CheckType",
+		  "name": "t",
+		  "type": "uint8"
+		}
+	  ],
+	  "name": "RuleAdded",
+	  "type": "event"
+	},
+	{
+	  "anonymous": false,
+	  "inputs": [
+		{
+		  "indexed": true,
+		  "internalType": "bytes32",
+		  "name": "eventSig",
+		  "type": "bytes32"
+		},
+		{
+		  "indexed": false,
+		  "internalType": "uint128",
+		  "name": "checkIdx",
+		  "type": "uint128"
+		},
+		{
+		  "indexed": false,
+		  "internalType": "enum AddressList.CheckType",
+		  "name": "t",
+		  "type": "uint8"
+		}
+	  ],
+	  "name": "RuleRemoved",
+	  "type": "event"
+	},
+	{
+	  "anonymous": false,
+	  "inputs": [
+		{
+		  "indexed": true,
+		  "internalType": "bytes32",
+		  "name": "eventSig",
+		  "type": "bytes32"
+		},
+		{
+		  "indexed": false,
+		  "internalType": "uint128",
+		  "name": "checkIdx",
+		  "type": "uint128"
+		},
+		{
+		  "indexed": false,
+		  "internalType": "enum AddressList.CheckType",
+		  "name": "t",
+		  "type": "uint8"
+		}
+	  ],
+	  "name": "RuleUpdated",
+	  "type": "event"
+	},
 	{
 	  "inputs": [],
 	  "name": "blackLastUpdatedNumber",
@@ -2687,33 +2858,78 @@ const PunishV1InteractiveABI = `[
     }
 ]`
 
-// DevMappingPosition is the position of the state variable `devs`.
-// Since the state variables are as follow:
-//    bool public initialized;
-//    bool public devVerifyEnabled;
-//    address public admin;
-//    address public pendingAdmin;
-//    mapping(address => bool) private devs;
-//    //NOTE: make sure this list is not too large!
-//    address[] blacksFrom;
-//    address[] blacksTo;
-//    mapping(address => uint256) blacksFromMap;      // address => index+1
-//    mapping(address => uint256) blacksToMap;        // address => index+1
-//    uint256 public blackLastUpdatedNumber; // last block number when the black list is updated
-//    uint256 public rulesLastUpdatedNumber;  // last block number when the rules are updated
-//    // event check rules
-//    EventCheckRule[] rules;
-//    mapping(bytes32 => mapping(uint128 => uint256)) rulesMap;   // eventSig => checkIdx => indexInArray+1
-// according to [Layout of State Variables in Storage](https://docs.soliditylang.org/en/v0.8.4/internals/layout_in_storage.html),
-// and after optimizer enabled, the `initialized`, `enabled` and `admin` will be packed, and stores at slot 0,
-// `pendingAdmin` stores at slot 1, so the position for `devs` is 2.
-const DevMappingPosition = 2
+// SlashingInteractiveABI is the part of the slashing contract the engine reads
+// the jail and slashing state of validators from.
+const SlashingInteractiveABI = `[
+    {
+      "inputs": [
+        {
+          "internalType": "address",
+          "name": "validator",
+          "type": "address"
+        }
+      ],
+      "name": "isJailed",
+      "outputs": [
+        {
+          "internalType": "bool",
+          "name": "",
+          "type": "bool"
+        }
+      ],
+      "stateMutability": "view",
+      "type": "function"
+    },
+    {
+      "inputs": [
+        {
+          "internalType": "address",
+          "name": "validator",
+          "type": "address"
+        }
+      ],
+      "name": "getSlashingRecord",
+      "outputs": [
+        {
+          "internalType": "uint256",
+          "name": "totalSlashed",
+          "type": "uint256"
+        },
+        {
+          "internalType": "uint256",
+          "name": "lastSlashTime",
+          "type": "uint256"
+        },
+        {
+          "internalType": "uint256",
+          "name": "doubleSignCount",
+          "type": "uint256"
+        },
+        {
+          "internalType": "bool",
+          "name": "isSlashed",
+          "type": "bool"
+        }
+      ],
+      "stateMutability": "view",
+      "type": "function"
+    }
+]`
 
+// Since Agora, the engine keeps the scheduled consensus parameters and the
+// force-removed validators in the storage of the system governance contract, at
+// hashed positions which no contract variable can occupy.
 var (
-	BlackLastUpdatedNumberPosition = common.BytesToHash([]byte{0x07})
-	RulesLastUpdatedNumberPosition = common.BytesToHash([]byte{0x08})
+	ParamsSchedulePosition    = crypto.Keccak256Hash([]byte("congress.params.schedule"))
+	RemovedValidatorsPosition = crypto.Keccak256Hash([]byte("congress.validators.removed"))
 )
 
+// RemovedValidatorPosition returns the position marking the validator as
+// force-removed, laid out as the entry of a mapping.
+func RemovedValidatorPosition(validator common.Address) common.Hash {
+	return crypto.Keccak256Hash(common.BytesToHash(validator.Bytes()).Bytes(), RemovedValidatorsPosition.Bytes())
+}
+
 var (
 	ValidatorsContractName   = "validators"
 	PunishContractName       = "punish"
@@ -2722,6 +2938,7 @@ var (
 	AddressListContractName  = "address_list"
 	ValidatorsV1ContractName = "validators_v1"
 	PunishV1ContractName     = "punish_v1"
+	SlashingContractName     = "slashing"
 	ValidatorsContractAddr   = common.HexToAddress("0x000000000000000000000000000000000000f000")
 	PunishContractAddr       = common.HexToAddress("0x000000000000000000000000000000000000f001")
 	ProposalAddr             = common.HexToAddress("0x000000000000000000000000000000000000f002")
@@ -2729,6 +2946,7 @@ var (
 	AddressListContractAddr  = common.HexToAddress("0x000000000000000000000000000000000000F004")
 	ValidatorsV1ContractAddr = common.HexToAddress("0x000000000000000000000000000000000000F005")
 	PunishV1ContractAddr     = common.HexToAddress("0x000000000000000000000000000000000000F006")
+	SlashingContractAddr     = common.HexToAddress("0x000000000000000000000000000000000000f007")
 	// SysGovToAddr is the To address for the system governance transaction, NOT contract address
 	SysGovToAddr = common.HexToAddress("0x000000000000000000000000000000000000ffff")
 
@@ -2752,6 +2970,8 @@ func init() {
 	abiMap[ValidatorsV1ContractName] = tmpABI
 	tmpABI, _ = abi.JSON(strings.NewReader(PunishV1InteractiveABI))
 	abiMap[PunishV1ContractName] = tmpABI
+	tmpABI, _ = abi.JSON(strings.NewReader(SlashingInteractiveABI))
+	abiMap[SlashingContractName] = tmpABI
 }
 
 func GetInteractiveABI() map[string]abi.ABI {
@@ -8,8 +8,8 @@ import (
 )
 
 func TestJsonUnmarshalABI(t *testing.T) {
-	for _, abiStr := range []string{ValidatorsInteractiveABI, PunishInteractiveABI, ProposalInteractiveABI, SysGovInteractiveABI, AddrListInteractiveABI} {
-		_, err := abi.JSON(strings.NewReader(ValidatorsInteractiveABI))
+	for _, abiStr := range []string{ValidatorsInteractiveABI, PunishInteractiveABI, ProposalInteractiveABI, SysGovInteractiveABI, AddrListInteractiveABI, SlashingInteractiveABI} {
+		_, err := abi.JSON(strings.NewReader(abiStr))
 		require.NoError(t, err, abiStr)
 	}
 }
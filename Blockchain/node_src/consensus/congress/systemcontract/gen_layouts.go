@@ -0,0 +1,44 @@
+// Code generated by genlayout. DO NOT EDIT.
+
+package systemcontract
+
+var addressListLayoutV1 = &StorageLayout{
+	Contract: "AddressList",
+	Version:  1,
+	Vars: []StorageVar{
+		{Label: "initialized", Slot: 0, Offset: 0, Size: 1, Encoding: EncodingInplace},
+		{Label: "devVerifyEnabled", Slot: 0, Offset: 1, Size: 1, Encoding: EncodingInplace},
+		{Label: "admin", Slot: 0, Offset: 2, Size: 20, Encoding: EncodingInplace},
+		{Label: "pendingAdmin", Slot: 1, Offset: 0, Size: 20, Encoding: EncodingInplace},
+		{Label: "devs", Slot: 2, Offset: 0, Size: 32, Encoding: EncodingMapping},
+		{Label: "blacksFrom", Slot: 3, Offset: 0, Size: 32, Encoding: EncodingDynamicArray},
+		{Label: "blacksTo", Slot: 4, Offset: 0, Size: 32, Encoding: EncodingDynamicArray},
+		{Label: "blacksFromMap", Slot: 5, Offset: 0, Size: 32, Encoding: EncodingMapping},
+		{Label: "blacksToMap", Slot: 6, Offset: 0, Size: 32, Encoding: EncodingMapping},
+	},
+}
+
+var addressListLayoutV2 = &StorageLayout{
+	Contract: "AddressList",
+	Version:  2,
+	Vars: []StorageVar{
+		{Label: "initialized", Slot: 0, Offset: 0, Size: 1, Encoding: EncodingInplace},
+		{Label: "devVerifyEnabled", Slot: 0, Offset: 1, Size: 1, Encoding: EncodingInplace},
+		{Label: "admin", Slot: 0, Offset: 2, Size: 20, Encoding: EncodingInplace},
+		{Label: "pendingAdmin", Slot: 1, Offset: 0, Size: 20, Encoding: EncodingInplace},
+		{Label: "devs", Slot: 2, Offset: 0, Size: 32, Encoding: EncodingMapping},
+		{Label: "blacksFrom", Slot: 3, Offset: 0, Size: 32, Encoding: EncodingDynamicArray},
+		{Label: "blacksTo", Slot: 4, Offset: 0, Size: 32, Encoding: EncodingDynamicArray},
+		{Label: "blacksFromMap", Slot: 5, Offset: 0, Size: 32, Encoding: EncodingMapping},
+		{Label: "blacksToMap", Slot: 6, Offset: 0, Size: 32, Encoding: EncodingMapping},
+		{Label: "blackLastUpdatedNumber", Slot: 7, Offset: 0, Size: 32, Encoding: EncodingInplace},
+		{Label: "rulesLastUpdatedNumber", Slot: 8, Offset: 0, Size: 32, Encoding: EncodingInplace},
+		{Label: "rules", Slot: 9, Offset: 0, Size: 32, Encoding: EncodingDynamicArray},
+		{Label: "rulesMap", Slot: 10, Offset: 0, Size: 32, Encoding: EncodingMapping},
+	},
+}
+
+// storageLayouts are the layouts of all the versions of the system contracts.
+var storageLayouts = map[string][]*StorageLayout{
+	"AddressList": {addressListLayoutV1, addressListLayoutV2},
+}
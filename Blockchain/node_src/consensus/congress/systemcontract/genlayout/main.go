@@ -0,0 +1,128 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+// genlayout generates the storage layouts of the system contracts from their
+// descriptors, which are named <contract>_v<version>.json and hold the storage
+// layout output of solc.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/consensus/congress/systemcontract"
+)
+
+var descriptorName = regexp.MustCompile(`^([a-z0-9_]+)_v([0-9]+)\.json$`)
+
+func main() {
+	var (
+		dir = flag.String("dir", "layouts", "directory of the layout descriptors")
+		out = flag.String("out", "gen_layouts.go", "output file")
+	)
+	flag.Parse()
+
+	code, err := generate(*dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*out, code, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// generate renders the layouts of all the descriptors in the directory.
+func generate(dir string) ([]byte, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var (
+		buf       = new(bytes.Buffer)
+		contracts = make(map[string][]string)
+		order     []string
+	)
+	fmt.Fprintln(buf, "// Code generated by genlayout. DO NOT EDIT.")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "package systemcontract")
+	fmt.Fprintln(buf)
+	for _, file := range files {
+		match := descriptorName.FindStringSubmatch(filepath.Base(file))
+		if match == nil {
+			return nil, fmt.Errorf("invalid descriptor name %s", file)
+		}
+		version, _ := strconv.Atoi(match[2])
+		blob, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		layout, err := systemcontract.ParseStorageLayout(blob)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		if len(contracts[layout.Contract])+1 != version {
+			return nil, fmt.Errorf("%s: version %d of %s follows %d versions", file, version, layout.Contract, len(contracts[layout.Contract]))
+		}
+		name := fmt.Sprintf("%sLayoutV%d", camelCase(match[1]), version)
+		if len(contracts[layout.Contract]) == 0 {
+			order = append(order, layout.Contract)
+		}
+		contracts[layout.Contract] = append(contracts[layout.Contract], name)
+
+		fmt.Fprintf(buf, "var %s = &StorageLayout{\n", name)
+		fmt.Fprintf(buf, "Contract: %q,\n", layout.Contract)
+		fmt.Fprintf(buf, "Version: %d,\n", version)
+		fmt.Fprintln(buf, "Vars: []StorageVar{")
+		for _, v := range layout.Vars {
+			fmt.Fprintf(buf, "{Label: %q, Slot: %d, Offset: %d, Size: %d, Encoding: %s},\n", v.Label, v.Slot, v.Offset, v.Size, encodingConst(v.Encoding))
+		}
+		fmt.Fprintln(buf, "},")
+		fmt.Fprintln(buf, "}")
+		fmt.Fprintln(buf)
+	}
+	fmt.Fprintln(buf, "// storageLayouts are the layouts of all the versions of the system contracts.")
+	fmt.Fprintln(buf, "var storageLayouts = map[string][]*StorageLayout{")
+	for _, contract := range order {
+		fmt.Fprintf(buf, "%q: {%s},\n", contract, strings.Join(contracts[contract], ", "))
+	}
+	fmt.Fprintln(buf, "}")
+
+	return format.Source(buf.Bytes())
+}
+
+// camelCase converts the snake case name of a descriptor to a Go identifier.
+func camelCase(name string) string {
+	parts := strings.Split(name, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// encodingConst returns the constant of the storage encoding.
+func encodingConst(encoding string) string {
+	switch encoding {
+	case systemcontract.EncodingInplace:
+		return "EncodingInplace"
+	case systemcontract.EncodingMapping:
+		return "EncodingMapping"
+	case systemcontract.EncodingDynamicArray:
+		return "EncodingDynamicArray"
+	case systemcontract.EncodingBytes:
+		return "EncodingBytes"
+	}
+	return strconv.Quote(encoding)
+}
@@ -0,0 +1,226 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package systemcontract
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// The layouts directory holds the storage layouts of every deployed version of
+// the system contracts read by the engine, in the storageLayout output format of
+// solc, which the generated layouts are compiled from. The AddressList sources
+// aren't part of System-Contracts, its descriptors follow the deployed code.
+//go:generate go run ./genlayout -dir layouts -out gen_layouts.go
+
+// Storage encodings of the state variables, as reported by solc.
+const (
+	EncodingInplace      = "inplace"
+	EncodingMapping      = "mapping"
+	EncodingDynamicArray = "dynamic_array"
+	EncodingBytes        = "bytes"
+)
+
+// StorageVar is a state variable of a system contract.
+type StorageVar struct {
+	Label    string // Name of the variable
+	Slot     uint64 // Slot storing the variable, or the base slot of a mapping or an array
+	Offset   int    // Offset of the variable in its slot, counted from the lowest order byte
+	Size     int    // Number of bytes of the variable in its slot
+	Encoding string // How the variable is stored
+}
+
+// StorageLayout is the storage layout of a version of a system contract. Later
+// versions of a contract may only append variables to the earlier layouts.
+type StorageLayout struct {
+	Contract string
+	Version  int
+	Vars     []StorageVar
+}
+
+// Var returns the state variable with the given name, or nil if this version
+// of the contract doesn't have it.
+func (l *StorageLayout) Var(label string) *StorageVar {
+	if l == nil {
+		return nil
+	}
+	for i := range l.Vars {
+		if l.Vars[i].Label == label {
+			return &l.Vars[i]
+		}
+	}
+	return nil
+}
+
+// solcStorageLayout is the storage layout output of solc, which the layout
+// descriptors are written in.
+type solcStorageLayout struct {
+	Storage []struct {
+		Contract string `json:"contract"`
+		Label    string `json:"label"`
+		Offset   int    `json:"offset"`
+		Slot     string `json:"slot"`
+		Type     string `json:"type"`
+	} `json:"storage"`
+	Types map[string]struct {
+		Encoding      string `json:"encoding"`
+		NumberOfBytes string `json:"numberOfBytes"`
+	} `json:"types"`
+}
+
+// ParseStorageLayout parses the storage layout of a contract, as output by
+// solc with the storageLayout selection.
+func ParseStorageLayout(blob []byte) (*StorageLayout, error) {
+	var solc solcStorageLayout
+	if err := json.Unmarshal(blob, &solc); err != nil {
+		return nil, err
+	}
+	layout := new(StorageLayout)
+	for _, v := range solc.Storage {
+		contract := v.Contract[strings.LastIndex(v.Contract, ":")+1:]
+		if layout.Contract == "" {
+			layout.Contract = contract
+		} else if layout.Contract != contract {
+			return nil, fmt.Errorf("variable %s of contract %s in the layout of %s", v.Label, contract, layout.Contract)
+		}
+		typ, ok := solc.Types[v.Type]
+		if !ok {
+			return nil, fmt.Errorf("variable %s of unknown type %s", v.Label, v.Type)
+		}
+		slot, err := strconv.ParseUint(v.Slot, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("variable %s: invalid slot: %v", v.Label, err)
+		}
+		size, err := strconv.Atoi(typ.NumberOfBytes)
+		if err != nil {
+			return nil, fmt.Errorf("variable %s: invalid size: %v", v.Label, err)
+		}
+		// Only value types are packed, others span whole slots
+		if typ.Encoding != EncodingInplace || size > common.HashLength {
+			size = common.HashLength
+		}
+		if v.Offset < 0 || v.Offset+size > common.HashLength {
+			return nil, fmt.Errorf("variable %s overflows its slot", v.Label)
+		}
+		layout.Vars = append(layout.Vars, StorageVar{Label: v.Label, Slot: slot, Offset: v.Offset, Size: size, Encoding: typ.Encoding})
+	}
+	return layout, nil
+}
+
+// AddressListLayout returns the storage layout of the address list contract
+// deployed at the given block, or nil if it isn't deployed yet.
+func AddressListLayout(config *params.ChainConfig, number *big.Int) *StorageLayout {
+	switch {
+	case config.IsSophon(number):
+		return addressListLayoutV2
+	case config.IsRedCoast(number):
+		return addressListLayoutV1
+	}
+	return nil
+}
+
+// StateReader is the part of the state the system contract readers need.
+type StateReader interface {
+	GetState(addr common.Address, hash common.Hash) common.Hash
+}
+
+// storageReader reads the state variables of a system contract through its
+// storage layout. Variables missing from the layout read as zero.
+type storageReader struct {
+	state  StateReader
+	addr   common.Address
+	layout *StorageLayout
+}
+
+// value returns the value of the state variable, or nil if the contract
+// doesn't have it.
+func (r *storageReader) value(label string) []byte {
+	v := r.layout.Var(label)
+	if v == nil || v.Encoding == EncodingMapping {
+		return nil
+	}
+	slot := r.state.GetState(r.addr, common.BigToHash(new(big.Int).SetUint64(v.Slot)))
+	return slot[common.HashLength-v.Offset-v.Size : common.HashLength-v.Offset]
+}
+
+// entry returns the value of the entry of the mapping, or nil if the contract
+// doesn't have the mapping.
+func (r *storageReader) entry(label string, key common.Hash) common.Hash {
+	v := r.layout.Var(label)
+	if v == nil || v.Encoding != EncodingMapping {
+		return common.Hash{}
+	}
+	return r.state.GetState(r.addr, MappingSlot(v.Slot, key))
+}
+
+// uint64 returns the value of the integer state variable, or zero if the
+// contract doesn't have it. Dynamic arrays read as their length.
+func (r *storageReader) uint64(label string) uint64 {
+	return new(big.Int).SetBytes(r.value(label)).Uint64()
+}
+
+// bool returns the value of the boolean state variable, or false if the
+// contract doesn't have it.
+func (r *storageReader) bool(label string) bool {
+	value := r.value(label)
+	return len(value) > 0 && value[len(value)-1] != 0
+}
+
+// MappingSlot returns the slot storing the entry of the mapping at the given
+// base slot, for a key padded to 32 bytes.
+func MappingSlot(slot uint64, key common.Hash) common.Hash {
+	return crypto.Keccak256Hash(key.Bytes(), common.BigToHash(new(big.Int).SetUint64(slot)).Bytes())
+}
+
+// AddressListReader reads the state of the address list contract.
+type AddressListReader struct {
+	storageReader
+}
+
+// NewAddressListReader creates a reader of the address list contract, laid out
+// as the version deployed at the given block.
+func NewAddressListReader(state StateReader, config *params.ChainConfig, number *big.Int) *AddressListReader {
+	return &AddressListReader{storageReader{state: state, addr: AddressListContractAddr, layout: AddressListLayout(config, number)}}
+}
+
+// DevVerifyEnabled returns whether the developer verification is enabled.
+func (r *AddressListReader) DevVerifyEnabled() bool {
+	return r.bool("devVerifyEnabled")
+}
+
+// IsDeveloper returns whether the address is an allowed developer.
+func (r *AddressListReader) IsDeveloper(addr common.Address) bool {
+	return r.entry("devs", common.BytesToHash(addr.Bytes())) != (common.Hash{})
+}
+
+// BlackLastUpdatedNumber returns the last block updating the blacklist.
+func (r *AddressListReader) BlackLastUpdatedNumber() uint64 {
+	return r.uint64("blackLastUpdatedNumber")
+}
+
+// RulesLastUpdatedNumber returns the last block updating the event check rules.
+func (r *AddressListReader) RulesLastUpdatedNumber() uint64 {
+	return r.uint64("rulesLastUpdatedNumber")
+}
+
+// BlacksFromLength returns the number of addresses denied as sender.
+func (r *AddressListReader) BlacksFromLength() uint64 {
+	return r.uint64("blacksFrom")
+}
+
+// BlacksToLength returns the number of addresses denied as recipient.
+func (r *AddressListReader) BlacksToLength() uint64 {
+	return r.uint64("blacksTo")
+}
+
+// RulesLength returns the number of event checks.
+func (r *AddressListReader) RulesLength() uint64 {
+	return r.uint64("rules")
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package systemcontract
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// artifactsDir holds the solc build info of the system contracts.
+var artifactsDir = filepath.Join("..", "..", "..", "..", "..", "System-Contracts", "contracts", "artifacts", "build-info")
+
+// Tests that the generated layouts are up to date with their descriptors.
+func TestLayoutsGenerated(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("layouts", "*.json"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no layout descriptors: %v", err)
+	}
+	name := regexp.MustCompile(`_v([0-9]+)\.json$`)
+	for _, file := range files {
+		blob, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file, err)
+		}
+		layout, err := ParseStorageLayout(blob)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", file, err)
+		}
+		layout.Version, _ = strconv.Atoi(name.FindStringSubmatch(file)[1])
+
+		versions := storageLayouts[layout.Contract]
+		if layout.Version < 1 || layout.Version > len(versions) {
+			t.Errorf("%s: version %d not generated, run go generate", file, layout.Version)
+			continue
+		}
+		if !reflect.DeepEqual(versions[layout.Version-1], layout) {
+			t.Errorf("%s: generated layout outdated, run go generate", file)
+		}
+	}
+}
+
+// Tests that every version of a system contract keeps the variables of the
+// previous one where they were, so upgrades don't reinterpret the storage.
+func TestLayoutsAppendOnly(t *testing.T) {
+	for contract, versions := range storageLayouts {
+		for i := 1; i < len(versions); i++ {
+			prev, next := versions[i-1], versions[i]
+			if len(next.Vars) < len(prev.Vars) || !reflect.DeepEqual(prev.Vars, next.Vars[:len(prev.Vars)]) {
+				t.Errorf("%s v%d: variables of v%d moved or removed", contract, next.Version, prev.Version)
+			}
+		}
+		for _, layout := range versions {
+			slots := make(map[uint64][]StorageVar)
+			for _, v := range layout.Vars {
+				for _, other := range slots[v.Slot] {
+					if v.Offset < other.Offset+other.Size && other.Offset < v.Offset+v.Size {
+						t.Errorf("%s v%d: %s overlaps %s", contract, layout.Version, v.Label, other.Label)
+					}
+				}
+				slots[v.Slot] = append(slots[v.Slot], v)
+			}
+		}
+	}
+}
+
+// Tests that the latest layouts match the storage layouts solc reports for the
+// system contracts built from source. Only the contracts whose build info is
+// available are checked.
+func TestLayoutsMatchArtifacts(t *testing.T) {
+	files, _ := filepath.Glob(filepath.Join(artifactsDir, "*.json"))
+	checked := 0
+	for _, file := range files {
+		blob, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file, err)
+		}
+		var info struct {
+			Output struct {
+				Contracts map[string]map[string]struct {
+					StorageLayout json.RawMessage `json:"storageLayout"`
+				} `json:"contracts"`
+			} `json:"output"`
+		}
+		if err := json.Unmarshal(blob, &info); err != nil {
+			t.Fatalf("failed to decode %s: %v", file, err)
+		}
+		for source, contracts := range info.Output.Contracts {
+			for name, contract := range contracts {
+				if len(contract.StorageLayout) == 0 {
+					continue
+				}
+				layout, err := ParseStorageLayout(contract.StorageLayout)
+				if err != nil {
+					t.Errorf("%s:%s: failed to parse storage layout: %v", source, name, err)
+					continue
+				}
+				versions := storageLayouts[name]
+				if len(versions) == 0 {
+					continue
+				}
+				latest := versions[len(versions)-1]
+				if !reflect.DeepEqual(latest.Vars, layout.Vars) {
+					t.Errorf("%s:%s: layout v%d diverges from the compiled contract", source, name, latest.Version)
+				}
+				checked++
+			}
+		}
+	}
+	if checked == 0 {
+		t.Skip("no build info of the system contracts with layouts")
+	}
+}
+
+// Tests that the address list reader finds the variables where each version of
+// the contract stores them.
+func TestAddressListReader(t *testing.T) {
+	var (
+		dev    = common.HexToAddress("0x5b38da6a701c568545dcfcb03fcb875f56beddc4")
+		config = &params.ChainConfig{RedCoastBlock: big.NewInt(10), SophonBlock: big.NewInt(20)}
+	)
+	if have, want := MappingSlot(2, common.BytesToHash(dev.Bytes())), common.HexToHash("0xb314f101a00aa0d8cc6704cc6dd1e9dd7551ec98c9df52079c192c560ba66c4a"); have != want {
+		t.Errorf("developer slot mismatch: have %x, want %x", have, want)
+	}
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	set := func(slot common.Hash, value common.Hash) {
+		statedb.SetState(AddressListContractAddr, slot, value)
+	}
+	// Slot 0 packs initialized, devVerifyEnabled and admin from the lowest order byte
+	set(common.Hash{}, common.HexToHash("0x00000000000000000000ffffffffffffffffffffffffffffffffffffffff0101"))
+	set(MappingSlot(2, common.BytesToHash(dev.Bytes())), common.BigToHash(common.Big1))
+	for slot := int64(3); slot <= 9; slot++ {
+		set(common.BigToHash(big.NewInt(slot)), common.BigToHash(big.NewInt(slot)))
+	}
+	tests := []struct {
+		number                      int64
+		enabled, developer          bool
+		blacksFrom, blacksTo, rules uint64
+		blackUpdated, rulesUpdated  uint64
+	}{
+		{9, false, false, 0, 0, 0, 0, 0},
+		{10, true, true, 3, 4, 0, 0, 0},
+		{20, true, true, 3, 4, 9, 7, 8},
+	}
+	for _, tt := range tests {
+		list := NewAddressListReader(statedb, config, big.NewInt(tt.number))
+		if list.DevVerifyEnabled() != tt.enabled || list.IsDeveloper(dev) != tt.developer || list.IsDeveloper(common.Address{0x1}) {
+			t.Errorf("block #%d: developers mismatch", tt.number)
+		}
+		if list.BlacksFromLength() != tt.blacksFrom || list.BlacksToLength() != tt.blacksTo || list.RulesLength() != tt.rules {
+			t.Errorf("block #%d: lengths mismatch: have %d/%d/%d", tt.number, list.BlacksFromLength(), list.BlacksToLength(), list.RulesLength())
+		}
+		if list.BlackLastUpdatedNumber() != tt.blackUpdated || list.RulesLastUpdatedNumber() != tt.rulesUpdated {
+			t.Errorf("block #%d: last updates mismatch", tt.number)
+		}
+	}
+	// The flag byte alone toggles the verification
+	set(common.Hash{}, common.HexToHash("0x00000000000000000000ffffffffffffffffffffffffffffffffffffffff0001"))
+	if NewAddressListReader(statedb, config, big.NewInt(20)).DevVerifyEnabled() {
+		t.Errorf("verification enabled by the neighbouring variables")
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package congress
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/congress/systemcontract"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	defaultStatusBlocks = 64   // Number of blocks the status covers by default
+	maxStatusBlocks     = 8192 // Maximum number of blocks the status may cover
+)
+
+// validatorStatusNames are the names of the statuses of the validators
+// contract, by value.
+var validatorStatusNames = []string{"notExist", "created", "staked", "unstaked", "jailed"}
+
+// inturnValidator returns the validator in turn for the block, given the
+// snapshot of its parent, and whether it is punished if the block is sealed
+// out of turn: the validators which signed recently are not.
+func inturnValidator(snap *Snapshot, number uint64) (common.Address, bool) {
+	validators := snap.inturnOrder()
+	validator := validators[number%uint64(len(validators))]
+	for _, recent := range snap.Recents {
+		if recent == validator {
+			return validator, false
+		}
+	}
+	return validator, true
+}
+
+// slashingRecord is the state of a validator in the slashing contract.
+type slashingRecord struct {
+	Jailed          bool         `json:"jailed"`
+	TotalSlashed    *hexutil.Big `json:"totalSlashed"`
+	LastSlashTime   uint64       `json:"lastSlashTime"`
+	DoubleSignCount uint64       `json:"doubleSignCount"`
+	Slashed         bool         `json:"slashed"`
+}
+
+// validatorStatus is the sealing activity of a validator over the blocks the
+// status covers, and its state in the system contracts at the last of them.
+type validatorStatus struct {
+	Sealed         uint64          `json:"sealed"`         // Blocks sealed, in turn or not
+	InturnSlots    uint64          `json:"inturnSlots"`    // Blocks the validator was in turn for
+	MissedInturn   uint64          `json:"missedInturn"`   // In-turn blocks sealed by another validator
+	Punished       uint64          `json:"punished"`       // Missed blocks the validator was punished for
+	MissedBlocks   []uint64        `json:"missedBlocks"`   // Numbers of the missed blocks
+	LastSealNumber *uint64         `json:"lastSealNumber"` // Last block sealed, if any was
+	LastSealTime   *uint64         `json:"lastSealTime"`
+	PunishCounter  uint64          `json:"punishCounter"` // Missed blocks counter of the punish contract
+	Status         string          `json:"status"`        // Status in the validators contract
+	Jailed         bool            `json:"jailed"`        // Jailed by the validators or the slashing contract
+	Slashing       *slashingRecord `json:"slashing,omitempty"`
+}
+
+// sealingWindow tallies the sealing activity of the validators over the given
+// number of blocks up to and including the header, and returns the headers of
+// the window. The validators of the snapshot of the header are always
+// reported, along with any other validator which was in turn or sealed.
+func (c *Congress) sealingWindow(chain consensus.ChainHeaderReader, header *types.Header, blocks uint64) (map[common.Address]*validatorStatus, []*types.Header, error) {
+	snap, err := c.snapshot(chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	statuses := make(map[common.Address]*validatorStatus)
+	get := func(validator common.Address) *validatorStatus {
+		if statuses[validator] == nil {
+			statuses[validator] = &validatorStatus{MissedBlocks: []uint64{}}
+		}
+		return statuses[validator]
+	}
+	for _, validator := range snap.validators() {
+		get(validator)
+	}
+	// Gather the window backwards so that it stays on the chain of the header
+	if number := header.Number.Uint64(); blocks > number {
+		blocks = number
+	}
+	headers := make([]*types.Header, blocks)
+	for i := len(headers) - 1; i >= 0; i-- {
+		headers[i] = header
+		if header = chain.GetHeader(header.ParentHash, header.Number.Uint64()-1); header == nil {
+			return nil, nil, fmt.Errorf("missing block %d", headers[i].Number.Uint64()-1)
+		}
+	}
+	if len(headers) == 0 {
+		return statuses, headers, nil
+	}
+	// Walk the snapshots of the parents along the window, rather than retrieve
+	// each of them, to keep the snapshot cache of the recent blocks intact
+	if snap, err = c.snapshot(chain, header.Number.Uint64(), header.Hash(), nil); err != nil {
+		return nil, nil, err
+	}
+	for i, h := range headers {
+		if i > 0 {
+			if snap, err = snap.apply(headers[i-1:i], chain, nil); err != nil {
+				return nil, nil, err
+			}
+		}
+		number := h.Number.Uint64()
+		validator, punishable := inturnValidator(snap, number)
+		inturn := get(validator)
+		inturn.InturnSlots++
+		if h.Difficulty.Cmp(diffInTurn) != 0 {
+			inturn.MissedInturn++
+			inturn.MissedBlocks = append(inturn.MissedBlocks, number)
+			if punishable {
+				inturn.Punished++
+			}
+		}
+		sealer, err := c.Author(h)
+		if err != nil {
+			return nil, nil, err
+		}
+		sealed, sealTime := get(sealer), h.Time
+		sealed.Sealed++
+		sealed.LastSealNumber, sealed.LastSealTime = &number, &sealTime
+	}
+	return statuses, headers, nil
+}
+
+// punishThresholds returns the number of missed blocks after which the punish
+// contract jails a validator and removes it from the validator set.
+func (c *Congress) punishThresholds(header *types.Header, statedb *state.StateDB) (uint64, uint64, error) {
+	var (
+		punishABI  = c.abi[systemcontract.PunishContractName]
+		punishAddr = systemcontract.GetPunishAddr(header.Number, c.chainConfig)
+		thresholds [2]uint64
+	)
+	for i, method := range []string{"punishThreshold", "removeThreshold"} {
+		ret, err := c.commonCallContract(header, statedb, punishABI, *punishAddr, method, 1)
+		if err != nil {
+			return 0, 0, err
+		}
+		threshold, ok := ret[0].(*big.Int)
+		if !ok {
+			return 0, 0, fmt.Errorf("invalid %s format", method)
+		}
+		thresholds[i] = threshold.Uint64()
+	}
+	return thresholds[0], thresholds[1], nil
+}
+
+// punishCounter returns the missed blocks counter of the validator in the
+// punish contract.
+func (c *Congress) punishCounter(header *types.Header, statedb *state.StateDB, validator common.Address) (uint64, error) {
+	punishAddr := systemcontract.GetPunishAddr(header.Number, c.chainConfig)
+	ret, err := c.commonCallContract(header, statedb, c.abi[systemcontract.PunishContractName], *punishAddr, "getPunishRecord", 1, validator)
+	if err != nil {
+		return 0, err
+	}
+	counter, ok := ret[0].(*big.Int)
+	if !ok {
+		return 0, errors.New("invalid punish record format")
+	}
+	return counter.Uint64(), nil
+}
+
+// fillContractStatus sets the punish counter, status, jail and slashing state
+// of the validator in the system contracts. The slashing state is only read if
+// the slashing contract is deployed.
+func (c *Congress) fillContractStatus(header *types.Header, statedb *state.StateDB, validator common.Address, status *validatorStatus) error {
+	counter, err := c.punishCounter(header, statedb, validator)
+	if err != nil {
+		return err
+	}
+	status.PunishCounter = counter
+
+	ret, err := c.commonCallContract(header, statedb, c.abi[systemcontract.ValidatorsContractName], systemcontract.ValidatorsContractAddr, "getValidatorInfo", 6, validator)
+	if err != nil {
+		return err
+	}
+	value, ok := ret[1].(uint8)
+	if !ok || int(value) >= len(validatorStatusNames) {
+		return fmt.Errorf("invalid validator status %v", ret[1])
+	}
+	status.Status = validatorStatusNames[value]
+	status.Jailed = status.Status == "jailed"
+
+	if statedb.GetCodeSize(systemcontract.SlashingContractAddr) == 0 {
+		return nil
+	}
+	slashingABI := c.abi[systemcontract.SlashingContractName]
+	if ret, err = c.commonCallContract(header, statedb, slashingABI, systemcontract.SlashingContractAddr, "isJailed", 1, validator); err != nil {
+		return err
+	}
+	jailed, ok := ret[0].(bool)
+	if !ok {
+		return errors.New("invalid jail state format")
+	}
+	if ret, err = c.commonCallContract(header, statedb, slashingABI, systemcontract.SlashingContractAddr, "getSlashingRecord", 4, validator); err != nil {
+		return err
+	}
+	total, ok1 := ret[0].(*big.Int)
+	last, ok2 := ret[1].(*big.Int)
+	count, ok3 := ret[2].(*big.Int)
+	slashed, ok4 := ret[3].(bool)
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return errors.New("invalid slashing record format")
+	}
+	status.Slashing = &slashingRecord{
+		Jailed:          jailed,
+		TotalSlashed:    (*hexutil.Big)(total),
+		LastSlashTime:   last.Uint64(),
+		DoubleSignCount: count.Uint64(),
+		Slashed:         slashed,
+	}
+	status.Jailed = status.Jailed || jailed
+	return nil
+}
+
+// missedBlock is an in-turn block a validator missed, as notified to the
+// subscribers.
+type missedBlock struct {
+	Number          uint64         `json:"number"`
+	Hash            common.Hash    `json:"hash"`
+	Time            uint64         `json:"time"`
+	Validator       common.Address `json:"validator"` // Validator which missed the block
+	Sealer          common.Address `json:"sealer"`    // Validator which sealed it out of turn
+	Punished        bool           `json:"punished"`
+	PunishCounter   uint64         `json:"punishCounter"` // Missed blocks counter after the block
+	PunishThreshold uint64         `json:"punishThreshold"`
+	RemoveThreshold uint64         `json:"removeThreshold"`
+}
+
+// missedBlock returns the in-turn slot missed by the block, or nil if it was
+// sealed in turn. The punish counter is only read if the state of the block is
+// available.
+func (c *Congress) missedBlock(chain consensus.ChainHeaderReader, header *types.Header) (*missedBlock, error) {
+	number := header.Number.Uint64()
+	if number == 0 || header.Difficulty.Cmp(diffInTurn) == 0 {
+		return nil, nil
+	}
+	snap, err := c.snapshot(chain, number-1, header.ParentHash, nil)
+	if err != nil {
+		return nil, err
+	}
+	sealer, err := c.Author(header)
+	if err != nil {
+		return nil, err
+	}
+	validator, punishable := inturnValidator(snap, number)
+	missed := &missedBlock{
+		Number:    number,
+		Hash:      header.Hash(),
+		Time:      header.Time,
+		Validator: validator,
+		Sealer:    sealer,
+		Punished:  punishable,
+	}
+	if c.stateFn == nil {
+		return missed, nil
+	}
+	statedb, err := c.stateFn(header.Root)
+	if err != nil {
+		return missed, nil
+	}
+	if missed.PunishCounter, err = c.punishCounter(header, statedb, validator); err != nil {
+		return nil, err
+	}
+	if missed.PunishThreshold, missed.RemoveThreshold, err = c.punishThresholds(header, statedb); err != nil {
+		return nil, err
+	}
+	return missed, nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package congress
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the sealing window attributes the out-of-turn blocks to the
+// validators which missed them, and tells the punished ones apart from the ones
+// which signed recently.
+func TestSealingWindow(t *testing.T) {
+	keys := make(map[common.Address]*ecdsa.PrivateKey)
+	var addrs []common.Address
+	for i := 0; i < 3; i++ {
+		key, _ := crypto.GenerateKey()
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		keys[addr] = key
+		addrs = append(addrs, addr)
+	}
+	sort.Sort(validatorsAscending(addrs))
+	a, b, c := addrs[0], addrs[1], addrs[2]
+
+	config := *params.AllCongressProtocolChanges
+	config.RedCoastBlock, config.SophonBlock = nil, nil
+	config.Congress = &params.CongressConfig{Epoch: 8}
+
+	extra := make([]byte, extraVanity)
+	for _, addr := range addrs {
+		extra = append(extra, addr.Bytes()...)
+	}
+	gspec := &core.Genesis{
+		Config:    &config,
+		ExtraData: append(extra, make([]byte, extraSeal)...),
+		GasLimit:  params.GenesisGasLimit,
+		BaseFee:   big.NewInt(params.InitialBaseFee),
+	}
+	db := rawdb.NewMemoryDatabase()
+	genesis := gspec.MustCommit(db)
+
+	// The second validator is offline, its slots and the ones of the validators
+	// which signed the block before theirs are sealed out of turn
+	var (
+		parent  = genesis.Header()
+		headers []*types.Header
+		last    common.Address
+	)
+	for number := uint64(1); number <= 12; number++ {
+		difficulty, signer := diffInTurn, addrs[number%3]
+		if signer == b || signer == last {
+			difficulty = diffNoTurn
+			for _, addr := range addrs {
+				if addr != b && addr != last {
+					signer = addr
+					break
+				}
+			}
+		}
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			UncleHash:  uncleHash,
+			Coinbase:   signer,
+			Difficulty: difficulty,
+			Number:     new(big.Int).SetUint64(number),
+			GasLimit:   parent.GasLimit,
+			Time:       parent.Time + 1,
+			BaseFee:    misc.CalcBaseFee(&config, parent),
+			Extra:      make([]byte, extraVanity),
+		}
+		if number%8 == 0 {
+			header.Extra = append(header.Extra, extra[extraVanity:]...)
+		}
+		header.Extra = append(header.Extra, make([]byte, extraSeal)...)
+		sig, _ := crypto.Sign(SealHash(header).Bytes(), keys[signer])
+		copy(header.Extra[len(header.Extra)-extraSeal:], sig)
+
+		headers = append(headers, header)
+		parent, last = header, signer
+	}
+	engine := New(&config, db)
+	chain, err := core.NewHeaderChain(db, &config, engine, func() bool { return false })
+	if err != nil {
+		t.Fatalf("failed to create header chain: %v", err)
+	}
+	if _, err := chain.InsertHeaderChain(headers, time.Now()); err != nil {
+		t.Fatalf("failed to insert headers: %v", err)
+	}
+	u64 := func(n uint64) *uint64 { return &n }
+	statuses, window, err := engine.sealingWindow(chain, parent, 12)
+	if err != nil {
+		t.Fatalf("failed to tally sealing window: %v", err)
+	}
+	if len(window) != 12 {
+		t.Fatalf("window length mismatch: have %d, want 12", len(window))
+	}
+	want := map[common.Address]*validatorStatus{
+		a: {Sealed: 6, InturnSlots: 4, MissedInturn: 2, MissedBlocks: []uint64{6, 12}, LastSealNumber: u64(11), LastSealTime: u64(genesis.Time() + 11)},
+		b: {InturnSlots: 4, MissedInturn: 4, Punished: 4, MissedBlocks: []uint64{1, 4, 7, 10}},
+		c: {Sealed: 6, InturnSlots: 4, MissedInturn: 2, MissedBlocks: []uint64{5, 11}, LastSealNumber: u64(12), LastSealTime: u64(genesis.Time() + 12)},
+	}
+	if !reflect.DeepEqual(statuses, want) {
+		for addr, status := range statuses {
+			t.Errorf("validator %x: have %+v, want %+v", addr, status, want[addr])
+		}
+	}
+	// A shorter window only covers the last blocks
+	statuses, _, err = engine.sealingWindow(chain, parent, 3)
+	if err != nil {
+		t.Fatalf("failed to tally sealing window: %v", err)
+	}
+	if have := statuses[b].MissedBlocks; !reflect.DeepEqual(have, []uint64{10}) {
+		t.Errorf("short window missed blocks mismatch: have %v, want [10]", have)
+	}
+	// Missed blocks are reported along with their sealer
+	missed, err := engine.missedBlock(chain, headers[3])
+	if err != nil || missed == nil {
+		t.Fatalf("failed to report missed block: %v", err)
+	}
+	if missed.Validator != b || missed.Sealer != c || !missed.Punished {
+		t.Errorf("missed block mismatch: have %+v", missed)
+	}
+	if missed, err = engine.missedBlock(chain, headers[1]); err != nil || missed != nil {
+		t.Errorf("in-turn block reported missed: %+v, %v", missed, err)
+	}
+}
@@ -13,13 +13,26 @@ import (
 
 // ExecuteMsg executes transaction sent to system contracts.
 func ExecuteMsg(msg core.Message, state *state.StateDB, header *types.Header, chainContext core.ChainContext, chainConfig *params.ChainConfig) (ret []byte, err error) {
+	return ExecuteMsgWithConfig(msg, state, header, chainContext, chainConfig, vm.Config{})
+}
+
+// ExecuteMsgWithConfig executes transaction sent to system contracts with the given evm config,
+// e.g. to trace it.
+func ExecuteMsgWithConfig(msg core.Message, state *state.StateDB, header *types.Header, chainContext core.ChainContext, chainConfig *params.ChainConfig, cfg vm.Config) (ret []byte, err error) {
 	blockContext := core.NewEVMBlockContext(header, chainContext, nil)
-	vmenv := vm.NewEVM(blockContext, core.NewEVMTxContext(msg), state, chainConfig, vm.Config{})
+	vmenv := vm.NewEVM(blockContext, core.NewEVMTxContext(msg), state, chainConfig, cfg)
 
-	ret, _, err = vmenv.Call(vm.AccountRef(msg.From()), *msg.To(), msg.Data(), msg.Gas(), msg.Value())
+	// A traced system call is a transaction of its own to the tracer
+	if cfg.Debug {
+		cfg.Tracer.CaptureTxStart(vmenv, msg.From(), msg.To(), msg.Gas())
+	}
+	ret, leftOverGas, err := vmenv.Call(vm.AccountRef(msg.From()), *msg.To(), msg.Data(), msg.Gas(), msg.Value())
 	// Finalise the statedb so any changes can take effect,
 	// and especially if the `from` account is empty, it can be finally deleted.
 	state.Finalise(true)
+	if cfg.Debug {
+		cfg.Tracer.CaptureTxEnd(leftOverGas)
+	}
 	if err != nil {
 		log.Error("ExecuteMsg failed", "err", err, "ret", string(ret))
 	}
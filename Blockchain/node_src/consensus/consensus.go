@@ -10,6 +10,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 )
@@ -18,6 +19,18 @@ var (
 	FeeRecoder = common.HexToAddress("0xffffffffffffffffffffffffffffffffffffffff")
 )
 
+// Kinds of the system calls a PoSA engine makes when finalizing a block.
+const (
+	SysCallReward   = "reward"   // Distribution of the block fees to the validators
+	SysCallPunish   = "punish"   // Punishment of a validator missing its turn
+	SysCallEpoch    = "epoch"    // Validator set update at the epoch block
+	SysCallProposal = "proposal" // Execution of a passed governance proposal
+)
+
+// SysCallTracerFn returns the EVM logger to attach to a system call of the
+// given kind, or nil to execute it untraced.
+type SysCallTracerFn func(kind string) vm.EVMLogger
+
 // ChainHeaderReader defines a small collection of methods needed to access the local
 // blockchain during header verification.
 type ChainHeaderReader interface {
@@ -137,6 +150,29 @@ type PoSA interface {
 
 	// ApplySysTx applies a system-transaction using a given evm,
 	ApplySysTx(evm *vm.EVM, state *state.StateDB, txIndex int, sender common.Address, tx *types.Transaction) (ret []byte, vmerr error, err error)
+
+	// TraceFinalize runs Finalize, attaching the EVM loggers returned by tracerFn
+	// to the system calls made along the way.
+	TraceFinalize(chain ChainHeaderReader, header *types.Header, state *state.StateDB, txs *[]*types.Transaction, uncles []*types.Header, receipts *[]*types.Receipt, systemTxs []*types.Transaction, tracerFn SysCallTracerFn) error
+}
+
+// SystemStatePrefetcher is implemented by the engines which can warm up the state
+// their system calls are going to touch when finalizing a block.
+type SystemStatePrefetcher interface {
+	// PrefetchSystemState runs the system calls of the given block on a
+	// throwaway state, after its transactions have been applied. It must not
+	// alter any engine cache, as the state is only a best effort guess.
+	PrefetchSystemState(chain ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction)
+}
+
+// BlockIndexer is implemented by the engines maintaining indexes over the blocks
+// written to the chain.
+type BlockIndexer interface {
+	// IndexBlock adds the block to the indexes of the engine, writing them to the
+	// given batch along with the block itself. Every block processed into the
+	// chain is indexed, whether or not it is canonical, but not the ones imported
+	// without being executed, e.g. by the fast sync.
+	IndexBlock(db ethdb.KeyValueWriter, block *types.Block, receipts []*types.Receipt)
 }
 
 type StateReader interface {
@@ -114,6 +114,10 @@ type CacheConfig struct {
 	TrieTimeLimit       time.Duration // Time limit after which to flush the current in-memory trie to disk
 	SnapshotLimit       int           // Memory allowance (MB) to use for caching snapshot entries in memory
 	Preimages           bool          // Whether to store preimage of trie key to the disk
+	StateScheme         string        // Scheme used to store the trie nodes, rawdb.HashScheme or rawdb.PathScheme
+	StateHistory        uint64        // Number of recent blocks to keep state histories for in the path scheme, zero for all
+	HistoryRetention    uint64        // Number of recent blocks to keep bodies and receipts for in the freezer, zero for all
+	StateIndex          bool          // Whether to index the state changes of every block for historical state access
 
 	SnapshotWait bool // Wait for snapshot construction on startup. TODO(karalabe): This is a dirty hack for testing, nuke it
 }
@@ -155,6 +159,10 @@ type BlockChain struct {
 	//  * nil: disable tx reindexer/deleter, but still index new blocks
 	txLookupLimit uint64
 
+	// stateIndexStale is set once the state index fell behind the chain and
+	// was reported, to avoid reporting it for every block.
+	stateIndexStale bool
+
 	hc            *HeaderChain
 	rmLogsFeed    event.Feed
 	chainFeed     event.Feed
@@ -216,7 +224,9 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 		stateCache: state.NewDatabaseWithConfig(db, &trie.Config{
 			Cache:     cacheConfig.TrieCleanLimit,
 			Journal:   cacheConfig.TrieCleanJournal,
-			Preimages: cacheConfig.Preimages,
+			Preimages:    cacheConfig.Preimages,
+			Scheme:       cacheConfig.StateScheme,
+			StateHistory: cacheConfig.StateHistory,
 		}),
 		quit:           make(chan struct{}),
 		chainmu:        syncx.NewClosableMutex(),
@@ -368,9 +378,19 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 	if txLookupLimit != nil {
 		bc.txLookupLimit = *txLookupLimit
 
+		// Expired blocks can't be indexed, keep the index within the history
+		if retention := bc.cacheConfig.HistoryRetention; retention > 0 && (bc.txLookupLimit == 0 || bc.txLookupLimit > retention) {
+			log.Info("Limiting transaction index to retained history", "txlookuplimit", bc.txLookupLimit, "retention", retention)
+			bc.txLookupLimit = retention
+		}
 		bc.wg.Add(1)
 		go bc.maintainTxIndex(txIndexBlock)
 	}
+	// Start the ancient history expirer.
+	if bc.cacheConfig.HistoryRetention > 0 {
+		bc.wg.Add(1)
+		go bc.maintainHistory()
+	}
 
 	// If periodic cache journal is required, spin it up.
 	if bc.cacheConfig.TrieCleanRejournal > 0 {
@@ -507,7 +527,7 @@ func (bc *BlockChain) setHeadBeyondRoot(head uint64, root common.Hash, repair bo
 					if root != (common.Hash{}) && !beyondRoot && newHeadBlock.Root() == root {
 						beyondRoot, rootNumber = true, newHeadBlock.NumberU64()
 					}
-					if _, err := state.New(newHeadBlock.Root(), bc.stateCache, bc.snaps); err != nil {
+					if _, err := state.New(newHeadBlock.Root(), bc.stateCache, bc.snaps); err != nil && !bc.recoverState(newHeadBlock.Root()) {
 						log.Trace("Block state missing, rewinding further", "number", newHeadBlock.NumberU64(), "hash", newHeadBlock.Hash())
 						if pivot == nil || newHeadBlock.NumberU64() > *pivot {
 							parent := bc.GetBlock(newHeadBlock.ParentHash(), newHeadBlock.NumberU64()-1)
@@ -610,6 +630,24 @@ func (bc *BlockChain) setHeadBeyondRoot(head uint64, root common.Hash, repair bo
 	return rootNumber, bc.loadLastState()
 }
 
+// recoverState reverts the persisted state to the given root, possible in the
+// path scheme as long as the state histories reaching it are retained.
+func (bc *BlockChain) recoverState(root common.Hash) bool {
+	triedb := bc.stateCache.TrieDB()
+	if !triedb.Recoverable(root) {
+		return false
+	}
+	if err := triedb.Recover(root); err != nil {
+		log.Error("Failed to recover state", "root", root, "err", err)
+		return false
+	}
+	// The snapshot layers are all descendants of the reverted state
+	if bc.snaps != nil {
+		bc.snaps.Rebuild(root)
+	}
+	return true
+}
+
 // FastSyncCommitHead sets the current head block to the one defined by the hash
 // irrelevant what the chain contents were prior.
 func (bc *BlockChain) FastSyncCommitHead(hash common.Hash) error {
@@ -730,6 +768,9 @@ func (bc *BlockChain) writeHeadBlock(block *types.Block) {
 		rawdb.WriteHeadHeaderHash(batch, block.Hash())
 		rawdb.WriteHeadFastBlockHash(batch, block.Hash())
 	}
+	if bc.cacheConfig.StateIndex {
+		bc.indexState(batch, block)
+	}
 	// Flush the whole batch into the disk, exit the node if failed
 	if err := batch.Write(); err != nil {
 		log.Crit("Failed to update chain indexes and markers", "err", err)
@@ -782,7 +823,12 @@ func (bc *BlockChain) Stop() {
 	//  - HEAD:     So we don't need to reprocess any blocks in the general case
 	//  - HEAD-1:   So we don't do large reorgs if our HEAD becomes an uncle
 	//  - HEAD-127: So we have a hard limit on the number of blocks reexecuted
-	if !bc.cacheConfig.TrieDirtyDisabled {
+	// The path scheme journals its in-memory states instead.
+	if triedb := bc.stateCache.TrieDB(); triedb.Scheme() == rawdb.PathScheme {
+		if err := triedb.Journal(bc.CurrentBlock().Root()); err != nil {
+			log.Error("Failed to journal in-memory trie nodes", "err", err)
+		}
+	} else if !bc.cacheConfig.TrieDirtyDisabled {
 		triedb := bc.stateCache.TrieDB()
 
 		for _, offset := range []uint64{0, 1, TriesInMemory - 1} {
@@ -1194,6 +1240,9 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 		rawdb.WriteBlock(blockBatch, block)
 		rawdb.WriteReceipts(blockBatch, block.Hash(), block.NumberU64(), receipts)
 		rawdb.WritePreimages(blockBatch, state.Preimages())
+		if indexer, ok := bc.engine.(consensus.BlockIndexer); ok {
+			indexer.IndexBlock(blockBatch, block, receipts)
+		}
 		if err := blockBatch.Write(); err != nil {
 			log.Crit("Failed to write block into disk", "err", err)
 		}
@@ -1205,6 +1254,10 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	blockHash := block.Header().Hash()
 	afterCommit := func(root common.Hash) {
 		triedb := bc.stateCache.TrieDB()
+		// The path scheme prunes the stale states on its own
+		if triedb.Scheme() == rawdb.PathScheme {
+			return
+		}
 		// If we're running an archive node, always flush
 		if bc.cacheConfig.TrieDirtyDisabled {
 			if err := triedb.Commit(root, false, nil); err != nil {
@@ -1270,6 +1323,9 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	if err = state.AsyncCommit(bc.chainConfig.IsEIP158(block.Number()), afterCommit); err != nil {
 		return NonStatTy, err
 	}
+	if changes := state.Changes(); bc.cacheConfig.StateIndex && changes != nil {
+		rawdb.WriteStateChanges(bc.db, blockNumber, blockHash, changes)
+	}
 
 	waitBlockBatchWrite.Wait()
 	// If the total difficulty is higher than our known, add it to the canonical chain
@@ -1571,6 +1627,9 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, er
 		if err != nil {
 			return it.index, err
 		}
+		if bc.cacheConfig.StateIndex {
+			statedb.TrackChanges()
+		}
 
 		// Enable prefetching to pull in trie node paths while processing transactions
 		statedb.StartPrefetcher("chain")
@@ -2058,6 +2117,10 @@ func (bc *BlockChain) skipBlock(err error, it *insertIterator) bool {
 func (bc *BlockChain) maintainTxIndex(ancients uint64) {
 	defer bc.wg.Done()
 
+	// The transactions below the history tail can't be indexed, their bodies are
+	// expired or were never available (chain imported from a state file).
+	floor := rawdb.ReadHistoryTail(bc.db)
+
 	// Before starting the actual maintenance, we need to handle a special case,
 	// where user might init Geth with an external ancient database. If so, we
 	// need to reindex all necessary transactions before starting to process any
@@ -2067,6 +2130,9 @@ func (bc *BlockChain) maintainTxIndex(ancients uint64) {
 		if bc.txLookupLimit != 0 && ancients > bc.txLookupLimit {
 			from = ancients - bc.txLookupLimit
 		}
+		if from < floor {
+			from = floor
+		}
 		rawdb.IndexTransactions(bc.db, from, ancients, bc.quit)
 	}
 
@@ -2088,18 +2154,22 @@ func (bc *BlockChain) maintainTxIndex(ancients uint64) {
 		}
 		// If a previous indexing existed, make sure that we fill in any missing entries
     if bc.txLookupLimit == 0 || head < bc.txLookupLimit {
-        if *tail > 0 {
-            rawdb.IndexTransactions(bc.db, 0, head+1, bc.quit)
+        if *tail > floor {
+            rawdb.IndexTransactions(bc.db, floor, head+1, bc.quit)
         }
         return
     }
 		// Update the transaction index to the new chain state
-		if head-bc.txLookupLimit+1 < *tail {
+		from := head - bc.txLookupLimit + 1
+		if from < floor {
+			from = floor
+		}
+		if from < *tail {
 			// Reindex a part of missing indices and rewind index tail to HEAD-limit
-			rawdb.IndexTransactions(bc.db, head-bc.txLookupLimit+1, *tail, bc.quit)
+			rawdb.IndexTransactions(bc.db, from, *tail, bc.quit)
 		} else {
 			// Unindex a part of stale indices and forward index tail to HEAD-limit
-			rawdb.UnindexTransactions(bc.db, *tail, head-bc.txLookupLimit+1, bc.quit)
+			rawdb.UnindexTransactions(bc.db, *tail, from, bc.quit)
 		}
 	}
 
@@ -2133,6 +2203,71 @@ func (bc *BlockChain) maintainTxIndex(ancients uint64) {
 	}
 }
 
+// maintainHistory is responsible for expiring the bodies and receipts of the
+// ancient blocks beyond the history retention window. Only the frozen blocks
+// whose transactions were unindexed already are expired, the indexer needs the
+// bodies to delete the lookup entries.
+func (bc *BlockChain) maintainHistory() {
+	defer bc.wg.Done()
+
+	expire := func(head uint64, done chan struct{}) {
+		defer func() { done <- struct{}{} }()
+
+		retention := bc.cacheConfig.HistoryRetention
+		if head <= retention {
+			return
+		}
+		limit := head - retention
+		frozen, err := bc.db.Ancients()
+		if err != nil {
+			return
+		}
+		if limit > frozen {
+			limit = frozen
+		}
+		if tail := rawdb.ReadTxIndexTail(bc.db); tail != nil && limit > *tail {
+			limit = *tail
+		}
+		tail := rawdb.ReadHistoryTail(bc.db)
+		if limit <= tail {
+			return
+		}
+		if err := rawdb.TruncateHistory(bc.db, limit); err != nil {
+			log.Error("Failed to expire ancient history", "limit", limit, "err", err)
+			return
+		}
+		if expired := rawdb.ReadHistoryTail(bc.db); expired > tail {
+			log.Info("Expired ancient chain history", "tail", expired, "retention", retention)
+		}
+	}
+	var (
+		done   chan struct{}                  // Non-nil if background expiring routine is active.
+		headCh = make(chan ChainHeadEvent, 1) // Buffered to avoid locking up the event feed
+	)
+	sub := bc.SubscribeChainHeadEvent(headCh)
+	if sub == nil {
+		return
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case head := <-headCh:
+			if done == nil {
+				done = make(chan struct{})
+				go expire(head.Block.NumberU64(), done)
+			}
+		case <-done:
+			done = nil
+		case <-bc.quit:
+			if done != nil {
+				<-done
+			}
+			return
+		}
+	}
+}
+
 // reportBlock logs a bad block error.
 func (bc *BlockChain) reportBlock(block *types.Block, receipts types.Receipts, err error) {
 	rawdb.WriteBadBlock(bc.db, block)
@@ -0,0 +1,193 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+var (
+	// errStateIndexUnavailable is returned if historical state is requested
+	// but the state index was never built.
+	errStateIndexUnavailable = errors.New("state index not available")
+
+	// errStateIndexStale is returned if historical state is requested but the
+	// state index does not follow the canonical chain.
+	errStateIndexStale = errors.New("state index out of sync with the canonical chain")
+)
+
+// indexState adds the state changes of a new head block to the state index,
+// rewinding the blocks of the previous chain first if the head was reorged.
+//
+// Note, this function assumes that the `mu` mutex is held!
+func (bc *BlockChain) indexState(batch ethdb.KeyValueWriter, block *types.Block) {
+	number, hash := block.NumberU64(), block.Hash()
+
+	// The state changes are only needed to rewind the index on reorgs, drop
+	// them once the blocks become immutable
+	if number > params.FullImmutabilityThreshold {
+		old := number - params.FullImmutabilityThreshold
+		for _, hash := range rawdb.ReadAllStateChangesHashes(bc.db, old) {
+			rawdb.DeleteStateChanges(batch, old, hash)
+		}
+	}
+	if number == 0 {
+		return
+	}
+	head, tail := rawdb.ReadStateIndexHead(bc.db), rawdb.ReadStateIndexTail(bc.db)
+	if head != nil && tail != nil && *head >= number {
+		for *head >= number && *head >= *tail {
+			indexed := rawdb.ReadStateIndexBlock(bc.db, *head)
+			changes := rawdb.ReadStateChanges(bc.db, *head, indexed)
+			if changes == nil {
+				bc.reportStaleStateIndex("State index can't be rewound", "number", *head, "hash", indexed)
+				return
+			}
+			rawdb.UnindexStateChanges(batch, *head, changes)
+			*head--
+		}
+		rawdb.WriteStateIndexHead(batch, *head)
+	}
+	changes := rawdb.ReadStateChanges(bc.db, number, hash)
+	if changes == nil {
+		bc.reportStaleStateIndex("State changes of block missing", "number", number, "hash", hash)
+		return
+	}
+	switch {
+	case head == nil || tail == nil || *head < *tail:
+		rawdb.WriteStateIndexTail(batch, number)
+	case *head+1 != number:
+		bc.reportStaleStateIndex("State index is behind the chain", "indexed", *head, "number", number)
+		return
+	}
+	rawdb.IndexStateChanges(batch, number, hash, changes)
+	rawdb.WriteStateIndexHead(batch, number)
+	bc.stateIndexStale = false
+}
+
+// reportStaleStateIndex warns the user that the state index can't follow the
+// chain any more, once until it recovers.
+func (bc *BlockChain) reportStaleStateIndex(msg string, ctx ...interface{}) {
+	if bc.stateIndexStale {
+		return
+	}
+	log.Warn(msg, append(ctx, "hint", "rebuild with 'geth db index-state'")...)
+	bc.stateIndexStale = true
+}
+
+// HistoricState returns a read-only state of the canonical block with the
+// given hash and number, reconstructed from the state index.
+func (bc *BlockChain) HistoricState(hash common.Hash, number uint64) (*state.StateDB, error) {
+	head, tail := rawdb.ReadStateIndexHead(bc.db), rawdb.ReadStateIndexTail(bc.db)
+	if head == nil || tail == nil || *head < *tail {
+		return nil, errStateIndexUnavailable
+	}
+	if number > *head || number+1 < *tail {
+		return nil, fmt.Errorf("block #%d out of the state index range [#%d, #%d]", number, *tail-1, *head)
+	}
+	if rawdb.ReadCanonicalHash(bc.db, number) != hash {
+		return nil, fmt.Errorf("block #%d [%x..] is not canonical", number, hash[:4])
+	}
+	headHash := rawdb.ReadStateIndexBlock(bc.db, *head)
+	if rawdb.ReadCanonicalHash(bc.db, *head) != headHash {
+		return nil, errStateIndexStale
+	}
+	header, headHeader := bc.GetHeader(hash, number), bc.GetHeader(headHash, *head)
+	if header == nil || headHeader == nil {
+		return nil, errStateIndexStale
+	}
+	return state.NewHistorical(bc.stateCache, bc.snaps, number, header.Root, headHeader.Root)
+}
+
+// RebuildStateIndex recreates the state index by re-executing the canonical
+// blocks from the given one up to the head. The state of the starting block
+// must be available.
+func (bc *BlockChain) RebuildStateIndex(from uint64) error {
+	if bc.stateCache.TrieDB().Scheme() == rawdb.PathScheme {
+		return errors.New("state index can't be rebuilt with the path scheme")
+	}
+	current := bc.CurrentBlock().NumberU64()
+	if from >= current {
+		return fmt.Errorf("block #%d is not below the head #%d", from, current)
+	}
+	base := bc.GetBlockByNumber(from)
+	if base == nil {
+		return fmt.Errorf("block #%d not found", from)
+	}
+	database := state.NewDatabaseWithConfig(bc.db, &trie.Config{Cache: 16})
+	if _, err := state.New(base.Root(), database, nil); err != nil {
+		return fmt.Errorf("state of block #%d not available: %v", from, err)
+	}
+	if err := rawdb.DeleteStateIndex(bc.db); err != nil {
+		return err
+	}
+	var (
+		triedb = database.TrieDB()
+		parent = base.Root()
+		start  = time.Now()
+		logged = time.Now()
+	)
+	for number := from + 1; number <= current; number++ {
+		block := bc.GetBlockByNumber(number)
+		if block == nil {
+			return fmt.Errorf("block #%d not found", number)
+		}
+		statedb, err := state.New(parent, database, nil)
+		if err != nil {
+			return err
+		}
+		statedb.TrackChanges()
+
+		receipts, _, usedGas, err := bc.processor.Process(block, statedb, bc.vmConfig)
+		if err != nil {
+			return fmt.Errorf("failed to process block #%d: %v", number, err)
+		}
+		if err := bc.validator.ValidateState(block, statedb, receipts, usedGas); err != nil {
+			return fmt.Errorf("invalid state of block #%d: %v", number, err)
+		}
+		root, err := statedb.Commit(bc.chainConfig.IsEIP158(block.Number()))
+		if err != nil {
+			return fmt.Errorf("failed to commit state of block #%d: %v", number, err)
+		}
+		// Keep the state of the last block only in memory, flushing it if
+		// the memory allowance is exceeded
+		triedb.Reference(root, common.Hash{})
+		triedb.Dereference(parent)
+		if nodes, _ := triedb.Size(); nodes > 256*1024*1024 {
+			if err := triedb.Cap(128 * 1024 * 1024); err != nil {
+				return err
+			}
+		}
+		parent = root
+
+		batch := bc.db.NewBatch()
+		if number+params.FullImmutabilityThreshold > current {
+			rawdb.WriteStateChanges(batch, number, block.Hash(), statedb.Changes())
+		}
+		rawdb.IndexStateChanges(batch, number, block.Hash(), statedb.Changes())
+		if number == from+1 {
+			rawdb.WriteStateIndexTail(batch, number)
+		}
+		rawdb.WriteStateIndexHead(batch, number)
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Rebuilding state index", "number", number, "head", current, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	log.Info("Rebuilt state index", "from", from, "head", current, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
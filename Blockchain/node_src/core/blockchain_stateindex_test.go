@@ -0,0 +1,137 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the states reconstructed from the state index match the states
+// of an archive node, also across reorgs and rebuilds.
+func TestStateIndex(t *testing.T) {
+	var (
+		engine  = ethash.NewFaker()
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		counter = common.HexToAddress("0xcccc")
+		aa      = common.HexToAddress("0xaaaa")
+		gspec   = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc: GenesisAlloc{
+				address: {Balance: big.NewInt(params.Ether)},
+				// The counter stores the number of the block it was last called in
+				counter: {Code: []byte{byte(vm.NUMBER), byte(vm.PUSH1), 0x0, byte(vm.SSTORE)}, Balance: big.NewInt(0)},
+				// AA selfdestructs if called
+				aa: {
+					Code:    []byte{byte(vm.PC), byte(vm.SELFDESTRUCT)},
+					Nonce:   1,
+					Balance: big.NewInt(0),
+					Storage: map[common.Hash]common.Hash{{0x01}: {0x01}, {0x02}: {0x02}},
+				},
+			},
+		}
+		signer   = types.LatestSigner(gspec.Config)
+		accounts = []common.Address{address, counter, aa, {0x01, 0x00}, {0x01, 0x01}, {0x01, 0x02}, {0x02}}
+	)
+	gendb := rawdb.NewMemoryDatabase()
+	genesis := gspec.MustCommit(gendb)
+
+	generate := func(parent *types.Block, n int, recipient byte) []*types.Block {
+		blocks, _ := GenerateChain(gspec.Config, parent, engine, gendb, n, func(i int, b *BlockGen) {
+			tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(address), common.Address{recipient, byte(i % 3)}, big.NewInt(1000), params.TxGas, b.BaseFee(), nil), signer, key)
+			b.AddTx(tx)
+			tx, _ = types.SignTx(types.NewTransaction(b.TxNonce(address), counter, big.NewInt(0), 50000, b.BaseFee(), nil), signer, key)
+			b.AddTx(tx)
+			switch parent.NumberU64() + uint64(i) + 1 {
+			case 4:
+				tx, _ = types.SignTx(types.NewTransaction(b.TxNonce(address), aa, big.NewInt(0), 50000, b.BaseFee(), nil), signer, key)
+				b.AddTx(tx)
+			case 7:
+				tx, _ = types.SignTx(types.NewTransaction(b.TxNonce(address), aa, big.NewInt(1), params.TxGas, b.BaseFee(), nil), signer, key)
+				b.AddTx(tx)
+			}
+		})
+		return blocks
+	}
+	canonical := generate(genesis, 10, 0x01)
+
+	db := rawdb.NewMemoryDatabase()
+	gspec.MustCommit(db)
+
+	config := *defaultCacheConfig
+	config.TrieDirtyDisabled = true
+	config.StateIndex = true
+	chain, err := NewBlockChain(db, &config, gspec.Config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	verify := func(blocks []*types.Block) {
+		t.Helper()
+		for _, block := range blocks {
+			historic, err := chain.HistoricState(block.Hash(), block.NumberU64())
+			if err != nil {
+				t.Fatalf("block #%d: failed to open historic state: %v", block.NumberU64(), err)
+			}
+			archive, err := state.New(block.Root(), chain.stateCache, nil)
+			if err != nil {
+				t.Fatalf("block #%d: failed to open archive state: %v", block.NumberU64(), err)
+			}
+			for _, account := range accounts {
+				if have, want := historic.Exist(account), archive.Exist(account); have != want {
+					t.Errorf("block #%d, account %x: existence mismatch: have %v, want %v", block.NumberU64(), account, have, want)
+				}
+				if have, want := historic.GetBalance(account), archive.GetBalance(account); have.Cmp(want) != 0 {
+					t.Errorf("block #%d, account %x: balance mismatch: have %v, want %v", block.NumberU64(), account, have, want)
+				}
+				if have, want := historic.GetNonce(account), archive.GetNonce(account); have != want {
+					t.Errorf("block #%d, account %x: nonce mismatch: have %v, want %v", block.NumberU64(), account, have, want)
+				}
+				if have, want := historic.GetCodeHash(account), archive.GetCodeHash(account); have != want {
+					t.Errorf("block #%d, account %x: code hash mismatch: have %x, want %x", block.NumberU64(), account, have, want)
+				}
+				for _, slot := range []common.Hash{{}, {0x01}, {0x02}} {
+					if have, want := historic.GetState(account, slot), archive.GetState(account, slot); have != want {
+						t.Errorf("block #%d, account %x, slot %x: value mismatch: have %x, want %x", block.NumberU64(), account, slot, have, want)
+					}
+				}
+			}
+		}
+	}
+	if _, err := chain.InsertChain(canonical); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	verify(append([]*types.Block{genesis}, canonical...))
+
+	// Reorg to a longer fork and check the index follows the new chain
+	fork := generate(canonical[4], 8, 0x02)
+	if _, err := chain.InsertChain(fork); err != nil {
+		t.Fatalf("failed to insert fork: %v", err)
+	}
+	verify(append([]*types.Block{genesis}, append(canonical[:5:5], fork...)...))
+	if _, err := chain.HistoricState(canonical[7].Hash(), 8); err == nil {
+		t.Fatalf("historic state returned for reorged block")
+	}
+	// Rebuild the index from a later block
+	if err := chain.RebuildStateIndex(3); err != nil {
+		t.Fatalf("failed to rebuild state index: %v", err)
+	}
+	if tail := rawdb.ReadStateIndexTail(db); tail == nil || *tail != 4 {
+		t.Fatalf("state index tail mismatch: have %v, want 4", tail)
+	}
+	if _, err := chain.HistoricState(canonical[0].Hash(), 1); err == nil {
+		t.Fatalf("historic state returned below the state index tail")
+	}
+	verify(append(canonical[2:5:5], fork...))
+}
@@ -3,6 +3,7 @@
 package core
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -3313,3 +3314,54 @@ func TestEIP1559Transition(t *testing.T) {
 		t.Fatalf("sender balance incorrect: expected %d, got %d", expected, actual)
 	}
 }
+
+// blockIndexEngine is an engine indexing the receipt count of the blocks.
+type blockIndexEngine struct {
+	consensus.Engine
+}
+
+func (e *blockIndexEngine) IndexBlock(db ethdb.KeyValueWriter, block *types.Block, receipts []*types.Receipt) {
+	db.Put(append([]byte("index-"), block.Hash().Bytes()...), []byte{byte(len(receipts))})
+}
+
+// Tests that the engine indexes every block written to the chain, canonical or
+// not, along with the block itself.
+func TestBlockIndexer(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		gspec   = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  GenesisAlloc{address: {Balance: big.NewInt(params.Ether)}},
+		}
+		signer = types.LatestSigner(gspec.Config)
+		engine = &blockIndexEngine{Engine: ethash.NewFaker()}
+		db     = rawdb.NewMemoryDatabase()
+	)
+	genesis := gspec.MustCommit(db)
+	blocks, _ := GenerateChain(gspec.Config, genesis, engine.Engine, db, 3, func(i int, b *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(address), common.Address{byte(i + 1)}, big.NewInt(1000), params.TxGas, b.BaseFee(), nil), signer, key)
+		b.AddTx(tx)
+	})
+	side, _ := GenerateChain(gspec.Config, genesis, engine.Engine, db, 1, func(i int, b *BlockGen) {
+		b.SetCoinbase(common.Address{0xff})
+	})
+	chain, err := NewBlockChain(db, nil, gspec.Config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	if _, err := chain.InsertChain(side); err != nil {
+		t.Fatalf("failed to insert side chain: %v", err)
+	}
+	for _, block := range append(blocks, side...) {
+		blob, _ := db.Get(append([]byte("index-"), block.Hash().Bytes()...))
+		if want := []byte{byte(len(block.Transactions()))}; !bytes.Equal(blob, want) {
+			t.Errorf("block #%d [%x..]: index mismatch: have %x, want %x", block.Number(), block.Hash().Bytes()[:4], blob, want)
+		}
+	}
+}
@@ -84,7 +84,10 @@ var (
 
 	ErrMetaTrans = errors.New("ErrMetaTrans")
 
-	ErrUnauthorizedDeveloper = errors.New("unauthorized developer")
+	// ErrUnauthorizedDeveloper is returned if the sender of a contract creation
+	// isn't an allowed developer while the developer verification is enabled.
+	ErrUnauthorizedDeveloper = types.ErrDeveloperNotAllowed
+
 	// ErrSenderNoEOA is returned if the sender of a transaction is a contract.
 	ErrSenderNoEOA = errors.New("sender not an eoa")
 )
@@ -380,9 +380,11 @@ func ReadBodyRLP(db ethdb.Reader, hash common.Hash, number uint64) rlp.RawValue
 		// Check if the data is in ancients
 		if isCanon(reader, number, hash) {
 			data, _ = reader.Ancient(freezerBodiesTable, number)
-			return nil
+			if len(data) > 0 {
+				return nil
+			}
 		}
-		// If not, try reading from leveldb
+		// If not or expired already, try reading from leveldb
 		data, _ = db.Get(blockBodyKey(number, hash))
 		return nil
 	})
@@ -419,7 +421,10 @@ func WriteBodyRLP(db ethdb.KeyValueWriter, hash common.Hash, number uint64, rlp
 // HasBody verifies the existence of a block body corresponding to the hash.
 func HasBody(db ethdb.Reader, hash common.Hash, number uint64) bool {
 	if isCanon(db, number, hash) {
-		return true
+		// Expired history might have been restored into the key-value store
+		if has, _ := db.HasAncient(freezerBodiesTable, number); has {
+			return true
+		}
 	}
 	if has, err := db.Has(blockBodyKey(number, hash)); !has || err != nil {
 		return false
@@ -509,7 +514,10 @@ func DeleteTd(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
 // to a block.
 func HasReceipts(db ethdb.Reader, hash common.Hash, number uint64) bool {
 	if isCanon(db, number, hash) {
-		return true
+		// Expired history might have been restored into the key-value store
+		if has, _ := db.HasAncient(freezerReceiptTable, number); has {
+			return true
+		}
 	}
 	if has, err := db.Has(blockReceiptsKey(number, hash)); !has || err != nil {
 		return false
@@ -524,9 +532,11 @@ func ReadReceiptsRLP(db ethdb.Reader, hash common.Hash, number uint64) rlp.RawVa
 		// Check if the data is in ancients
 		if isCanon(reader, number, hash) {
 			data, _ = reader.Ancient(freezerReceiptTable, number)
-			return nil
+			if len(data) > 0 {
+				return nil
+			}
 		}
-		// If not, try reading from leveldb
+		// If not or expired already, try reading from leveldb
 		data, _ = db.Get(blockReceiptsKey(number, hash))
 		return nil
 	})
@@ -909,6 +919,29 @@ func FindCommonAncestor(db ethdb.Reader, a, b *types.Header) *types.Header {
 	return a
 }
 
+// ReadHistoryTail retrieves the number of the first block whose body and
+// receipts are retained in the ancient store, the history below it expired.
+func ReadHistoryTail(db ethdb.AncientReader) uint64 {
+	var tail uint64
+	for _, kind := range []string{freezerBodiesTable, freezerReceiptTable} {
+		if n, err := db.AncientTail(kind); err == nil && n > tail {
+			tail = n
+		}
+	}
+	return tail
+}
+
+// TruncateHistory discards the bodies and receipts of the frozen blocks below
+// the given number. Headers, hashes and total difficulties are retained.
+func TruncateHistory(db ethdb.AncientWriter, tail uint64) error {
+	for _, kind := range []string{freezerBodiesTable, freezerReceiptTable} {
+		if err := db.TruncateAncientTail(kind, tail); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ReadHeadHeader returns the current canonical head header.
 func ReadHeadHeader(db ethdb.Reader) *types.Header {
 	headHeaderHash := ReadHeadHeaderHash(db)
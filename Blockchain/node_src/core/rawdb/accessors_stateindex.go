@@ -0,0 +1,245 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// AccountChange is the value of an account before it was changed by a block,
+// in the slim snapshot format. An empty value marks a non-existent account.
+type AccountChange struct {
+	Hash common.Hash
+	Prev []byte
+}
+
+// StorageChange is the value of a storage slot before it was changed by a
+// block, in the RLP encoded trie format. An empty value marks an unset slot.
+type StorageChange struct {
+	Account common.Hash
+	Slot    common.Hash
+	Prev    []byte
+}
+
+// StateChanges is the set of accounts and storage slots changed by a block,
+// sorted by hash.
+type StateChanges struct {
+	Accounts []AccountChange
+	Storage  []StorageChange
+}
+
+// ReadStateChanges retrieves the state changes of the block with the given
+// number and hash.
+func ReadStateChanges(db ethdb.KeyValueReader, number uint64, hash common.Hash) *StateChanges {
+	data, _ := db.Get(stateChangesKey(number, hash))
+	if len(data) == 0 {
+		return nil
+	}
+	changes := new(StateChanges)
+	if err := rlp.DecodeBytes(data, changes); err != nil {
+		log.Error("Invalid state changes RLP", "number", number, "hash", hash, "err", err)
+		return nil
+	}
+	return changes
+}
+
+// WriteStateChanges stores the state changes of the block with the given
+// number and hash.
+func WriteStateChanges(db ethdb.KeyValueWriter, number uint64, hash common.Hash, changes *StateChanges) {
+	data, err := rlp.EncodeToBytes(changes)
+	if err != nil {
+		log.Crit("Failed to RLP encode state changes", "err", err)
+	}
+	if err := db.Put(stateChangesKey(number, hash), data); err != nil {
+		log.Crit("Failed to store state changes", "err", err)
+	}
+}
+
+// DeleteStateChanges deletes the state changes of the block with the given
+// number and hash.
+func DeleteStateChanges(db ethdb.KeyValueWriter, number uint64, hash common.Hash) {
+	if err := db.Delete(stateChangesKey(number, hash)); err != nil {
+		log.Crit("Failed to delete state changes", "err", err)
+	}
+}
+
+// ReadAllStateChangesHashes retrieves the hashes of all blocks with the given
+// number that have state changes stored.
+func ReadAllStateChangesHashes(db ethdb.Iteratee, number uint64) []common.Hash {
+	prefix := append(stateChangesPrefix, encodeBlockNumber(number)...)
+	it := db.NewIterator(prefix, nil)
+	defer it.Release()
+
+	var hashes []common.Hash
+	for it.Next() {
+		if key := it.Key(); len(key) == len(prefix)+common.HashLength {
+			hashes = append(hashes, common.BytesToHash(key[len(prefix):]))
+		}
+	}
+	return hashes
+}
+
+// ReadStateIndexHead retrieves the number of the latest block in the state
+// index.
+func ReadStateIndexHead(db ethdb.KeyValueReader) *uint64 {
+	return readStateIndexMarker(db, stateIndexHeadKey)
+}
+
+// WriteStateIndexHead stores the number of the latest block in the state index.
+func WriteStateIndexHead(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Put(stateIndexHeadKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store state index head", "err", err)
+	}
+}
+
+// ReadStateIndexTail retrieves the number of the oldest block in the state
+// index.
+func ReadStateIndexTail(db ethdb.KeyValueReader) *uint64 {
+	return readStateIndexMarker(db, stateIndexTailKey)
+}
+
+// WriteStateIndexTail stores the number of the oldest block in the state index.
+func WriteStateIndexTail(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Put(stateIndexTailKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store state index tail", "err", err)
+	}
+}
+
+func readStateIndexMarker(db ethdb.KeyValueReader, key []byte) *uint64 {
+	data, _ := db.Get(key)
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// ReadStateIndexBlock retrieves the hash of the block indexed at the given
+// number.
+func ReadStateIndexBlock(db ethdb.KeyValueReader, number uint64) common.Hash {
+	data, _ := db.Get(stateIndexBlockKey(number))
+	if len(data) == 0 {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
+// IndexStateChanges adds the state changes of a block to the state index.
+func IndexStateChanges(db ethdb.KeyValueWriter, number uint64, hash common.Hash, changes *StateChanges) {
+	for _, change := range changes.Accounts {
+		if err := db.Put(stateIndexAccountKey(change.Hash, number), change.Prev); err != nil {
+			log.Crit("Failed to store account index", "err", err)
+		}
+	}
+	for _, change := range changes.Storage {
+		if err := db.Put(stateIndexStorageKey(change.Account, change.Slot, number), change.Prev); err != nil {
+			log.Crit("Failed to store storage index", "err", err)
+		}
+	}
+	if err := db.Put(stateIndexBlockKey(number), hash.Bytes()); err != nil {
+		log.Crit("Failed to store state index block", "err", err)
+	}
+}
+
+// UnindexStateChanges removes the state changes of a block from the state index.
+func UnindexStateChanges(db ethdb.KeyValueWriter, number uint64, changes *StateChanges) {
+	for _, change := range changes.Accounts {
+		if err := db.Delete(stateIndexAccountKey(change.Hash, number)); err != nil {
+			log.Crit("Failed to delete account index", "err", err)
+		}
+	}
+	for _, change := range changes.Storage {
+		if err := db.Delete(stateIndexStorageKey(change.Account, change.Slot, number)); err != nil {
+			log.Crit("Failed to delete storage index", "err", err)
+		}
+	}
+	if err := db.Delete(stateIndexBlockKey(number)); err != nil {
+		log.Crit("Failed to delete state index block", "err", err)
+	}
+}
+
+// ReadAccountIndex retrieves the first change of an account made by a block
+// after the given number, returning the block number and the account value
+// before that block. False is returned if the account has not changed since.
+func ReadAccountIndex(db ethdb.Iteratee, accountHash common.Hash, number uint64) (uint64, []byte, bool) {
+	return readStateIndex(db, append(stateIndexAccountPrefix, accountHash.Bytes()...), number)
+}
+
+// ReadStorageIndex retrieves the first change of a storage slot made by a
+// block after the given number, returning the block number and the slot value
+// before that block. False is returned if the slot has not changed since.
+func ReadStorageIndex(db ethdb.Iteratee, accountHash, storageHash common.Hash, number uint64) (uint64, []byte, bool) {
+	prefix := append(append(stateIndexStoragePrefix, accountHash.Bytes()...), storageHash.Bytes()...)
+	return readStateIndex(db, prefix, number)
+}
+
+func readStateIndex(db ethdb.Iteratee, prefix []byte, number uint64) (uint64, []byte, bool) {
+	it := db.NewIterator(prefix, encodeBlockNumber(number+1))
+	defer it.Release()
+
+	for it.Next() {
+		if key := it.Key(); len(key) == len(prefix)+8 {
+			return binary.BigEndian.Uint64(key[len(prefix):]), common.CopyBytes(it.Value()), true
+		}
+	}
+	return 0, nil, false
+}
+
+// ReadAccountIndexBlocks retrieves the numbers of the blocks in the range
+// [from, to] which changed the given account.
+func ReadAccountIndexBlocks(db ethdb.Iteratee, accountHash common.Hash, from, to uint64) []uint64 {
+	prefix := append(stateIndexAccountPrefix, accountHash.Bytes()...)
+	it := db.NewIterator(prefix, encodeBlockNumber(from))
+	defer it.Release()
+
+	var numbers []uint64
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(prefix)+8 {
+			continue
+		}
+		number := binary.BigEndian.Uint64(key[len(prefix):])
+		if number > to {
+			break
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers
+}
+
+// DeleteStateIndex removes the state index and all stored state changes.
+func DeleteStateIndex(db ethdb.KeyValueStore) error {
+	batch := db.NewBatch()
+	for _, prefix := range [][]byte{stateChangesPrefix, stateIndexBlockPrefix, stateIndexAccountPrefix, stateIndexStoragePrefix} {
+		it := db.NewIterator(prefix, nil)
+		for it.Next() {
+			if err := batch.Delete(it.Key()); err != nil {
+				it.Release()
+				return err
+			}
+			if batch.ValueSize() > ethdb.IdealBatchSize {
+				if err := batch.Write(); err != nil {
+					it.Release()
+					return err
+				}
+				batch.Reset()
+			}
+		}
+		it.Release()
+		if err := it.Error(); err != nil {
+			return err
+		}
+	}
+	if err := batch.Delete(stateIndexHeadKey); err != nil {
+		return err
+	}
+	if err := batch.Delete(stateIndexTailKey); err != nil {
+		return err
+	}
+	return batch.Write()
+}
@@ -0,0 +1,206 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package rawdb
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// HashScheme stores the trie nodes keyed by their hash, sharing identical
+	// nodes between states. Stale nodes can only be removed by offline pruning.
+	HashScheme = "hash"
+
+	// PathScheme stores the trie nodes keyed by their owner and path, keeping
+	// a single persisted state which is overwritten in place. Recent states are
+	// kept in memory and older ones are reachable through the state histories.
+	PathScheme = "path"
+)
+
+// ReadAccountTrieNode retrieves the account trie node at the given path.
+func ReadAccountTrieNode(db ethdb.KeyValueReader, path []byte) []byte {
+	data, _ := db.Get(accountTrieNodeKey(path))
+	return data
+}
+
+// WriteAccountTrieNode stores the account trie node at the given path.
+func WriteAccountTrieNode(db ethdb.KeyValueWriter, path []byte, node []byte) {
+	if err := db.Put(accountTrieNodeKey(path), node); err != nil {
+		log.Crit("Failed to store account trie node", "err", err)
+	}
+}
+
+// DeleteAccountTrieNode deletes the account trie node at the given path.
+func DeleteAccountTrieNode(db ethdb.KeyValueWriter, path []byte) {
+	if err := db.Delete(accountTrieNodeKey(path)); err != nil {
+		log.Crit("Failed to delete account trie node", "err", err)
+	}
+}
+
+// ReadStorageTrieNode retrieves the storage trie node of the given account at
+// the given path.
+func ReadStorageTrieNode(db ethdb.KeyValueReader, accountHash common.Hash, path []byte) []byte {
+	data, _ := db.Get(storageTrieNodeKey(accountHash, path))
+	return data
+}
+
+// WriteStorageTrieNode stores the storage trie node of the given account at
+// the given path.
+func WriteStorageTrieNode(db ethdb.KeyValueWriter, accountHash common.Hash, path []byte, node []byte) {
+	if err := db.Put(storageTrieNodeKey(accountHash, path), node); err != nil {
+		log.Crit("Failed to store storage trie node", "err", err)
+	}
+}
+
+// DeleteStorageTrieNode deletes the storage trie node of the given account at
+// the given path.
+func DeleteStorageTrieNode(db ethdb.KeyValueWriter, accountHash common.Hash, path []byte) {
+	if err := db.Delete(storageTrieNodeKey(accountHash, path)); err != nil {
+		log.Crit("Failed to delete storage trie node", "err", err)
+	}
+}
+
+// ReadStateScheme retrieves the trie node storage scheme of the database, or
+// the empty string if it was never recorded.
+func ReadStateScheme(db ethdb.KeyValueReader) string {
+	data, _ := db.Get(stateSchemeKey)
+	return string(data)
+}
+
+// WriteStateScheme stores the trie node storage scheme of the database.
+func WriteStateScheme(db ethdb.KeyValueWriter, scheme string) {
+	if err := db.Put(stateSchemeKey, []byte(scheme)); err != nil {
+		log.Crit("Failed to store state scheme", "err", err)
+	}
+}
+
+// ParseStateScheme checks the requested state scheme against the one of the
+// database, returning the scheme to use. Databases without a recorded scheme
+// holding a chain predate the path scheme and use the hash scheme, fresh ones
+// adopt the requested scheme (hash by default) which is recorded.
+func ParseStateScheme(provided string, db ethdb.Database) (string, error) {
+	if provided != "" && provided != HashScheme && provided != PathScheme {
+		return "", fmt.Errorf("unknown state scheme %q", provided)
+	}
+	stored := ReadStateScheme(db)
+	if stored == "" {
+		switch {
+		case ReadHeadBlockHash(db) != (common.Hash{}):
+			stored = HashScheme
+		case provided != "":
+			stored = provided
+		default:
+			stored = HashScheme
+		}
+		WriteStateScheme(db, stored)
+	}
+	if provided != "" && provided != stored {
+		return "", fmt.Errorf("incompatible state scheme, stored: %s, provided: %s", stored, provided)
+	}
+	return stored, nil
+}
+
+// ReadPersistentStateID retrieves the id of the state persisted by the path
+// scheme, zero if none was persisted yet.
+func ReadPersistentStateID(db ethdb.KeyValueReader) uint64 {
+	data, _ := db.Get(persistentStateIDKey)
+	if len(data) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+// WritePersistentStateID stores the id of the state persisted by the path scheme.
+func WritePersistentStateID(db ethdb.KeyValueWriter, id uint64) {
+	if err := db.Put(persistentStateIDKey, encodeBlockNumber(id)); err != nil {
+		log.Crit("Failed to store persistent state id", "err", err)
+	}
+}
+
+// ReadStateID retrieves the id of the state with the given root.
+func ReadStateID(db ethdb.KeyValueReader, root common.Hash) *uint64 {
+	data, _ := db.Get(stateIDKey(root))
+	if len(data) != 8 {
+		return nil
+	}
+	id := binary.BigEndian.Uint64(data)
+	return &id
+}
+
+// WriteStateID stores the id of the state with the given root.
+func WriteStateID(db ethdb.KeyValueWriter, root common.Hash, id uint64) {
+	if err := db.Put(stateIDKey(root), encodeBlockNumber(id)); err != nil {
+		log.Crit("Failed to store state id", "err", err)
+	}
+}
+
+// DeleteStateID deletes the id of the state with the given root.
+func DeleteStateID(db ethdb.KeyValueWriter, root common.Hash) {
+	if err := db.Delete(stateIDKey(root)); err != nil {
+		log.Crit("Failed to delete state id", "err", err)
+	}
+}
+
+// ReadStateHistory retrieves the RLP encoded state history with the given id,
+// reverting the state with that id into its parent.
+func ReadStateHistory(db ethdb.KeyValueReader, id uint64) []byte {
+	data, _ := db.Get(stateHistoryKey(id))
+	return data
+}
+
+// WriteStateHistory stores the RLP encoded state history with the given id.
+func WriteStateHistory(db ethdb.KeyValueWriter, id uint64, history []byte) {
+	if err := db.Put(stateHistoryKey(id), history); err != nil {
+		log.Crit("Failed to store state history", "err", err)
+	}
+}
+
+// DeleteStateHistory deletes the state history with the given id.
+func DeleteStateHistory(db ethdb.KeyValueWriter, id uint64) {
+	if err := db.Delete(stateHistoryKey(id)); err != nil {
+		log.Crit("Failed to delete state history", "err", err)
+	}
+}
+
+// ReadStateHistoryTail retrieves the id of the oldest retained state history.
+func ReadStateHistoryTail(db ethdb.KeyValueReader) uint64 {
+	data, _ := db.Get(stateHistoryTailKey)
+	if len(data) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+// WriteStateHistoryTail stores the id of the oldest retained state history.
+func WriteStateHistoryTail(db ethdb.KeyValueWriter, id uint64) {
+	if err := db.Put(stateHistoryTailKey, encodeBlockNumber(id)); err != nil {
+		log.Crit("Failed to store state history tail", "err", err)
+	}
+}
+
+// ReadTrieJournal retrieves the serialized in-memory trie node layers saved at
+// the last shutdown.
+func ReadTrieJournal(db ethdb.KeyValueReader) []byte {
+	data, _ := db.Get(trieJournalKey)
+	return data
+}
+
+// WriteTrieJournal stores the serialized in-memory trie node layers to survive
+// a restart.
+func WriteTrieJournal(db ethdb.KeyValueWriter, journal []byte) {
+	if err := db.Put(trieJournalKey, journal); err != nil {
+		log.Crit("Failed to store trie journal", "err", err)
+	}
+}
+
+// DeleteTrieJournal deletes the serialized in-memory trie node layers.
+func DeleteTrieJournal(db ethdb.KeyValueWriter) {
+	if err := db.Delete(trieJournalKey); err != nil {
+		log.Crit("Failed to remove trie journal", "err", err)
+	}
+}
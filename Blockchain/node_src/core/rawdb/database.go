@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sync/atomic"
 	"time"
 
@@ -89,6 +90,11 @@ func (db *nofreezedb) AncientSize(kind string) (uint64, error) {
 	return 0, errNotSupported
 }
 
+// AncientTail returns an error as we don't have a backing chain freezer.
+func (db *nofreezedb) AncientTail(kind string) (uint64, error) {
+	return 0, errNotSupported
+}
+
 // ModifyAncients is not supported.
 func (db *nofreezedb) ModifyAncients(func(ethdb.AncientWriteOp) error) (int64, error) {
 	return 0, errNotSupported
@@ -99,6 +105,16 @@ func (db *nofreezedb) TruncateAncients(items uint64) error {
 	return errNotSupported
 }
 
+// TruncateAncientTail returns an error as we don't have a backing chain freezer.
+func (db *nofreezedb) TruncateAncientTail(kind string, tail uint64) error {
+	return errNotSupported
+}
+
+// ResetAncients returns an error as we don't have a backing chain freezer.
+func (db *nofreezedb) ResetAncients(tail uint64) error {
+	return errNotSupported
+}
+
 // Sync returns an error as we don't have a backing chain freezer.
 func (db *nofreezedb) Sync() error {
 	return errNotSupported
@@ -155,12 +171,16 @@ func NewDatabaseWithFreezer(db ethdb.KeyValueStore, freezer string, namespace st
 	if kvgenesis, _ := db.Get(headerHashKey(0)); len(kvgenesis) > 0 {
 		if frozen, _ := frdb.Ancients(); frozen > 0 {
 			// If the freezer already contains something, ensure that the genesis blocks
-			// match, otherwise we might mix up freezers across chains and destroy both
-			frgenesis, err := frdb.Ancient(freezerHashTable, 0)
-			if err != nil {
-				return nil, fmt.Errorf("failed to retrieve genesis from ancient %v", err)
-			} else if !bytes.Equal(kvgenesis, frgenesis) {
-				return nil, fmt.Errorf("genesis mismatch: %#x (leveldb) != %#x (ancients)", kvgenesis, frgenesis)
+			// match, otherwise we might mix up freezers across chains and destroy both.
+			// Freezers started above genesis (chain imported from a state file) can't
+			// be cross checked.
+			if tail, _ := frdb.AncientTail(freezerHashTable); tail == 0 {
+				frgenesis, err := frdb.Ancient(freezerHashTable, 0)
+				if err != nil {
+					return nil, fmt.Errorf("failed to retrieve genesis from ancient %v", err)
+				} else if !bytes.Equal(kvgenesis, frgenesis) {
+					return nil, fmt.Errorf("genesis mismatch: %#x (leveldb) != %#x (ancients)", kvgenesis, frgenesis)
+				}
 			}
 			// Key-value store and freezer belong to the same network. Ensure that they
 			// are contiguous, otherwise we might end up with a non-functional freezer.
@@ -245,6 +265,114 @@ func NewLevelDBDatabaseWithFreezer(file string, cache int, handles int, freezer
 	return frdb, nil
 }
 
+const (
+	DBPebble  = "pebble"
+	DBLeveldb = "leveldb"
+)
+
+// PreexistingDatabase checks the given data directory whether a database is
+// already instantiated at that location, and if so, returns the type of the
+// database (or the empty string).
+func PreexistingDatabase(path string) string {
+	if _, err := os.Stat(filepath.Join(path, "CURRENT")); err != nil {
+		return "" // No pre-existing db
+	}
+	if matches, err := filepath.Glob(filepath.Join(path, "OPTIONS*")); len(matches) > 0 || err != nil {
+		if err != nil {
+			panic(err) // only possible if the pattern is malformed
+		}
+		return DBPebble
+	}
+	return DBLeveldb
+}
+
+// OpenOptions contains the options to apply when opening a database.
+type OpenOptions struct {
+	Type              string // "leveldb" | "pebble"
+	Directory         string // the datadir
+	AncientsDirectory string // the ancients-dir
+	Namespace         string // the namespace for database relevant metrics
+	Cache             int    // the capacity(in megabytes) of the data caching
+	Handles           int    // number of files to be open simultaneously
+	ReadOnly          bool
+}
+
+// openKeyValueDatabase opens a disk-based key-value database, e.g. leveldb or
+// pebble. The engine is picked by the pre-existing database if there is one,
+// falling back to the requested type and finally to leveldb.
+func openKeyValueDatabase(o OpenOptions) (ethdb.KeyValueStore, string, error) {
+	existing := PreexistingDatabase(o.Directory)
+	if existing != "" && o.Type != "" && o.Type != existing {
+		return nil, "", fmt.Errorf("db.engine choice was %v but found pre-existing %v database in specified data directory", o.Type, existing)
+	}
+	engine := existing
+	if engine == "" {
+		engine = o.Type
+	}
+	switch engine {
+	case DBPebble:
+		log.Info("Using pebble as the backing database")
+		db, err := newPebbleDBDatabase(o.Directory, o.Cache, o.Handles, o.Namespace, o.ReadOnly)
+		return db, DBPebble, err
+	case DBLeveldb, "":
+		log.Info("Using leveldb as the backing database")
+		db, err := leveldb.New(o.Directory, o.Cache, o.Handles, o.Namespace, o.ReadOnly)
+		return db, DBLeveldb, err
+	default:
+		return nil, "", fmt.Errorf("unknown db.engine %v", engine)
+	}
+}
+
+// checkDatabaseEngine verifies that the engine recorded in the database matches
+// the one used to open it, recording it for databases without the marker.
+func checkDatabaseEngine(db ethdb.KeyValueStore, engine string, readonly bool) error {
+	stored := ReadDatabaseEngine(db)
+	if stored == "" {
+		if !readonly {
+			WriteDatabaseEngine(db, engine)
+		}
+		return nil
+	}
+	if stored != engine {
+		return fmt.Errorf("database was created with %v but opened with %v", stored, engine)
+	}
+	return nil
+}
+
+// OpenKeyValueStore opens a disk-based key-value database such as leveldb or
+// pebble without a freezer, ensuring it's opened with the engine it was
+// created with.
+func OpenKeyValueStore(o OpenOptions) (ethdb.KeyValueStore, error) {
+	kvdb, engine, err := openKeyValueDatabase(o)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDatabaseEngine(kvdb, engine, o.ReadOnly); err != nil {
+		kvdb.Close()
+		return nil, err
+	}
+	return kvdb, nil
+}
+
+// Open opens a disk-based key-value database such as leveldb or pebble, and
+// integrates it with a freezer database if the AncientsDirectory option has
+// been set on the provided OpenOptions.
+func Open(o OpenOptions) (ethdb.Database, error) {
+	kvdb, err := OpenKeyValueStore(o)
+	if err != nil {
+		return nil, err
+	}
+	if len(o.AncientsDirectory) == 0 {
+		return NewDatabase(kvdb), nil
+	}
+	frdb, err := NewDatabaseWithFreezer(kvdb, o.AncientsDirectory, o.Namespace, o.ReadOnly)
+	if err != nil {
+		kvdb.Close()
+		return nil, err
+	}
+	return frdb, nil
+}
+
 type counter uint64
 
 func (c counter) String() string {
@@ -293,6 +421,9 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		numHashPairings stat
 		hashNumPairings stat
 		tries           stat
+		pathTries       stat
+		stateHistories  stat
+		stateIndex      stat
 		codes           stat
 		txLookups       stat
 		accountSnaps    stat
@@ -342,6 +473,22 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 			hashNumPairings.Add(size)
 		case len(key) == common.HashLength:
 			tries.Add(size)
+		case bytes.HasPrefix(key, trieNodeAccountPrefix) && len(key) <= len(trieNodeAccountPrefix)+2*common.HashLength:
+			pathTries.Add(size)
+		case bytes.HasPrefix(key, trieNodeStoragePrefix) && len(key) >= len(trieNodeStoragePrefix)+common.HashLength:
+			pathTries.Add(size)
+		case bytes.HasPrefix(key, stateHistoryPrefix) && len(key) == len(stateHistoryPrefix)+8:
+			stateHistories.Add(size)
+		case bytes.HasPrefix(key, stateIDPrefix) && len(key) == len(stateIDPrefix)+common.HashLength:
+			stateHistories.Add(size)
+		case bytes.HasPrefix(key, stateChangesPrefix) && len(key) == len(stateChangesPrefix)+8+common.HashLength:
+			stateIndex.Add(size)
+		case bytes.HasPrefix(key, stateIndexBlockPrefix) && len(key) == len(stateIndexBlockPrefix)+8:
+			stateIndex.Add(size)
+		case bytes.HasPrefix(key, stateIndexAccountPrefix) && len(key) == len(stateIndexAccountPrefix)+common.HashLength+8:
+			stateIndex.Add(size)
+		case bytes.HasPrefix(key, stateIndexStoragePrefix) && len(key) == len(stateIndexStoragePrefix)+2*common.HashLength+8:
+			stateIndex.Add(size)
 		case bytes.HasPrefix(key, CodePrefix) && len(key) == len(CodePrefix)+common.HashLength:
 			codes.Add(size)
 		case bytes.HasPrefix(key, txLookupPrefix) && len(key) == (len(txLookupPrefix)+common.HashLength):
@@ -373,10 +520,11 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		default:
 			var accounted bool
 			for _, meta := range [][]byte{
-				databaseVersionKey, headHeaderKey, headBlockKey, headFastBlockKey, lastPivotKey,
+				databaseVersionKey, databaseEngineKey, databaseMigrationKey, headHeaderKey, headBlockKey, headFastBlockKey, lastPivotKey,
 				fastTrieProgressKey, snapshotDisabledKey, SnapshotRootKey, snapshotJournalKey,
 				snapshotGeneratorKey, snapshotRecoveryKey, txIndexTailKey, fastTxLookupLimitKey,
-				uncleanShutdownKey, badBlockKey,
+				uncleanShutdownKey, badBlockKey, stateSchemeKey, persistentStateIDKey,
+				stateHistoryTailKey, trieJournalKey, stateIndexHeadKey, stateIndexTailKey,
 			} {
 				if bytes.Equal(key, meta) {
 					metadata.Add(size)
@@ -419,6 +567,9 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		{"Key-Value store", "Bloombit index", bloomBits.Size(), bloomBits.Count()},
 		{"Key-Value store", "Contract codes", codes.Size(), codes.Count()},
 		{"Key-Value store", "Trie nodes", tries.Size(), tries.Count()},
+		{"Key-Value store", "Path trie nodes", pathTries.Size(), pathTries.Count()},
+		{"Key-Value store", "State histories", stateHistories.Size(), stateHistories.Count()},
+		{"Key-Value store", "State index", stateIndex.Size(), stateIndex.Count()},
 		{"Key-Value store", "Trie preimages", preimages.Size(), preimages.Count()},
 		{"Key-Value store", "Account snapshot", accountSnaps.Size(), accountSnaps.Count()},
 		{"Key-Value store", "Storage snapshot", storageSnaps.Size(), storageSnaps.Count()},
@@ -1,3 +1,35 @@
 // Copyright 2025 Silver Bitcoin Foundation
 
 package rawdb
+
+import (
+	"testing"
+)
+
+// Tests that a database refuses to be opened with an engine other than the
+// one it was created with.
+func TestOpenDatabaseEngine(t *testing.T) {
+	dir := t.TempDir()
+
+	db, err := Open(OpenOptions{Type: DBLeveldb, Directory: dir})
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	if engine := ReadDatabaseEngine(db); engine != DBLeveldb {
+		t.Fatalf("engine mismatch: have %q, want %q", engine, DBLeveldb)
+	}
+	db.Close()
+
+	if engine := PreexistingDatabase(dir); engine != DBLeveldb {
+		t.Fatalf("preexisting engine mismatch: have %q, want %q", engine, DBLeveldb)
+	}
+	if _, err := Open(OpenOptions{Type: DBPebble, Directory: dir}); err == nil {
+		t.Fatal("opened leveldb database with pebble")
+	}
+	// Reopening without an explicit choice picks the existing engine
+	db, err = Open(OpenOptions{Directory: dir})
+	if err != nil {
+		t.Fatalf("failed to reopen database: %v", err)
+	}
+	db.Close()
+}
@@ -215,6 +215,15 @@ func (f *freezer) AncientSize(kind string) (uint64, error) {
 	return 0, errUnknownTable
 }
 
+// AncientTail returns the number of the first item retained in the specified
+// category.
+func (f *freezer) AncientTail(kind string) (uint64, error) {
+	if table := f.tables[kind]; table != nil {
+		return table.tail(), nil
+	}
+	return 0, errUnknownTable
+}
+
 // ReadAncients runs the given read operation while ensuring that no writes take place
 // on the underlying freezer.
 func (f *freezer) ReadAncients(fn func(ethdb.AncientReader) error) (err error) {
@@ -277,6 +286,45 @@ func (f *freezer) TruncateAncients(items uint64) error {
 	return nil
 }
 
+// TruncateAncientTail discards the items below the given number from the
+// specified category. Only the frozen items can be discarded, the tables have
+// to keep their length in sync.
+func (f *freezer) TruncateAncientTail(kind string, tail uint64) error {
+	if f.readonly {
+		return errReadOnly
+	}
+	f.writeLock.Lock()
+	defer f.writeLock.Unlock()
+
+	table := f.tables[kind]
+	if table == nil {
+		return errUnknownTable
+	}
+	if frozen := atomic.LoadUint64(&f.frozen); tail > frozen {
+		tail = frozen
+	}
+	return table.truncateTail(tail)
+}
+
+// ResetAncients discards all the ancient data and restarts the empty tables at
+// the given item number, so a chain whose history starts above genesis can be
+// frozen from there on.
+func (f *freezer) ResetAncients(tail uint64) error {
+	if f.readonly {
+		return errReadOnly
+	}
+	f.writeLock.Lock()
+	defer f.writeLock.Unlock()
+
+	for _, table := range f.tables {
+		if err := table.resetTo(tail); err != nil {
+			return err
+		}
+	}
+	atomic.StoreUint64(&f.frozen, tail)
+	return nil
+}
+
 // Sync flushes all data tables to disk.
 func (f *freezer) Sync() error {
 	var errs []error
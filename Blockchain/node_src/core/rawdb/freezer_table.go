@@ -8,8 +8,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"sync/atomic"
 
@@ -245,8 +247,13 @@ func (t *freezerTable) repair() error {
 	}
 	contentSize = stat.Size()
 
-	// Keep truncating both files until they come in sync
+	// Keep truncating both files until they come in sync. The first index entry
+	// carries the tail metadata instead of a data offset, if it's the only one
+	// the head file is empty.
 	contentExp = int64(lastIndex.offset)
+	if offsetsSize == indexEntrySize {
+		contentExp = 0
+	}
 
 	for contentExp != contentSize {
 		// Truncate the head file to the last offset pointer
@@ -283,6 +290,9 @@ func (t *freezerTable) repair() error {
 			}
 			lastIndex = newLastIndex
 			contentExp = int64(lastIndex.offset)
+			if offsetsSize == indexEntrySize {
+				contentExp = 0
+			}
 		}
 	}
 	// Ensure all reparation changes have been written to disk
@@ -343,17 +353,25 @@ func (t *freezerTable) truncate(items uint64) error {
 	if existing > items+1 {
 		log = t.logger.Warn // Only loud warn if we delete multiple items
 	}
+	if items < uint64(t.itemOffset) {
+		return fmt.Errorf("truncating below the table tail, items %d, tail %d", items, t.itemOffset)
+	}
 	log("Truncating freezer table", "items", existing, "limit", items)
-	if err := truncateFreezerFile(t.index, int64(items+1)*indexEntrySize); err != nil {
+	position := items - uint64(t.itemOffset)
+	if err := truncateFreezerFile(t.index, int64(position+1)*indexEntrySize); err != nil {
 		return err
 	}
-	// Calculate the new expected size of the data file and truncate it
-	buffer := make([]byte, indexEntrySize)
-	if _, err := t.index.ReadAt(buffer, int64(items*indexEntrySize)); err != nil {
-		return err
+	// Calculate the new expected size of the data file and truncate it. The
+	// first index entry carries the tail metadata, truncating down to the tail
+	// empties the tail file.
+	expected := indexEntry{filenum: t.tailId}
+	if position > 0 {
+		buffer := make([]byte, indexEntrySize)
+		if _, err := t.index.ReadAt(buffer, int64(position*indexEntrySize)); err != nil {
+			return err
+		}
+		expected.unmarshalBinary(buffer)
 	}
-	var expected indexEntry
-	expected.unmarshalBinary(buffer)
 
 	// We might need to truncate back to older files
 	if expected.filenum != t.headId {
@@ -387,6 +405,180 @@ func (t *freezerTable) truncate(items uint64) error {
 	return nil
 }
 
+// tail returns the number of the first item retained in the table.
+func (t *freezerTable) tail() uint64 {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return uint64(t.itemOffset)
+}
+
+// truncateTail discards the data files which only contain items below the
+// provided threshold number. The first retained item is the first one of its
+// data file, so some items below the threshold might be retained.
+func (t *freezerTable) truncateTail(tail uint64) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	items := atomic.LoadUint64(&t.items)
+	if tail > items {
+		tail = items
+	}
+	if tail <= uint64(t.itemOffset) {
+		return nil
+	}
+	buffer := make([]byte, indexEntrySize)
+	readEntry := func(position uint64) (indexEntry, error) {
+		var entry indexEntry
+		if _, err := t.index.ReadAt(buffer, int64(position*indexEntrySize)); err != nil {
+			return entry, err
+		}
+		entry.unmarshalBinary(buffer)
+		return entry, nil
+	}
+	// Locate the data file holding the new tail item, all the files before it
+	// can be dropped.
+	fileId := t.headId
+	if tail < items {
+		entry, err := readEntry(tail - uint64(t.itemOffset) + 1)
+		if err != nil {
+			return err
+		}
+		fileId = entry.filenum
+	}
+	if fileId == t.tailId {
+		return nil
+	}
+	// Find the first item stored in the new tail file, the file numbers of the
+	// index entries are monotonic.
+	var (
+		count     = int(items - uint64(t.itemOffset))
+		searchErr error
+	)
+	position := sort.Search(count, func(i int) bool {
+		entry, err := readEntry(uint64(i) + 1)
+		if err != nil {
+			searchErr = err
+			return true
+		}
+		return entry.filenum >= fileId
+	})
+	if searchErr != nil {
+		return searchErr
+	}
+	oldSize, err := t.sizeNolock()
+	if err != nil {
+		return err
+	}
+	newOffset := uint64(t.itemOffset) + uint64(position)
+	t.logger.Info("Truncating freezer table tail", "items", items, "tail", t.itemOffset, "limit", newOffset)
+
+	// Rewrite the index with the new tail metadata as the first entry followed
+	// by the retained entries, and swap it in atomically.
+	stat, err := t.index.Stat()
+	if err != nil {
+		return err
+	}
+	name := t.index.Name()
+	tmp, err := openFreezerFileTruncated(name + ".tmp")
+	if err != nil {
+		return err
+	}
+	meta := indexEntry{filenum: fileId, offset: uint32(newOffset)}
+	if _, err := tmp.Write(meta.append(nil)); err != nil {
+		tmp.Close()
+		return err
+	}
+	start := int64(position+1) * indexEntrySize
+	if _, err := io.Copy(tmp, io.NewSectionReader(t.index, start, stat.Size()-start)); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := t.index.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(name+".tmp", name); err != nil {
+		return err
+	}
+	if t.index, err = openFreezerFileForAppend(name); err != nil {
+		return err
+	}
+	// The index doesn't reference the old data files anymore, delete them
+	for i := t.tailId; i < fileId; i++ {
+		t.releaseFile(i)
+		if err := os.Remove(filepath.Join(t.path, t.fileName(i))); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	t.tailId = fileId
+	t.itemOffset = uint32(newOffset)
+
+	newSize, err := t.sizeNolock()
+	if err != nil {
+		return err
+	}
+	t.sizeGauge.Dec(int64(oldSize - newSize))
+	return nil
+}
+
+// resetTo discards all the items of the table and restarts it empty at the
+// given item number, storing the upcoming items in a fresh data file.
+func (t *freezerTable) resetTo(tail uint64) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if tail > math.MaxUint32 {
+		return fmt.Errorf("freezer table tail %d out of range", tail)
+	}
+	oldSize, err := t.sizeNolock()
+	if err != nil {
+		return err
+	}
+	t.logger.Info("Resetting freezer table", "items", atomic.LoadUint64(&t.items), "tail", tail)
+
+	// Point the index to the not yet existing next data file first, the repair
+	// on startup creates it if the reset is interrupted.
+	nextId := t.headId + 1
+	if err := truncateFreezerFile(t.index, 0); err != nil {
+		return err
+	}
+	meta := indexEntry{filenum: nextId, offset: uint32(tail)}
+	if _, err := t.index.Write(meta.append(nil)); err != nil {
+		return err
+	}
+	if err := t.index.Sync(); err != nil {
+		return err
+	}
+	head, err := t.openFile(nextId, openFreezerFileTruncated)
+	if err != nil {
+		return err
+	}
+	for i := t.tailId; i <= t.headId; i++ {
+		t.releaseFile(i)
+		if err := os.Remove(filepath.Join(t.path, t.fileName(i))); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	t.head, t.headBytes = head, 0
+	t.headId, t.tailId = nextId, nextId
+	t.itemOffset = uint32(tail)
+	atomic.StoreUint64(&t.items, tail)
+
+	newSize, err := t.sizeNolock()
+	if err != nil {
+		return err
+	}
+	t.sizeGauge.Dec(int64(oldSize - newSize))
+	return nil
+}
+
 // Close closes all opened files.
 func (t *freezerTable) Close() error {
 	t.lock.Lock()
@@ -415,13 +607,7 @@ func (t *freezerTable) Close() error {
 func (t *freezerTable) openFile(num uint32, opener func(string) (*os.File, error)) (f *os.File, err error) {
 	var exist bool
 	if f, exist = t.files[num]; !exist {
-		var name string
-		if t.noCompression {
-			name = fmt.Sprintf("%s.%04d.rdat", t.name, num)
-		} else {
-			name = fmt.Sprintf("%s.%04d.cdat", t.name, num)
-		}
-		f, err = opener(filepath.Join(t.path, name))
+		f, err = opener(filepath.Join(t.path, t.fileName(num)))
 		if err != nil {
 			return nil, err
 		}
@@ -430,6 +616,14 @@ func (t *freezerTable) openFile(num uint32, opener func(string) (*os.File, error
 	return f, err
 }
 
+// fileName returns the name of the data file with the given number.
+func (t *freezerTable) fileName(num uint32) string {
+	if t.noCompression {
+		return fmt.Sprintf("%s.%04d.rdat", t.name, num)
+	}
+	return fmt.Sprintf("%s.%04d.cdat", t.name, num)
+}
+
 // releaseFile closes a file, and removes it from the open file cache.
 // Assumes that the caller holds the write lock
 func (t *freezerTable) releaseFile(num uint32) {
@@ -632,7 +826,10 @@ func (t *freezerTable) retrieveItems(start, count, maxBytes uint64) ([]byte, []i
 // has returns an indicator whether the specified number data
 // exists in the freezer table.
 func (t *freezerTable) has(number uint64) bool {
-	return atomic.LoadUint64(&t.items) > number
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return atomic.LoadUint64(&t.items) > number && uint64(t.itemOffset) <= number
 }
 
 // size returns the total data size in the freezer table.
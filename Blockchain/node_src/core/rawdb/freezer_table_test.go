@@ -643,6 +643,127 @@ func TestFreezerOffset(t *testing.T) {
 	}
 }
 
+// TestFreezerTruncateTail tests discarding the data files from the tail of the
+// table, both in memory and across reopening it.
+func TestFreezerTruncateTail(t *testing.T) {
+	t.Parallel()
+	rm, wm, sg := metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge()
+	fname := fmt.Sprintf("truncate-tail-%d", rand.Uint64())
+
+	// Write 30 items of 15 bytes, 3 items per data file
+	f, err := newTable(os.TempDir(), fname, rm, wm, sg, 50, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeChunks(t, f, 30, 15)
+
+	// Item 7 lives in the third data file, which starts with item 6
+	if err := f.truncateTail(7); err != nil {
+		t.Fatal(err)
+	}
+	if tail := f.tail(); tail != 6 {
+		t.Fatalf("tail mismatch: have %d, want %d", tail, 6)
+	}
+	checkRetrieveError(t, f, map[uint64]error{0: errOutOfBounds, 5: errOutOfBounds, 30: errOutOfBounds})
+	checkRetrieve(t, f, map[uint64][]byte{6: getChunk(15, 6), 7: getChunk(15, 7), 29: getChunk(15, 29)})
+	for i := uint32(0); i < 2; i++ {
+		if _, err := os.Stat(filepath.Join(os.TempDir(), f.fileName(i))); !os.IsNotExist(err) {
+			t.Fatalf("data file %d not deleted: %v", i, err)
+		}
+	}
+	if f.has(5) || !f.has(6) {
+		t.Fatalf("item availability mismatch")
+	}
+	// Truncating within the tail file is a noop
+	if err := f.truncateTail(8); err != nil {
+		t.Fatal(err)
+	}
+	if tail := f.tail(); tail != 6 {
+		t.Fatalf("tail mismatch: have %d, want %d", tail, 6)
+	}
+	f.Close()
+
+	// Reopen the table and check the tail survived, then continue writing
+	f, err = newTable(os.TempDir(), fname, rm, wm, sg, 50, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if tail := f.tail(); tail != 6 {
+		t.Fatalf("tail mismatch after reopen: have %d, want %d", tail, 6)
+	}
+	if items := f.items; items != 30 {
+		t.Fatalf("item count mismatch after reopen: have %d, want %d", items, 30)
+	}
+	batch := f.newBatch()
+	require.NoError(t, batch.AppendRaw(30, getChunk(15, 30)))
+	require.NoError(t, batch.commit())
+	checkRetrieve(t, f, map[uint64][]byte{6: getChunk(15, 6), 30: getChunk(15, 30)})
+
+	// Truncate the head down to the tail and below it
+	if err := f.truncate(10); err != nil {
+		t.Fatal(err)
+	}
+	checkRetrieve(t, f, map[uint64][]byte{6: getChunk(15, 6), 9: getChunk(15, 9)})
+	checkRetrieveError(t, f, map[uint64]error{10: errOutOfBounds})
+
+	if err := f.truncate(6); err != nil {
+		t.Fatal(err)
+	}
+	checkRetrieveError(t, f, map[uint64]error{6: errOutOfBounds})
+	if err := f.truncate(5); err == nil {
+		t.Fatalf("truncated below the tail")
+	}
+	batch = f.newBatch()
+	require.NoError(t, batch.AppendRaw(6, getChunk(15, 0xaa)))
+	require.NoError(t, batch.commit())
+	checkRetrieve(t, f, map[uint64][]byte{6: getChunk(15, 0xaa)})
+}
+
+// Tests that a table can be restarted empty at an arbitrary item, and that the
+// reset survives a reopen.
+func TestFreezerResetTo(t *testing.T) {
+	t.Parallel()
+	rm, wm, sg := metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge()
+	fname := fmt.Sprintf("reset-%d", rand.Uint64())
+
+	f, err := newTable(os.TempDir(), fname, rm, wm, sg, 50, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeChunks(t, f, 10, 15)
+	if err := f.resetTo(100); err != nil {
+		t.Fatal(err)
+	}
+	if tail, items := f.tail(), f.items; tail != 100 || items != 100 {
+		t.Fatalf("range mismatch: have [%d, %d), want [100, 100)", tail, items)
+	}
+	checkRetrieveError(t, f, map[uint64]error{0: errOutOfBounds, 9: errOutOfBounds, 100: errOutOfBounds})
+	f.Close()
+
+	// The empty table is opened at the reset position
+	f, err = newTable(os.TempDir(), fname, rm, wm, sg, 50, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if tail, items := f.tail(), f.items; tail != 100 || items != 100 {
+		t.Fatalf("range mismatch after reopen: have [%d, %d), want [100, 100)", tail, items)
+	}
+	batch := f.newBatch()
+	require.Error(t, batch.AppendRaw(10, getChunk(15, 10)))
+
+	batch = f.newBatch()
+	for i := 0; i < 5; i++ {
+		require.NoError(t, batch.AppendRaw(uint64(100+i), getChunk(15, 100+i)))
+	}
+	require.NoError(t, batch.commit())
+	checkRetrieve(t, f, map[uint64][]byte{100: getChunk(15, 100), 104: getChunk(15, 104)})
+	checkRetrieveError(t, f, map[uint64]error{99: errOutOfBounds, 105: errOutOfBounds})
+}
+
 func checkRetrieve(t *testing.T, f *freezerTable, items map[uint64][]byte) {
 	t.Helper()
 
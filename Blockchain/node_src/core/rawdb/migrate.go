@@ -0,0 +1,212 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package rawdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// errMigrationInterrupted is returned if a migration is aborted, it can be
+// resumed by running it again on the same destination.
+var errMigrationInterrupted = errors.New("migration interrupted")
+
+const (
+	migrationRunning = byte(0) // Progress marker prefix of an unfinished migration
+	migrationDone    = byte(1) // Progress marker of a finished migration
+)
+
+// isMigrationMetaKey reports whether the key holds engine specific metadata,
+// which is neither copied nor verified during a migration.
+func isMigrationMetaKey(key []byte) bool {
+	return bytes.Equal(key, databaseEngineKey) || bytes.Equal(key, databaseMigrationKey)
+}
+
+// MigrateDatabase copies all the key-value pairs of src into dst, tracking the
+// progress in dst so that an interrupted migration is resumed from the last
+// written batch.
+func MigrateDatabase(src ethdb.Iteratee, dst ethdb.KeyValueStore, stop <-chan struct{}) error {
+	var start []byte
+	if marker, _ := dst.Get(databaseMigrationKey); len(marker) > 0 {
+		if marker[0] == migrationDone {
+			log.Info("Database already migrated")
+			return nil
+		}
+		start = common.CopyBytes(marker[1:])
+		log.Info("Resuming database migration", "key", fmt.Sprintf("%#x", start))
+	}
+	var (
+		it      = src.NewIterator(nil, start)
+		batch   = dst.NewBatch()
+		count   uint64
+		size    common.StorageSize
+		begin   = time.Now()
+		logged  = time.Now()
+		lastKey []byte
+	)
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		if isMigrationMetaKey(key) {
+			continue
+		}
+		if err := batch.Put(key, it.Value()); err != nil {
+			return err
+		}
+		count++
+		size += common.StorageSize(len(key) + len(it.Value()))
+		lastKey = append(lastKey[:0], key...)
+
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Put(databaseMigrationKey, append([]byte{migrationRunning}, lastKey...)); err != nil {
+				return err
+			}
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+
+			select {
+			case <-stop:
+				log.Info("Database migration interrupted", "count", count, "size", size, "elapsed", common.PrettyDuration(time.Since(begin)))
+				return errMigrationInterrupted
+			default:
+			}
+			if time.Since(logged) > 8*time.Second {
+				log.Info("Migrating database", "count", count, "size", size, "key", fmt.Sprintf("%#x", lastKey), "elapsed", common.PrettyDuration(time.Since(begin)))
+				logged = time.Now()
+			}
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := batch.Put(databaseMigrationKey, []byte{migrationDone}); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	log.Info("Migrated database", "count", count, "size", size, "elapsed", common.PrettyDuration(time.Since(begin)))
+	return nil
+}
+
+// VerifyMigration checks that dst holds as many entries as src, comparing the
+// value of every sampleRate-th entry.
+func VerifyMigration(src ethdb.Iteratee, dst ethdb.KeyValueStore, sampleRate uint64) error {
+	if sampleRate == 0 {
+		sampleRate = 1
+	}
+	var (
+		srcCount, dstCount uint64
+		sampled            uint64
+		begin              = time.Now()
+		logged             = time.Now()
+	)
+	it := src.NewIterator(nil, nil)
+	for it.Next() {
+		if isMigrationMetaKey(it.Key()) {
+			continue
+		}
+		srcCount++
+		if srcCount%sampleRate == 0 {
+			val, err := dst.Get(it.Key())
+			if err != nil {
+				it.Release()
+				return fmt.Errorf("key %#x missing from destination: %v", it.Key(), err)
+			}
+			if !bytes.Equal(val, it.Value()) {
+				it.Release()
+				return fmt.Errorf("value mismatch for key %#x", it.Key())
+			}
+			sampled++
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Verifying migrated database", "count", srcCount, "sampled", sampled, "elapsed", common.PrettyDuration(time.Since(begin)))
+			logged = time.Now()
+		}
+	}
+	it.Release()
+	if err := it.Error(); err != nil {
+		return err
+	}
+	it = dst.NewIterator(nil, nil)
+	for it.Next() {
+		if !isMigrationMetaKey(it.Key()) {
+			dstCount++
+		}
+	}
+	it.Release()
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if srcCount != dstCount {
+		return fmt.Errorf("entry count mismatch: source %d, destination %d", srcCount, dstCount)
+	}
+	log.Info("Verified migrated database", "count", srcCount, "sampled", sampled, "elapsed", common.PrettyDuration(time.Since(begin)))
+	return nil
+}
+
+// CopyFreezer copies the files of an ancient store into another directory.
+// Files already present with the same size are skipped, so an interrupted
+// copy can be resumed.
+func CopyFreezer(src, dst string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, entry.Name())
+		if stat, err := os.Stat(target); err == nil && stat.Size() == info.Size() {
+			continue
+		}
+		if err := copyFile(filepath.Join(src, entry.Name()), target); err != nil {
+			return err
+		}
+		log.Info("Copied ancient file", "name", entry.Name(), "size", common.StorageSize(info.Size()))
+	}
+	return nil
+}
+
+// copyFile copies a single file, syncing it to disk.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
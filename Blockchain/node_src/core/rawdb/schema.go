@@ -61,6 +61,31 @@ var (
 	// uncleanShutdownKey tracks the list of local crashes
 	uncleanShutdownKey = []byte("unclean-shutdown") // config prefix for the db
 
+	// databaseEngineKey tracks the key-value engine the database was created with.
+	databaseEngineKey = []byte("DatabaseEngine")
+
+	// databaseMigrationKey tracks the progress of migrating a database into
+	// another key-value engine.
+	databaseMigrationKey = []byte("DatabaseMigration")
+
+	// stateSchemeKey tracks the storage scheme of the trie nodes.
+	stateSchemeKey = []byte("StateScheme")
+
+	// persistentStateIDKey tracks the id of the state persisted by the path scheme.
+	persistentStateIDKey = []byte("LastStateID")
+
+	// stateHistoryTailKey tracks the id of the oldest retained state history.
+	stateHistoryTailKey = []byte("StateHistoryTail")
+
+	// trieJournalKey tracks the in-memory trie node layers across restarts.
+	trieJournalKey = []byte("TrieJournal")
+
+	// stateIndexHeadKey tracks the number of the latest block in the state index.
+	stateIndexHeadKey = []byte("StateIndexHead")
+
+	// stateIndexTailKey tracks the number of the oldest block in the state index.
+	stateIndexTailKey = []byte("StateIndexTail")
+
 	// Data item prefixes (use single byte to avoid mixing data types, avoid `i`, used for indexes).
 	headerPrefix       = []byte("h") // headerPrefix + num (uint64 big endian) + hash -> header
 	headerTDSuffix     = []byte("t") // headerPrefix + num (uint64 big endian) + hash + headerTDSuffix -> td
@@ -76,6 +101,16 @@ var (
 	SnapshotStoragePrefix = []byte("o") // SnapshotStoragePrefix + account hash + storage hash -> storage trie value
 	CodePrefix            = []byte("c") // CodePrefix + code hash -> account code
 
+	trieNodeAccountPrefix = []byte("A")  // trieNodeAccountPrefix + hexPath -> trie node
+	trieNodeStoragePrefix = []byte("O")  // trieNodeStoragePrefix + account hash + hexPath -> trie node
+	stateIDPrefix         = []byte("L")  // stateIDPrefix + state root -> state id
+	stateHistoryPrefix    = []byte("sh") // stateHistoryPrefix + state id (uint64 big endian) -> state history
+
+	stateChangesPrefix      = []byte("sc")  // stateChangesPrefix + num (uint64 big endian) + hash -> state changes of the block
+	stateIndexBlockPrefix   = []byte("sib") // stateIndexBlockPrefix + num (uint64 big endian) -> hash of the indexed block
+	stateIndexAccountPrefix = []byte("sia") // stateIndexAccountPrefix + account hash + num (uint64 big endian) -> account before the block
+	stateIndexStoragePrefix = []byte("sis") // stateIndexStoragePrefix + account hash + storage hash + num (uint64 big endian) -> slot before the block
+
 	PreimagePrefix = []byte("secure-key-")      // PreimagePrefix + hash -> preimage
 	configPrefix   = []byte("ethereum-config-") // config prefix for the db
 
@@ -194,6 +229,47 @@ func bloomBitsKey(bit uint, section uint64, hash common.Hash) []byte {
 }
 
 // preimageKey = PreimagePrefix + hash
+// accountTrieNodeKey = trieNodeAccountPrefix + hexPath
+func accountTrieNodeKey(path []byte) []byte {
+	return append(trieNodeAccountPrefix, path...)
+}
+
+// storageTrieNodeKey = trieNodeStoragePrefix + account hash + hexPath
+func storageTrieNodeKey(accountHash common.Hash, path []byte) []byte {
+	return append(append(trieNodeStoragePrefix, accountHash.Bytes()...), path...)
+}
+
+// stateIDKey = stateIDPrefix + state root
+func stateIDKey(root common.Hash) []byte {
+	return append(stateIDPrefix, root.Bytes()...)
+}
+
+// stateHistoryKey = stateHistoryPrefix + state id (uint64 big endian)
+func stateHistoryKey(id uint64) []byte {
+	return append(stateHistoryPrefix, encodeBlockNumber(id)...)
+}
+
+// stateChangesKey = stateChangesPrefix + num (uint64 big endian) + hash
+func stateChangesKey(number uint64, hash common.Hash) []byte {
+	return append(append(stateChangesPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
+// stateIndexBlockKey = stateIndexBlockPrefix + num (uint64 big endian)
+func stateIndexBlockKey(number uint64) []byte {
+	return append(stateIndexBlockPrefix, encodeBlockNumber(number)...)
+}
+
+// stateIndexAccountKey = stateIndexAccountPrefix + account hash + num (uint64 big endian)
+func stateIndexAccountKey(accountHash common.Hash, number uint64) []byte {
+	return append(append(stateIndexAccountPrefix, accountHash.Bytes()...), encodeBlockNumber(number)...)
+}
+
+// stateIndexStorageKey = stateIndexStoragePrefix + account hash + storage hash + num (uint64 big endian)
+func stateIndexStorageKey(accountHash, storageHash common.Hash, number uint64) []byte {
+	key := append(append(stateIndexStoragePrefix, accountHash.Bytes()...), storageHash.Bytes()...)
+	return append(key, encodeBlockNumber(number)...)
+}
+
 func preimageKey(hash common.Hash) []byte {
 	return append(PreimagePrefix, hash.Bytes()...)
 }
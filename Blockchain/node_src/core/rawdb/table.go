@@ -66,6 +66,12 @@ func (t *table) AncientSize(kind string) (uint64, error) {
 	return t.db.AncientSize(kind)
 }
 
+// AncientTail is a noop passthrough that just forwards the request to the underlying
+// database.
+func (t *table) AncientTail(kind string) (uint64, error) {
+	return t.db.AncientTail(kind)
+}
+
 // ModifyAncients runs an ancient write operation on the underlying database.
 func (t *table) ModifyAncients(fn func(ethdb.AncientWriteOp) error) (int64, error) {
 	return t.db.ModifyAncients(fn)
@@ -81,6 +87,18 @@ func (t *table) TruncateAncients(items uint64) error {
 	return t.db.TruncateAncients(items)
 }
 
+// TruncateAncientTail is a noop passthrough that just forwards the request to
+// the underlying database.
+func (t *table) TruncateAncientTail(kind string, tail uint64) error {
+	return t.db.TruncateAncientTail(kind, tail)
+}
+
+// ResetAncients is a noop passthrough that just forwards the request to the
+// underlying database.
+func (t *table) ResetAncients(tail uint64) error {
+	return t.db.ResetAncients(tail)
+}
+
 // Sync is a noop passthrough that just forwards the request to the underlying
 // database.
 func (t *table) Sync() error {
@@ -0,0 +1,224 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package state
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state/snapshot"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// TrackChanges enables tracking the accounts and storage slots changed by the
+// block, retrievable through Changes once the state is committed. It must be
+// enabled before any state is accessed.
+func (s *StateDB) TrackChanges() {
+	s.trackChanges = true
+}
+
+// Changes returns the accounts and storage slots changed by the last commit
+// along with their previous values, or nil if changes are not tracked.
+func (s *StateDB) Changes() *rawdb.StateChanges {
+	return s.changes
+}
+
+// collectChanges gathers the state changes of the dirty objects, comparing
+// them against their values in the state the block is built on. It must be
+// called after the changes are merged into the tries, but before the storage
+// of the destructed accounts is discarded.
+func (s *StateDB) collectChanges() error {
+	if !s.trackChanges {
+		return nil
+	}
+	changes := new(rawdb.StateChanges)
+	for addr := range s.stateObjectsDirty {
+		obj := s.stateObjects[addr]
+
+		var prev, current []byte
+		if obj.origin != nil {
+			prev = snapshot.SlimAccountRLP(obj.origin.Nonce, obj.origin.Balance, obj.origin.Root, obj.origin.CodeHash)
+		}
+		if !obj.deleted {
+			current = snapshot.SlimAccountRLP(obj.data.Nonce, obj.data.Balance, obj.data.Root, obj.data.CodeHash)
+		}
+		if !bytes.Equal(prev, current) {
+			changes.Accounts = append(changes.Accounts, rawdb.AccountChange{Hash: obj.addrHash, Prev: prev})
+		}
+		// The whole original storage is gone if the account was destructed,
+		// otherwise only the written slots have changed
+		slots := make(map[common.Hash][]byte)
+		if obj.originRoot != emptyRoot && (obj.deleted || obj.wipeStorage) {
+			tr, err := s.db.OpenStorageTrie(s.originalRoot, obj.addrHash, obj.originRoot)
+			if err != nil {
+				return err
+			}
+			it := trie.NewIterator(tr.NodeIterator(nil))
+			for it.Next() {
+				slots[common.BytesToHash(it.Key)] = common.CopyBytes(it.Value)
+			}
+			if it.Err != nil {
+				return it.Err
+			}
+		}
+		values := make(map[common.Hash][]byte, len(obj.storageOrigin))
+		for key, value := range obj.storageOrigin {
+			hash := crypto.Keccak256Hash(key[:])
+			if _, ok := slots[hash]; !ok {
+				slots[hash] = encodeStorage(value)
+			}
+			if !obj.deleted {
+				values[hash] = encodeStorage(obj.originStorage[key])
+			}
+		}
+		for hash, prev := range slots {
+			if !bytes.Equal(prev, values[hash]) {
+				changes.Storage = append(changes.Storage, rawdb.StorageChange{Account: obj.addrHash, Slot: hash, Prev: prev})
+			}
+		}
+		// Start tracking the next block from the committed values
+		obj.origin, obj.storageOrigin = nil, nil
+		if !obj.deleted {
+			obj.origin = obj.copyAccount()
+		}
+	}
+	sort.Slice(changes.Accounts, func(i, j int) bool {
+		return bytes.Compare(changes.Accounts[i].Hash[:], changes.Accounts[j].Hash[:]) < 0
+	})
+	sort.Slice(changes.Storage, func(i, j int) bool {
+		a, b := changes.Storage[i], changes.Storage[j]
+		if a.Account != b.Account {
+			return bytes.Compare(a.Account[:], b.Account[:]) < 0
+		}
+		return bytes.Compare(a.Slot[:], b.Slot[:]) < 0
+	})
+	s.changes = changes
+	return nil
+}
+
+// encodeStorage encodes a storage value in the trie format, an unset slot is
+// encoded as empty.
+func encodeStorage(value common.Hash) []byte {
+	if value == (common.Hash{}) {
+		return nil
+	}
+	enc, _ := rlp.EncodeToBytes(common.TrimLeftZeroes(value[:]))
+	return enc
+}
+
+// NewHistorical creates a read-only state of an older block from the state
+// index. The values changed since the block are read from the index, all the
+// others from the state of the latest indexed block with the given root.
+func NewHistorical(db Database, snaps *snapshot.Tree, number uint64, root, headRoot common.Hash) (*StateDB, error) {
+	sdb, err := New(headRoot, db, nil)
+	if err != nil {
+		return nil, err
+	}
+	reader := &historyReader{
+		diskdb: db.TrieDB().DiskDB(),
+		triedb: db.TrieDB(),
+		number: number,
+		root:   root,
+		head:   headRoot,
+	}
+	if snaps != nil {
+		reader.snap = snaps.Snapshot(headRoot)
+	}
+	if reader.trie, err = trie.NewWithID(trie.StateTrieID(headRoot), db.TrieDB()); err != nil {
+		return nil, err
+	}
+	sdb.snap = reader
+	sdb.snapDestructs = make(map[common.Hash]struct{})
+	sdb.snapAccounts = make(map[common.Hash][]byte)
+	sdb.snapStorage = make(map[common.Hash]map[common.Hash][]byte)
+	return sdb, nil
+}
+
+// historyReader implements snapshot.Snapshot on top of the state index to
+// serve the flat state of an older block.
+type historyReader struct {
+	diskdb ethdb.KeyValueStore
+	triedb *trie.Database
+	number uint64      // Number of the block the state is served for
+	root   common.Hash // State root of the block the state is served for
+	head   common.Hash // State root of the latest indexed block
+	snap   snapshot.Snapshot
+	trie   *trie.Trie
+}
+
+// Root returns the state root of the block the state is served for.
+func (r *historyReader) Root() common.Hash {
+	return r.root
+}
+
+// Account retrieves the account with the given hash at the block.
+func (r *historyReader) Account(hash common.Hash) (*snapshot.Account, error) {
+	data, err := r.AccountRLP(hash)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	account := new(snapshot.Account)
+	if err := rlp.DecodeBytes(data, account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// AccountRLP retrieves the slim RLP encoded account with the given hash at
+// the block.
+func (r *historyReader) AccountRLP(hash common.Hash) ([]byte, error) {
+	if _, prev, ok := rawdb.ReadAccountIndex(r.diskdb, hash, r.number); ok {
+		return prev, nil
+	}
+	if r.snap != nil {
+		if data, err := r.snap.AccountRLP(hash); err == nil {
+			return data, nil
+		}
+	}
+	enc, err := r.trie.TryGet(hash[:])
+	if err != nil || len(enc) == 0 {
+		return nil, err
+	}
+	var account types.StateAccount
+	if err := rlp.DecodeBytes(enc, &account); err != nil {
+		return nil, err
+	}
+	return snapshot.SlimAccountRLP(account.Nonce, account.Balance, account.Root, account.CodeHash), nil
+}
+
+// Storage retrieves the RLP encoded value of a storage slot at the block.
+func (r *historyReader) Storage(accountHash, storageHash common.Hash) ([]byte, error) {
+	if _, prev, ok := rawdb.ReadStorageIndex(r.diskdb, accountHash, storageHash, r.number); ok {
+		return prev, nil
+	}
+	if r.snap != nil {
+		if data, err := r.snap.Storage(accountHash, storageHash); err == nil {
+			return data, nil
+		}
+	}
+	enc, err := r.trie.TryGet(accountHash[:])
+	if err != nil || len(enc) == 0 {
+		return nil, err
+	}
+	var account types.StateAccount
+	if err := rlp.DecodeBytes(enc, &account); err != nil {
+		return nil, err
+	}
+	if account.Root == emptyRoot {
+		return nil, nil
+	}
+	tr, err := trie.NewWithID(trie.StorageTrieID(r.head, accountHash, account.Root), r.triedb)
+	if err != nil {
+		return nil, err
+	}
+	return tr.TryGet(storageHash[:])
+}
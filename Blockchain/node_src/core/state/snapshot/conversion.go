@@ -89,6 +89,39 @@ func GenerateTrie(snaptree *Tree, root common.Hash, src ethdb.Database, dst ethd
 	return nil
 }
 
+// GenerateTrieFromDisk regenerates the whole state (account trie + all storage
+// tries) from the flat snapshot entries persisted in db, checking the contract
+// codes are present and the recomputed root matches the expected one. On success
+// the flat entries are marked as a complete snapshot of the given root, so they
+// can be picked up without regeneration.
+func GenerateTrieFromDisk(db ethdb.KeyValueStore, root common.Hash) error {
+	base := &diskLayer{diskdb: db, root: root}
+
+	acctIt := base.AccountIterator(common.Hash{})
+	defer acctIt.Release()
+
+	got, err := generateTrieRoot(db, acctIt, common.Hash{}, stackTrieGenerate, func(dst ethdb.KeyValueWriter, accountHash, codeHash common.Hash, stat *generateStats) (common.Hash, error) {
+		if codeHash != emptyCode && len(rawdb.ReadCode(db, codeHash)) == 0 {
+			return common.Hash{}, fmt.Errorf("missing contract code %x", codeHash)
+		}
+		storageIt, _ := base.StorageIterator(accountHash, common.Hash{})
+		defer storageIt.Release()
+
+		return generateTrieRoot(dst, storageIt, accountHash, stackTrieGenerate, nil, stat, false)
+	}, newGenerateStats(), true)
+
+	if err != nil {
+		return err
+	}
+	if got != root {
+		return fmt.Errorf("state root hash mismatch: got %x, want %x", got, root)
+	}
+	batch := db.NewBatch()
+	rawdb.WriteSnapshotRoot(batch, root)
+	journalProgress(batch, nil, nil)
+	return batch.Write()
+}
+
 // generateStats is a collection of statistics gathered by the trie generator
 // for logging purposes.
 type generateStats struct {
@@ -46,7 +46,7 @@ func TestGeneration(t *testing.T) {
 	acc = &Account{Balance: big.NewInt(3), Root: stTrie.Hash().Bytes(), CodeHash: emptyCode.Bytes()}
 	val, _ = rlp.EncodeToBytes(acc)
 	accTrie.Update([]byte("acc-3"), val) // 0x50815097425d000edfc8b3a4a13e175fc2bdcfee8bdfbf2d1ff61041d3c235b2
-	root, _, _ := accTrie.Commit(nil)    // Root: 0xe3712f1a226f3782caca78ca770ccc19ee000552813a9f59d479f8611db9b1fd
+	root, _, _, _ := accTrie.Commit(nil)    // Root: 0xe3712f1a226f3782caca78ca770ccc19ee000552813a9f59d479f8611db9b1fd
 	triedb.Commit(root, false, nil)
 
 	if have, want := root, common.HexToHash("0xe3712f1a226f3782caca78ca770ccc19ee000552813a9f59d479f8611db9b1fd"); have != want {
@@ -114,7 +114,7 @@ func TestGenerateExistentState(t *testing.T) {
 	rawdb.WriteStorageSnapshot(diskdb, hashData([]byte("acc-3")), hashData([]byte("key-2")), []byte("val-2"))
 	rawdb.WriteStorageSnapshot(diskdb, hashData([]byte("acc-3")), hashData([]byte("key-3")), []byte("val-3"))
 
-	root, _, _ := accTrie.Commit(nil) // Root: 0xe3712f1a226f3782caca78ca770ccc19ee000552813a9f59d479f8611db9b1fd
+	root, _, _, _ := accTrie.Commit(nil) // Root: 0xe3712f1a226f3782caca78ca770ccc19ee000552813a9f59d479f8611db9b1fd
 	triedb.Commit(root, false, nil)
 
 	snap := generateSnapshot(diskdb, triedb, 16, root)
@@ -201,12 +201,12 @@ func (t *testHelper) makeStorageTrie(keys []string, vals []string) []byte {
 	for i, k := range keys {
 		stTrie.Update([]byte(k), []byte(vals[i]))
 	}
-	root, _, _ := stTrie.Commit(nil)
+	root, _, _, _ := stTrie.Commit(nil)
 	return root.Bytes()
 }
 
 func (t *testHelper) Generate() (common.Hash, *diskLayer) {
-	root, _, _ := t.accTrie.Commit(nil)
+	root, _, _, _ := t.accTrie.Commit(nil)
 	t.triedb.Commit(root, false, nil)
 	snap := generateSnapshot(t.diskdb, t.triedb, 16, root)
 	return root, snap
@@ -561,7 +561,7 @@ func TestGenerateWithExtraAccounts(t *testing.T) {
 		rawdb.WriteStorageSnapshot(diskdb, key, hashData([]byte("b-key-2")), []byte("b-val-2"))
 		rawdb.WriteStorageSnapshot(diskdb, key, hashData([]byte("b-key-3")), []byte("b-val-3"))
 	}
-	root, _, _ := accTrie.Commit(nil)
+	root, _, _, _ := accTrie.Commit(nil)
 	t.Logf("root: %x", root)
 	triedb.Commit(root, false, nil)
 	// To verify the test: If we now inspect the snap db, there should exist extraneous storage items
@@ -623,7 +623,7 @@ func TestGenerateWithManyExtraAccounts(t *testing.T) {
 			rawdb.WriteAccountSnapshot(diskdb, key, val)
 		}
 	}
-	root, _, _ := accTrie.Commit(nil)
+	root, _, _, _ := accTrie.Commit(nil)
 	t.Logf("root: %x", root)
 	triedb.Commit(root, false, nil)
 
@@ -675,7 +675,7 @@ func TestGenerateWithExtraBeforeAndAfter(t *testing.T) {
 		rawdb.WriteAccountSnapshot(diskdb, common.HexToHash("0x07"), val)
 	}
 
-	root, _, _ := accTrie.Commit(nil)
+	root, _, _, _ := accTrie.Commit(nil)
 	t.Logf("root: %x", root)
 	triedb.Commit(root, false, nil)
 
@@ -719,7 +719,7 @@ func TestGenerateWithMalformedSnapdata(t *testing.T) {
 		rawdb.WriteAccountSnapshot(diskdb, common.HexToHash("0x05"), junk)
 	}
 
-	root, _, _ := accTrie.Commit(nil)
+	root, _, _, _ := accTrie.Commit(nil)
 	t.Logf("root: %x", root)
 	triedb.Commit(root, false, nil)
 
@@ -816,3 +816,69 @@ func TestGenerateWithIncompleteStorage(t *testing.T) {
 	snap.genAbort <- stop
 	<-stop
 }
+
+// Tests that the state tries can be rebuilt purely from the flat snapshot
+// entries, and that inconsistent entries are rejected.
+func TestGenerateTrieFromDisk(t *testing.T) {
+	var (
+		code   = []byte{0x60, 0x00}
+		helper = newHelper()
+		stRoot = helper.makeStorageTrie([]string{"key-1", "key-2", "key-3"}, []string{"val-1", "val-2", "val-3"})
+	)
+	helper.addAccount("acc-1", &Account{Balance: big.NewInt(1), Root: stRoot, CodeHash: hashData(code).Bytes()})
+	helper.addAccount("acc-2", &Account{Balance: big.NewInt(2), Root: emptyRoot.Bytes(), CodeHash: emptyCode.Bytes()})
+	helper.addAccount("acc-3", &Account{Balance: big.NewInt(3), Root: stRoot, CodeHash: emptyCode.Bytes()})
+	helper.addSnapStorage("acc-1", []string{"key-1", "key-2", "key-3"}, []string{"val-1", "val-2", "val-3"})
+	helper.addSnapStorage("acc-3", []string{"key-1", "key-2", "key-3"}, []string{"val-1", "val-2", "val-3"})
+
+	// Only commit the tries in memory, the disk has the flat state alone
+	root, _, _, _ := helper.accTrie.Commit(nil)
+
+	if err := GenerateTrieFromDisk(helper.diskdb, root); err == nil {
+		t.Fatalf("state with missing contract code generated")
+	}
+	rawdb.WriteCode(helper.diskdb, hashData(code), code)
+	if err := GenerateTrieFromDisk(helper.diskdb, common.Hash{0x01}); err == nil {
+		t.Fatalf("state with mismatching root generated")
+	}
+	if err := GenerateTrieFromDisk(helper.diskdb, root); err != nil {
+		t.Fatalf("failed to generate state: %v", err)
+	}
+	if have := rawdb.ReadSnapshotRoot(helper.diskdb); have != root {
+		t.Fatalf("snapshot root mismatch: have %x, want %x", have, root)
+	}
+	// The rebuilt tries must be complete in a fresh database
+	triedb := trie.NewDatabase(helper.diskdb)
+	accTrie, err := trie.NewSecure(root, triedb)
+	if err != nil {
+		t.Fatalf("failed to open account trie: %v", err)
+	}
+	it := trie.NewIterator(accTrie.NodeIterator(nil))
+	for it.Next() {
+		var acc Account
+		if err := rlp.DecodeBytes(it.Value, &acc); err != nil {
+			t.Fatal(err)
+		}
+		stTrie, err := trie.NewSecure(common.BytesToHash(acc.Root), triedb)
+		if err != nil {
+			t.Fatalf("failed to open storage trie: %v", err)
+		}
+		stIt := trie.NewIterator(stTrie.NodeIterator(nil))
+		for stIt.Next() {
+		}
+		if stIt.Err != nil {
+			t.Fatalf("failed to iterate storage trie: %v", stIt.Err)
+		}
+	}
+	if it.Err != nil {
+		t.Fatalf("failed to iterate account trie: %v", it.Err)
+	}
+	// The flat state is picked up as a complete snapshot
+	snaps, err := New(helper.diskdb, triedb, 16, root, false, false, false)
+	if err != nil {
+		t.Fatalf("failed to load snapshot: %v", err)
+	}
+	if err := snaps.Verify(root); err != nil {
+		t.Fatalf("failed to verify snapshot: %v", err)
+	}
+}
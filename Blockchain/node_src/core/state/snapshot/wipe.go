@@ -34,6 +34,15 @@ func wipeSnapshot(db ethdb.KeyValueStore, full bool) chan struct{} {
 	return wiper
 }
 
+// Wipe synchronously deletes all the data associated with the snapshot
+// (accounts, storage, metadata) from the database.
+func Wipe(db ethdb.KeyValueStore) error {
+	rawdb.DeleteSnapshotRoot(db)
+	rawdb.DeleteSnapshotJournal(db)
+	rawdb.DeleteSnapshotGenerator(db)
+	return wipeContent(db)
+}
+
 // wipeContent iterates over the entire key-value database and deletes all the
 // data associated with the snapshot (accounts, storage), but not the root hash
 // as the wiper is meant to run on a background thread but the root needs to be
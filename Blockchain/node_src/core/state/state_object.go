@@ -14,6 +14,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
 )
 
 var emptyCodeHash = crypto.Keccak256(nil)
@@ -77,6 +78,18 @@ type stateObject struct {
 	suicided  bool
 	deleted   bool
 
+	// originRoot is the storage root of the account in the state the block
+	// is built on, wiped in the path scheme if the account is destructed or
+	// recreated (wipeStorage).
+	originRoot  common.Hash
+	wipeStorage bool
+
+	// origin is the account in the state the block is built on (nil if it did
+	// not exist yet) and storageOrigin the original values of the slots changed
+	// by the block. Both are only tracked for the state index.
+	origin        *types.StateAccount
+	storageOrigin Storage
+
 	// only used between StateDB.preUpdateStateObject and StateDB.updateStateObject
 	accountRLP     []byte
 	rlpErr         error
@@ -103,15 +116,28 @@ func newObject(db *StateDB, address common.Address, data types.StateAccount) *st
 	if data.Root == (common.Hash{}) {
 		data.Root = emptyRoot
 	}
-	return &stateObject{
+	obj := &stateObject{
 		db:             db,
 		address:        address,
 		addrHash:       crypto.HashDataWithCache(nil, address[:]),
 		data:           data,
+		originRoot:     data.Root,
 		originStorage:  make(Storage),
 		pendingStorage: make(Storage),
 		dirtyStorage:   make(Storage),
 	}
+	if db.trackChanges {
+		obj.origin = obj.copyAccount()
+	}
+	return obj
+}
+
+// copyAccount returns an independent copy of the account data.
+func (s *stateObject) copyAccount() *types.StateAccount {
+	account := s.data
+	account.Balance = new(big.Int).Set(s.data.Balance)
+	account.CodeHash = common.CopyBytes(s.data.CodeHash)
+	return &account
 }
 
 // EncodeRLP implements rlp.Encoder.
@@ -148,13 +174,13 @@ func (s *stateObject) getTrie(db Database) Trie {
 		if s.data.Root != emptyRoot && s.db.prefetcher != nil {
 			// When the miner is creating the pending state, there is no
 			// prefetcher
-			s.trie = s.db.prefetcher.trie(s.data.Root)
+			s.trie = s.db.prefetcher.trie(s.addrHash, s.data.Root)
 		}
 		if s.trie == nil {
 			var err error
-			s.trie, err = db.OpenStorageTrie(s.addrHash, s.data.Root)
+			s.trie, err = db.OpenStorageTrie(s.db.originalRoot, s.addrHash, s.data.Root)
 			if err != nil {
-				s.trie, _ = db.OpenStorageTrie(s.addrHash, common.Hash{})
+				s.trie, _ = db.OpenStorageTrie(s.db.originalRoot, s.addrHash, common.Hash{})
 				s.setError(fmt.Errorf("can't create storage trie: %v", err))
 			}
 		}
@@ -262,7 +288,7 @@ func (s *stateObject) finalise(prefetch bool) {
 		}
 	}
 	if s.db.prefetcher != nil && prefetch && len(slotsToPrefetch) > 0 && s.data.Root != emptyRoot {
-		s.db.prefetcher.prefetch(s.data.Root, slotsToPrefetch)
+		s.db.prefetcher.prefetch(s.addrHash, s.data.Root, slotsToPrefetch)
 	}
 	if len(s.dirtyStorage) > 0 {
 		s.dirtyStorage = make(Storage)
@@ -286,6 +312,14 @@ func (s *stateObject) updateTrieConcurrencySafe(db Database) Trie {
 		if value == s.originStorage[key] {
 			continue
 		}
+		if s.db.trackChanges {
+			if s.storageOrigin == nil {
+				s.storageOrigin = make(Storage)
+			}
+			if _, ok := s.storageOrigin[key]; !ok {
+				s.storageOrigin[key] = s.originStorage[key]
+			}
+		}
 		s.originStorage[key] = value
 
 		var v []byte
@@ -326,7 +360,7 @@ func (s *stateObject) updateSnapshot() {
 		s.snapStorage = nil
 	}
 	if s.db.prefetcher != nil && s.usedStorage != nil {
-		s.db.prefetcher.used(s.data.Root, s.usedStorage)
+		s.db.prefetcher.used(s.addrHash, s.data.Root, s.usedStorage)
 		s.usedStorage = nil
 	}
 }
@@ -346,24 +380,24 @@ func (s *stateObject) updateTrie(db Database) Trie {
 }
 
 // CommitTrie the storage trie of the object to db.
-// This updates the trie root.
-func (s *stateObject) CommitTrie(db Database) (int, error) {
+// This updates the trie root and returns the committed nodes in the path scheme.
+func (s *stateObject) CommitTrie(db Database) (*trie.NodeSet, int, error) {
 	// If nothing changed, don't bother with hashing anything
 	if s.updateTrie(db) == nil {
-		return 0, nil
+		return nil, 0, nil
 	}
 	if s.dbErr != nil {
-		return 0, s.dbErr
+		return nil, 0, s.dbErr
 	}
 	// Track the amount of time wasted on committing the storage trie
 	if metrics.EnabledExpensive {
 		defer func(start time.Time) { s.db.StorageCommits += time.Since(start) }(time.Now())
 	}
-	root, committed, err := s.trie.Commit(nil)
+	root, committed, nodes, err := s.trie.Commit(nil)
 	if err == nil {
 		s.data.Root = root
 	}
-	return committed, err
+	return nodes, committed, err
 }
 
 // AddBalance adds amount to s's balance.
@@ -420,6 +454,12 @@ func (s *stateObject) deepCopy(db *StateDB) *stateObject {
 	stateObject.suicided = s.suicided
 	stateObject.dirtyCode = s.dirtyCode
 	stateObject.deleted = s.deleted
+	stateObject.originRoot = s.originRoot
+	stateObject.wipeStorage = s.wipeStorage
+	stateObject.origin = s.origin
+	if s.storageOrigin != nil {
+		stateObject.storageOrigin = s.storageOrigin.Copy()
+	}
 	return stateObject
 }
 
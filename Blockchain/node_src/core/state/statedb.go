@@ -68,6 +68,11 @@ type StateDB struct {
 	stateObjectsDirty   map[common.Address]struct{} // State objects modified in the current execution
 	stateObjectsDestruct map[common.Address]struct{} // State objects destructed in the block
 
+	// Accounts and storage slots changed by the block, only tracked for the
+	// state index.
+	trackChanges bool
+	changes      *rawdb.StateChanges
+
 	// DB error.
 	// State objects are used by the consensus core and VM which are
 	// unable to deal with database-level errors. Any error that occurs
@@ -456,6 +461,21 @@ func (s *StateDB) Erase(addr common.Address) bool {
 	return true
 }
 
+// PendingChanges returns the accounts changed by the finalised, but not yet
+// hashed changes, along with the storage slots written in each of them.
+func (s *StateDB) PendingChanges() map[common.Address][]common.Hash {
+	changes := make(map[common.Address][]common.Hash, len(s.stateObjectsPending))
+	for addr := range s.stateObjectsPending {
+		obj := s.stateObjects[addr]
+		slots := make([]common.Hash, 0, len(obj.pendingStorage))
+		for key := range obj.pendingStorage {
+			slots = append(slots, key)
+		}
+		changes[addr] = slots
+	}
+	return changes
+}
+
 // Setting, updating & deleting state object methods.
 
 // concurrency safe
@@ -566,6 +586,15 @@ func (s *StateDB) PreloadAccounts(block *types.Block, signer types.Signer) {
 	}
 }
 
+// PrefetchAccountTries resolves the account trie nodes on the paths to the given
+// accounts, warming the trie database caches without loading the accounts into
+// the state.
+func (s *StateDB) PrefetchAccountTries(addrs []common.Address) {
+	for _, addr := range addrs {
+		s.trie.TryGet(addr.Bytes())
+	}
+}
+
 func (s *StateDB) preloadAccountFromSnap(addr common.Address) *stateObject {
 	if s.snap == nil {
 		return nil
@@ -681,6 +710,11 @@ func (s *StateDB) createObject(addr common.Address) (newobj, prev *stateObject)
 		}
 	}
 	newobj = newObject(s, addr, types.StateAccount{})
+	newobj.origin = nil
+	if prev != nil {
+		newobj.originRoot, newobj.wipeStorage = prev.originRoot, true
+		newobj.origin, newobj.storageOrigin = prev.origin, prev.storageOrigin
+	}
 	if prev == nil {
 		s.journal.append(createObjectChange{account: &addr})
 	} else {
@@ -743,6 +777,7 @@ func (s *StateDB) Copy() *StateDB {
 	state := &StateDB{
 		db:                  s.db,
 		trie:                s.db.CopyTrie(s.trie),
+		originalRoot:        s.originalRoot,
 		stateObjects:        make(map[common.Address]*stateObject, len(s.journal.dirties)),
 		stateObjectsPending: make(map[common.Address]struct{}, len(s.stateObjectsPending)),
 		stateObjectsDirty:   make(map[common.Address]struct{}, len(s.journal.dirties)),
@@ -752,6 +787,7 @@ func (s *StateDB) Copy() *StateDB {
 		preimages:           make(map[common.Hash][]byte, len(s.preimages)),
 		journal:             newJournal(),
 		hasher:              crypto.NewKeccakState(),
+		trackChanges:        s.trackChanges,
 	}
 	// Copy the dirty states, logs, and preimages
 	for addr := range s.journal.dirties {
@@ -903,7 +939,7 @@ func (s *StateDB) Finalise(deleteEmptyObjects bool) {
 		addressesToPrefetch = append(addressesToPrefetch, common.CopyBytes(addr[:])) // Copy needed for closure
 	}
 	if s.prefetcher != nil && len(addressesToPrefetch) > 0 {
-		s.prefetcher.prefetch(s.originalRoot, addressesToPrefetch)
+		s.prefetcher.prefetch(common.Hash{}, s.originalRoot, addressesToPrefetch)
 	}
 	// Invalidate journal because reverting across transactions is not allowed.
 	s.clearJournalAndRefund()
@@ -957,7 +993,7 @@ func (s *StateDB) IntermediateRoot(deleteEmptyObjects bool) common.Hash {
 	// _untouched_. We can check with the prefetcher, if it can give us a trie
 	// which has the same root, but also has some content loaded into it.
 	if prefetcher != nil {
-		if trie := prefetcher.trie(s.originalRoot); trie != nil {
+		if trie := prefetcher.trie(common.Hash{}, s.originalRoot); trie != nil {
 			s.trie = trie
 		}
 	}
@@ -973,7 +1009,7 @@ func (s *StateDB) IntermediateRoot(deleteEmptyObjects bool) common.Hash {
 		usedAddrs = append(usedAddrs, common.CopyBytes(addr[:])) // Copy needed for closure
 	}
 	if prefetcher != nil {
-		prefetcher.used(s.originalRoot, usedAddrs)
+		prefetcher.used(common.Hash{}, s.originalRoot, usedAddrs)
 	}
 	if len(s.stateObjectsPending) > 0 {
 		s.stateObjectsPending = make(map[common.Address]struct{})
@@ -1009,6 +1045,23 @@ func (s *StateDB) Commit(deleteEmptyObjects bool) (common.Hash, error) {
 	// Finalize any pending changes and merge everything into the tries
 	s.IntermediateRoot(deleteEmptyObjects)
 
+	// Collect the state changes for the state index while the storage of the
+	// destructed accounts is still available
+	if err := s.collectChanges(); err != nil {
+		return common.Hash{}, err
+	}
+	// Discard the storage of the destructed and recreated accounts before the
+	// new storage tries are committed, only needed by the path scheme
+	nodes := trie.NewMergedNodeSet()
+	for addr := range s.stateObjectsDirty {
+		if obj := s.stateObjects[addr]; obj.originRoot != emptyRoot && (obj.deleted || obj.wipeStorage) {
+			set, err := s.db.TrieDB().WipeStorage(s.originalRoot, obj.addrHash, obj.originRoot)
+			if err != nil {
+				return common.Hash{}, err
+			}
+			nodes.Merge(set)
+		}
+	}
 	// Commit objects to the trie, measuring the elapsed time
 	var storageCommitted int
 	codeWriter := s.db.TrieDB().DiskDB().NewBatch()
@@ -1020,10 +1073,11 @@ func (s *StateDB) Commit(deleteEmptyObjects bool) (common.Hash, error) {
 				obj.dirtyCode = false
 			}
 			// Write any storage changes in the state object to its storage trie
-			committed, err := obj.CommitTrie(s.db)
+			set, committed, err := obj.CommitTrie(s.db)
 			if err != nil {
 				return common.Hash{}, err
 			}
+			nodes.Merge(set)
 			storageCommitted += committed
 		}
 	}
@@ -1043,7 +1097,7 @@ func (s *StateDB) Commit(deleteEmptyObjects bool) (common.Hash, error) {
 	// The onleaf func is called _serially_, so we can reuse the same account
 	// for unmarshalling every time.
 	var account types.StateAccount
-	root, accountCommitted, err := s.trie.Commit(func(_ [][]byte, _ []byte, leaf []byte, parent common.Hash) error {
+	root, accountCommitted, set, err := s.trie.Commit(func(_ [][]byte, _ []byte, leaf []byte, parent common.Hash) error {
 		if err := rlp.DecodeBytes(leaf, &account); err != nil {
 			return nil
 		}
@@ -1055,6 +1109,7 @@ func (s *StateDB) Commit(deleteEmptyObjects bool) (common.Hash, error) {
 	if err != nil {
 		return common.Hash{}, err
 	}
+	nodes.Merge(set)
 	if metrics.EnabledExpensive {
 		s.AccountCommits += time.Since(start)
 
@@ -1087,6 +1142,26 @@ func (s *StateDB) Commit(deleteEmptyObjects bool) (common.Hash, error) {
 		}
 		s.snap, s.snapDestructs, s.snapAccounts, s.snapStorage = nil, nil, nil, nil
 	}
+	if triedb := s.db.TrieDB(); triedb.Scheme() == rawdb.PathScheme {
+		if err := triedb.Update(root, s.originalRoot, nodes); err != nil {
+			return common.Hash{}, err
+		}
+		// Keep 128 diff layers in the memory like the snapshots, the state of
+		// HEAD-128 is persisted.
+		if err := triedb.Flatten(root, 128); err != nil {
+			return common.Hash{}, err
+		}
+		// The committed tries are bound to the parent state, reopen them on
+		// top of the new one so the statedb stays usable.
+		if s.trie, err = s.db.OpenTrie(root); err != nil {
+			return common.Hash{}, err
+		}
+		for _, obj := range s.stateObjects {
+			obj.trie = nil
+			obj.originRoot, obj.wipeStorage = obj.data.Root, false
+		}
+		s.originalRoot = root
+	}
 	return root, err
 }
 
@@ -1153,8 +1228,20 @@ func (s *StateDB) AsyncCommit(deleteEmptyObjects bool, afterCommit func(common.H
 	if s.dbErr != nil {
 		return fmt.Errorf("commit aborted due to earlier error: %v", s.dbErr)
 	}
+	// The path scheme seals every state into its own layer, commit in place
+	if s.db.TrieDB().Scheme() == rawdb.PathScheme {
+		root, err := s.Commit(deleteEmptyObjects)
+		if err != nil {
+			return err
+		}
+		afterCommit(root)
+		return nil
+	}
 	// Finalize any pending changes and merge everything into the tries
 	root := s.IntermediateRoot(deleteEmptyObjects)
+	if err := s.collectChanges(); err != nil {
+		return err
+	}
 
 	// If snapshotting is enabled, update the snapshot tree with this new version
 	if s.snap != nil {
@@ -1214,7 +1301,7 @@ func (s *StateDB) AsyncCommit(deleteEmptyObjects bool, afterCommit func(common.H
 			if obj := s.stateObjects[addr]; !obj.deleted {
 
 				// Write any storage changes in the state object to its storage trie
-				committed, err := obj.CommitTrie(s.db)
+				_, committed, err := obj.CommitTrie(s.db)
 				if err != nil {
 					log.Crit("Aync commit storage trie error", "addr", addr, "err", err)
 					return
@@ -1235,7 +1322,7 @@ func (s *StateDB) AsyncCommit(deleteEmptyObjects bool, afterCommit func(common.H
 		// for unmarshalling every time.
 		var account types.StateAccount
 		accountNum := 0
-		commitRoot, accountCommitted, err := s.trie.Commit(func(_ [][]byte, _ []byte, leaf []byte, parent common.Hash) error {
+		commitRoot, accountCommitted, _, err := s.trie.Commit(func(_ [][]byte, _ []byte, leaf []byte, parent common.Hash) error {
 			accountNum++
 			if err := rlp.DecodeBytes(leaf, &account); err != nil {
 				return nil
@@ -3,21 +3,39 @@
 package core
 
 import (
+	"sync"
 	"sync/atomic"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
 )
 
+// prefetchRecentBlocks is the number of recent blocks whose touched accounts
+// have their trie nodes warmed before prefetching a block.
+const prefetchRecentBlocks = 8
+
+var (
+	prefetchAccountHitMeter  = metrics.NewRegisteredMeter("chain/prefetch/accounts/hit", nil)
+	prefetchAccountMissMeter = metrics.NewRegisteredMeter("chain/prefetch/accounts/miss", nil)
+	prefetchSystemTimer      = metrics.NewRegisteredTimer("chain/prefetch/system", nil)
+)
+
 // statePrefetcher is a basic Prefetcher, which blindly executes a block on top
 // data from disk before the main block processor start executing.
 type statePrefetcher struct {
 	config *params.ChainConfig // Chain configuration options
 	bc     *BlockChain         // Canonical block chain
 	engine consensus.Engine    // Consensus engine used for block rewards
+
+	recent     [prefetchRecentBlocks][]common.Address // Accounts touched by the recently prefetched blocks
+	recentNext int                                    // Slot of the next block in the recent accounts
+	recentLock sync.Mutex
 }
 
 // newStatePrefetcher initialises a new statePrefetcher.
@@ -30,7 +48,12 @@ func newStatePrefetcher(config *params.ChainConfig, bc *BlockChain, engine conse
 }
 
 // Prefetch processes the state changes according to the Ethereum rules by running
+// the transaction messages using the statedb, but any changes are discarded. The
 // only goal is to pre-cache transaction signatures and state trie nodes.
+//
+// The account trie nodes of the accounts touched by the recent blocks are warmed
+// first, and for engines making system calls the system transactions are left
+// to the engine, which warms the system contract storage after the transactions.
 func (p *statePrefetcher) Prefetch(block *types.Block, statedb *state.StateDB, cfg vm.Config, interrupt *uint32) {
 	var (
 		header       = block.Header()
@@ -39,8 +62,24 @@ func (p *statePrefetcher) Prefetch(block *types.Block, statedb *state.StateDB, c
 		evm          = vm.NewEVM(blockContext, vm.TxContext{}, statedb, p.config, cfg)
 		signer       = types.MakeSigner(p.config, header.Number)
 	)
+	// Warm the trie nodes of the accounts touched by the recent blocks
+	touched := make([]common.Address, 0, 2*len(block.Transactions()))
+	for _, tx := range block.Transactions() {
+		if from, err := types.Sender(signer, tx); err == nil {
+			touched = append(touched, from)
+		}
+		if to := tx.To(); to != nil {
+			touched = append(touched, *to)
+		}
+	}
+	statedb.PrefetchAccountTries(p.trackAccounts(touched))
+
 	// Iterate over and process the individual transactions
-	byzantium := p.config.IsByzantium(block.Number())
+	var (
+		posa, isPoSA = p.engine.(consensus.PoSA)
+		txs          = make([]*types.Transaction, 0, len(block.Transactions()))
+		byzantium    = p.config.IsByzantium(block.Number())
+	)
 	for i, tx := range block.Transactions() {
 		// If block precaching was interrupted, abort
 		if interrupt != nil && atomic.LoadUint32(interrupt) == 1 {
@@ -51,6 +90,12 @@ func (p *statePrefetcher) Prefetch(block *types.Block, statedb *state.StateDB, c
 		if err != nil {
 			return // Also invalid block, bail out
 		}
+		if isPoSA {
+			if isSystem, _ := posa.IsSysTransaction(msg.From(), tx, header); isSystem {
+				continue
+			}
+		}
+		txs = append(txs, tx)
 		statedb.Prepare(tx.Hash(), i)
 		if err := precacheTransaction(msg, p.config, gaspool, statedb, header, evm); err != nil {
 			return // Ugh, something went horribly wrong, bail out
@@ -60,12 +105,58 @@ func (p *statePrefetcher) Prefetch(block *types.Block, statedb *state.StateDB, c
 			statedb.IntermediateRoot(true)
 		}
 	}
+	// Warm the storage of the system contracts the engine calls when finalizing
+	if prefetcher, ok := p.engine.(consensus.SystemStatePrefetcher); ok {
+		if interrupt != nil && atomic.LoadUint32(interrupt) == 1 {
+			return
+		}
+		start := time.Now()
+		prefetcher.PrefetchSystemState(p.bc, header, statedb, txs)
+		prefetchSystemTimer.UpdateSince(start)
+	}
 	// If were post-byzantium, pre-load trie nodes for the final root hash
 	if byzantium {
 		statedb.IntermediateRoot(true)
 	}
 }
 
+// trackAccounts records the accounts touched by a block and returns the ones
+// touched by the blocks recorded before it, marking how many of the accounts
+// of the block were among them.
+func (p *statePrefetcher) trackAccounts(accounts []common.Address) []common.Address {
+	p.recentLock.Lock()
+	defer p.recentLock.Unlock()
+
+	seen := make(map[common.Address]struct{})
+	var recent []common.Address
+	for _, addrs := range p.recent {
+		for _, addr := range addrs {
+			if _, ok := seen[addr]; !ok {
+				seen[addr] = struct{}{}
+				recent = append(recent, addr)
+			}
+		}
+	}
+	unique := make([]common.Address, 0, len(accounts))
+	tracked := make(map[common.Address]struct{}, len(accounts))
+	for _, addr := range accounts {
+		if _, ok := tracked[addr]; ok {
+			continue
+		}
+		tracked[addr] = struct{}{}
+		unique = append(unique, addr)
+
+		if _, ok := seen[addr]; ok {
+			prefetchAccountHitMeter.Mark(1)
+		} else {
+			prefetchAccountMissMeter.Mark(1)
+		}
+	}
+	p.recent[p.recentNext] = unique
+	p.recentNext = (p.recentNext + 1) % prefetchRecentBlocks
+	return recent
+}
+
 // precacheTransaction attempts to apply a transaction to the given state database
 // and uses the input parameters for its environment. The goal is not to execute
 func precacheTransaction(msg types.Message, config *params.ChainConfig, gaspool *GasPool, statedb *state.StateDB, header *types.Header, evm *vm.EVM) error {
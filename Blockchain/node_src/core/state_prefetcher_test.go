@@ -0,0 +1,101 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package core
+
+import (
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// systemPrefetchEngine is an engine recording the transactions it is asked to
+// prefetch the system state for.
+type systemPrefetchEngine struct {
+	consensus.Engine
+	txs [][]*types.Transaction
+}
+
+func (e *systemPrefetchEngine) PrefetchSystemState(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction) {
+	e.txs = append(e.txs, txs)
+}
+
+// Tests that the prefetcher warms the accounts touched by the recent blocks
+// and lets the engine warm its system state after the transactions.
+func TestStatePrefetcher(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		gspec   = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  GenesisAlloc{address: {Balance: big.NewInt(params.Ether)}},
+		}
+		signer = types.LatestSigner(gspec.Config)
+		engine = &systemPrefetchEngine{Engine: ethash.NewFaker()}
+	)
+	db := rawdb.NewMemoryDatabase()
+	genesis := gspec.MustCommit(db)
+
+	// Only the first two blocks contain transactions
+	blocks, _ := GenerateChain(gspec.Config, genesis, engine.Engine, db, 2+prefetchRecentBlocks, func(i int, b *BlockGen) {
+		if i < 2 {
+			tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(address), common.Address{byte(i + 1)}, big.NewInt(1000), params.TxGas, b.BaseFee(), nil), signer, key)
+			b.AddTx(tx)
+		}
+	})
+	chain, err := NewBlockChain(db, nil, gspec.Config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	prefetcher := newStatePrefetcher(gspec.Config, chain, engine)
+	prefetch := func(block *types.Block) {
+		statedb, err := state.New(genesis.Root(), state.NewDatabase(db), nil)
+		if err != nil {
+			t.Fatalf("failed to create state: %v", err)
+		}
+		prefetcher.Prefetch(block, statedb, vm.Config{}, nil)
+	}
+	recent := func() []common.Address {
+		prefetcher.recentLock.Lock()
+		defer prefetcher.recentLock.Unlock()
+
+		var addrs []common.Address
+		for _, accounts := range prefetcher.recent {
+			addrs = append(addrs, accounts...)
+		}
+		sort.Slice(addrs, func(i, j int) bool { return addrs[i].Hash().Big().Cmp(addrs[j].Hash().Big()) < 0 })
+		return addrs
+	}
+	prefetch(blocks[0])
+	if len(engine.txs) != 1 || len(engine.txs[0]) != 1 || engine.txs[0][0].Hash() != blocks[0].Transactions()[0].Hash() {
+		t.Fatalf("system state prefetched with the wrong transactions: %v", engine.txs)
+	}
+	// The second block doesn't apply on the genesis state, but its accounts
+	// are still tracked
+	prefetch(blocks[1])
+	if have := recent(); len(have) != 4 {
+		t.Fatalf("recent accounts mismatch: have %v, want sender twice and both recipients", have)
+	}
+	// The accounts of a block are forgotten once enough blocks are prefetched
+	for _, block := range blocks[2 : 2+prefetchRecentBlocks-1] {
+		prefetch(block)
+	}
+	if have := recent(); len(have) != 2 || (have[0] != address && have[1] != address) {
+		t.Fatalf("recent accounts mismatch: have %v, want the accounts of the second block", have)
+	}
+	prefetch(blocks[len(blocks)-1])
+	if have := recent(); len(have) != 0 {
+		t.Fatalf("recent accounts not forgotten: %v", have)
+	}
+}
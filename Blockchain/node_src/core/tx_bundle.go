@@ -0,0 +1,275 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	// bundleSlots is the maximum number of bundles kept in the pool.
+	bundleSlots = 1024
+
+	// bundleSenderSlots is the maximum number of bundles kept in the pool per
+	// sender of their first transaction.
+	bundleSenderSlots = 16
+
+	// BundleMaxTxs is the maximum number of transactions in a single bundle.
+	BundleMaxTxs = 64
+
+	// BundleMaxBlocks is the maximum number of blocks into the future a bundle
+	// may target.
+	BundleMaxBlocks = 1000
+)
+
+var (
+	// ErrBundleEmpty is returned if a bundle without any transactions is submitted.
+	ErrBundleEmpty = errors.New("bundle contains no transactions")
+
+	// ErrBundleTooLarge is returned if a bundle contains more than BundleMaxTxs
+	// transactions.
+	ErrBundleTooLarge = errors.New("bundle contains too many transactions")
+
+	// ErrBundleBlockRange is returned if a bundle's target block range is empty
+	// or is already passed by the local chain.
+	ErrBundleBlockRange = errors.New("invalid bundle block range")
+
+	// ErrBundlePoolFull is returned if the bundle pool has no more slots.
+	ErrBundlePoolFull = errors.New("bundle pool is full")
+
+	// ErrBundleSenderLimit is returned if the sender of a bundle has no more
+	// slots in the bundle pool.
+	ErrBundleSenderLimit = errors.New("too many bundles from sender")
+
+	// ErrBundleReverted is returned if a transaction of a bundle was executed but
+	// failed, which makes the whole bundle fail.
+	ErrBundleReverted = errors.New("bundle transaction reverted")
+
+	bundleGauge = metrics.NewRegisteredGauge("txpool/bundles", nil)
+)
+
+// Bundle is an ordered list of transactions which must be included into a block
+// within the given block range, either all of them consecutively or none.
+type Bundle struct {
+	Txs      types.Transactions
+	MinBlock uint64 // First block number the bundle may be included into
+	MaxBlock uint64 // Last block number the bundle may be included into
+
+	sender common.Address // Sender of the first transaction, the bundle is accounted to
+}
+
+// Hash returns the identifier of the bundle, the hash of its transaction hashes.
+func (b *Bundle) Hash() common.Hash {
+	hashes := make([]byte, 0, len(b.Txs)*common.HashLength)
+	for _, tx := range b.Txs {
+		hashes = append(hashes, tx.Hash().Bytes()...)
+	}
+	return crypto.Keccak256Hash(hashes)
+}
+
+// Gas returns the total gas limit of all transactions in the bundle.
+func (b *Bundle) Gas() uint64 {
+	var gas uint64
+	for _, tx := range b.Txs {
+		gas += tx.Gas()
+	}
+	return gas
+}
+
+// bundleSet keeps the bundles submitted for inclusion, in arrival order.
+type bundleSet struct {
+	bundles map[common.Hash]*Bundle
+	order   []common.Hash
+	senders map[common.Address]int // Number of bundles accounted to each sender
+	lock    sync.RWMutex
+}
+
+func newBundleSet() *bundleSet {
+	return &bundleSet{
+		bundles: make(map[common.Hash]*Bundle),
+		senders: make(map[common.Address]int),
+	}
+}
+
+// add inserts a bundle, returning its hash.
+func (s *bundleSet) add(bundle *Bundle) (common.Hash, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	hash := bundle.Hash()
+	if _, ok := s.bundles[hash]; ok {
+		return hash, ErrAlreadyKnown
+	}
+	if len(s.bundles) >= bundleSlots {
+		return hash, ErrBundlePoolFull
+	}
+	if s.senders[bundle.sender] >= bundleSenderSlots {
+		return hash, ErrBundleSenderLimit
+	}
+	s.bundles[hash] = bundle
+	s.order = append(s.order, hash)
+	s.senders[bundle.sender]++
+	bundleGauge.Update(int64(len(s.bundles)))
+	return hash, nil
+}
+
+// prune drops all bundles which can't be included from the given block on, and
+// the ones the optional stale callback reports can't be included anymore.
+func (s *bundleSet) prune(number uint64, stale func(*Bundle) bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	order := s.order[:0]
+	for _, hash := range s.order {
+		bundle := s.bundles[hash]
+		if bundle.MaxBlock < number || (stale != nil && stale(bundle)) {
+			delete(s.bundles, hash)
+			if s.senders[bundle.sender]--; s.senders[bundle.sender] == 0 {
+				delete(s.senders, bundle.sender)
+			}
+			continue
+		}
+		order = append(order, hash)
+	}
+	s.order = order
+	bundleGauge.Update(int64(len(s.bundles)))
+}
+
+// includable returns the bundles which target the given block number, in
+// arrival order.
+func (s *bundleSet) includable(number uint64) []*Bundle {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var bundles []*Bundle
+	for _, hash := range s.order {
+		if bundle := s.bundles[hash]; bundle.MinBlock <= number && number <= bundle.MaxBlock {
+			bundles = append(bundles, bundle)
+		}
+	}
+	return bundles
+}
+
+// AddBundle validates a bundle of transactions and keeps it for inclusion into
+// a block within its target range. Bundle transactions are not added to the
+// regular pool and are never propagated to the network.
+func (pool *TxPool) AddBundle(bundle *Bundle) (common.Hash, error) {
+	if len(bundle.Txs) == 0 {
+		return common.Hash{}, ErrBundleEmpty
+	}
+	if len(bundle.Txs) > BundleMaxTxs {
+		return common.Hash{}, ErrBundleTooLarge
+	}
+	head := pool.chain.CurrentBlock().NumberU64()
+	if bundle.MinBlock > bundle.MaxBlock || bundle.MaxBlock <= head || bundle.MaxBlock > head+BundleMaxBlocks {
+		return common.Hash{}, ErrBundleBlockRange
+	}
+	for i, tx := range bundle.Txs {
+		from, err := types.Sender(pool.signer, tx)
+		if err != nil {
+			return common.Hash{}, ErrInvalidSender
+		}
+		if i == 0 {
+			bundle.sender = from
+		}
+	}
+	pool.mu.RLock()
+	maxGas, stale := pool.currentMaxGas, pool.staleBundle(bundle)
+	pool.mu.RUnlock()
+
+	if bundle.Gas() > maxGas {
+		return common.Hash{}, ErrGasLimit
+	}
+	if stale {
+		return common.Hash{}, ErrNonceTooLow
+	}
+	pool.bundles.prune(head+1, nil)
+	return pool.bundles.add(bundle)
+}
+
+// staleBundle returns whether any transaction of the bundle has a nonce below
+// the one of its sender in the current state, which is the case once the bundle
+// was included, or anything else was in its place.
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) staleBundle(bundle *Bundle) bool {
+	for _, tx := range bundle.Txs {
+		from, _ := types.Sender(pool.signer, tx) // already validated
+		if tx.Nonce() < pool.currentState.GetNonce(from) {
+			return true
+		}
+	}
+	return false
+}
+
+// demoteBundles drops all bundles which can't be included anymore after the
+// given head, because their block range passed or their nonces went stale.
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) demoteBundles(head *types.Header) {
+	pool.bundles.prune(head.Number.Uint64()+1, pool.staleBundle)
+}
+
+// Bundles returns the bundles which may be included into the block with the
+// given number, in arrival order. Bundles which can only target older blocks
+// are dropped.
+func (pool *TxPool) Bundles(number uint64) []*Bundle {
+	pool.bundles.prune(number, nil)
+	return pool.bundles.includable(number)
+}
+
+// ApplyBundle applies the transactions of a bundle one after the other to the
+// given state, starting at transaction index txIndex of the block. If any of
+// the transactions can't be applied or fails execution, an error is returned
+// and the state, gas pool and used gas counter are left untouched.
+func ApplyBundle(config *params.ChainConfig, bc ChainContext, author *common.Address, gp *GasPool, statedb *state.StateDB, header *types.Header, txs types.Transactions, txIndex int, usedGas *uint64, cfg vm.Config, extraValidator types.EvmExtraValidator) ([]*types.Receipt, error) {
+	// The state is finalised after every transaction, which drops its journal,
+	// so a partially applied bundle can't be reverted. Do a dry run on a copy.
+	var (
+		simGas  = GasPool(gp.Gas())
+		simUsed = *usedGas
+	)
+	if _, err := applyBundle(config, bc, author, &simGas, statedb.Copy(), header, txs, txIndex, &simUsed, cfg, extraValidator); err != nil {
+		return nil, err
+	}
+	return applyBundle(config, bc, author, gp, statedb, header, txs, txIndex, usedGas, cfg, extraValidator)
+}
+
+func applyBundle(config *params.ChainConfig, bc ChainContext, author *common.Address, gp *GasPool, statedb *state.StateDB, header *types.Header, txs types.Transactions, txIndex int, usedGas *uint64, cfg vm.Config, extraValidator types.EvmExtraValidator) ([]*types.Receipt, error) {
+	receipts := make([]*types.Receipt, 0, len(txs))
+	for i, tx := range txs {
+		statedb.Prepare(tx.Hash(), txIndex+i)
+
+		receipt, err := ApplyTransaction(config, bc, author, gp, statedb, header, tx, usedGas, cfg, extraValidator)
+		if err == nil && receipt.Status != types.ReceiptStatusSuccessful {
+			err = ErrBundleReverted
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not apply bundle tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+		}
+		receipts = append(receipts, receipt)
+	}
+	return receipts, nil
+}
+
+// ValidateBundle runs the consensus related validation of the given PoSA engine
+// on every transaction of a bundle.
+func ValidateBundle(posa consensus.PoSA, signer types.Signer, txs types.Transactions, header *types.Header, statedb *state.StateDB) error {
+	for i, tx := range txs {
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			return fmt.Errorf("bundle tx %d [%v]: %w", i, tx.Hash().Hex(), ErrInvalidSender)
+		}
+		if err := posa.ValidateTx(from, tx, header, statedb); err != nil {
+			return fmt.Errorf("bundle tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+		}
+	}
+	return nil
+}
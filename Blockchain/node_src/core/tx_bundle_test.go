@@ -0,0 +1,165 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that bundles are validated on submission and only returned for the
+// blocks within their target range.
+func TestBundlePool(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	tx0, tx1 := transaction(0, 100000, key), transaction(1, 100000, key)
+
+	if _, err := pool.AddBundle(&Bundle{MinBlock: 1, MaxBlock: 2}); err != ErrBundleEmpty {
+		t.Fatalf("empty bundle error mismatch: have %v, want %v", err, ErrBundleEmpty)
+	}
+	if _, err := pool.AddBundle(&Bundle{Txs: types.Transactions{tx0}, MinBlock: 3, MaxBlock: 2}); err != ErrBundleBlockRange {
+		t.Fatalf("empty range error mismatch: have %v, want %v", err, ErrBundleBlockRange)
+	}
+	if _, err := pool.AddBundle(&Bundle{Txs: types.Transactions{tx0}, MinBlock: 0, MaxBlock: 0}); err != ErrBundleBlockRange {
+		t.Fatalf("passed range error mismatch: have %v, want %v", err, ErrBundleBlockRange)
+	}
+	if _, err := pool.AddBundle(&Bundle{Txs: types.Transactions{transaction(0, testTxPoolConfig.PriceLimit*1000000000, key)}, MinBlock: 1, MaxBlock: 1}); err != ErrGasLimit {
+		t.Fatalf("oversized bundle error mismatch: have %v, want %v", err, ErrGasLimit)
+	}
+	short := &Bundle{Txs: types.Transactions{tx0}, MinBlock: 1, MaxBlock: 1}
+	long := &Bundle{Txs: types.Transactions{tx0, tx1}, MinBlock: 2, MaxBlock: 4}
+
+	for _, bundle := range []*Bundle{short, long} {
+		hash, err := pool.AddBundle(bundle)
+		if err != nil {
+			t.Fatalf("failed to add bundle: %v", err)
+		}
+		if hash != bundle.Hash() {
+			t.Fatalf("bundle hash mismatch: have %x, want %x", hash, bundle.Hash())
+		}
+	}
+	if _, err := pool.AddBundle(long); err != ErrAlreadyKnown {
+		t.Fatalf("duplicate bundle error mismatch: have %v, want %v", err, ErrAlreadyKnown)
+	}
+	tests := []struct {
+		number  uint64
+		bundles []*Bundle
+	}{
+		{1, []*Bundle{short}},
+		{2, []*Bundle{long}},
+		{5, nil},
+	}
+	for i, tt := range tests {
+		bundles := pool.Bundles(tt.number)
+		if len(bundles) != len(tt.bundles) {
+			t.Fatalf("test %d: bundle count mismatch: have %d, want %d", i, len(bundles), len(tt.bundles))
+		}
+		for j, bundle := range bundles {
+			if bundle.Hash() != tt.bundles[j].Hash() {
+				t.Errorf("test %d: bundle %d mismatch: have %x, want %x", i, j, bundle.Hash(), tt.bundles[j].Hash())
+			}
+		}
+	}
+	// Bundles for passed blocks must have been dropped
+	if bundles := pool.Bundles(2); len(bundles) != 0 {
+		t.Fatalf("stale bundles retained: %d", len(bundles))
+	}
+}
+
+// Tests that bundles are limited in target range and per sender, and dropped
+// once their nonces go stale.
+func TestBundlePoolLimits(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	if _, err := pool.AddBundle(&Bundle{Txs: types.Transactions{transaction(0, 100000, key)}, MinBlock: 1, MaxBlock: BundleMaxBlocks + 1}); err != ErrBundleBlockRange {
+		t.Fatalf("far range error mismatch: have %v, want %v", err, ErrBundleBlockRange)
+	}
+	for i := 0; i < bundleSenderSlots; i++ {
+		if _, err := pool.AddBundle(&Bundle{Txs: types.Transactions{transaction(uint64(i), 100000, key)}, MinBlock: 1, MaxBlock: 10}); err != nil {
+			t.Fatalf("bundle %d: failed to add: %v", i, err)
+		}
+	}
+	if _, err := pool.AddBundle(&Bundle{Txs: types.Transactions{transaction(bundleSenderSlots, 100000, key)}, MinBlock: 1, MaxBlock: 10}); err != ErrBundleSenderLimit {
+		t.Fatalf("sender limit error mismatch: have %v, want %v", err, ErrBundleSenderLimit)
+	}
+	// Once the sender's nonce moves on, the bundles below it are dropped and
+	// rejected
+	testSetNonce(pool, addr, 4)
+
+	pool.mu.Lock()
+	pool.demoteBundles(pool.chain.CurrentBlock().Header())
+	pool.mu.Unlock()
+
+	if have, want := len(pool.Bundles(1)), bundleSenderSlots-4; have != want {
+		t.Fatalf("bundle count mismatch: have %d, want %d", have, want)
+	}
+	if _, err := pool.AddBundle(&Bundle{Txs: types.Transactions{pricedTransaction(3, 100000, big.NewInt(2), key)}, MinBlock: 1, MaxBlock: 10}); err != ErrNonceTooLow {
+		t.Fatalf("stale bundle error mismatch: have %v, want %v", err, ErrNonceTooLow)
+	}
+	if _, err := pool.AddBundle(&Bundle{Txs: types.Transactions{transaction(bundleSenderSlots, 100000, key)}, MinBlock: 1, MaxBlock: 10}); err != nil {
+		t.Fatalf("failed to add bundle in freed slot: %v", err)
+	}
+}
+
+// Tests that a bundle is applied either fully or not at all.
+func TestApplyBundle(t *testing.T) {
+	t.Parallel()
+
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.AddBalance(addr, big.NewInt(params.Ether))
+
+	header := &types.Header{
+		Number:     big.NewInt(1),
+		GasLimit:   1000000,
+		Difficulty: big.NewInt(1),
+		BaseFee:    big.NewInt(0),
+	}
+	var (
+		coinbase common.Address
+		gp       = new(GasPool).AddGas(header.GasLimit)
+		used     uint64
+	)
+	// A bundle with a nonce gap must leave everything untouched
+	failing := types.Transactions{transaction(0, 21000, key), transaction(2, 21000, key)}
+	if _, err := ApplyBundle(params.TestChainConfig, nil, &coinbase, gp, statedb, header, failing, 0, &used, vm.Config{}, nil); !errors.Is(err, ErrNonceTooHigh) {
+		t.Fatalf("failing bundle error mismatch: have %v, want %v", err, ErrNonceTooHigh)
+	}
+	if nonce := statedb.GetNonce(addr); nonce != 0 {
+		t.Fatalf("failing bundle changed nonce: have %d, want 0", nonce)
+	}
+	if gp.Gas() != header.GasLimit || used != 0 {
+		t.Fatalf("failing bundle used gas: pool %d, used %d", gp.Gas(), used)
+	}
+	// A valid bundle must be applied fully
+	valid := types.Transactions{transaction(0, 21000, key), transaction(1, 21000, key)}
+	receipts, err := ApplyBundle(params.TestChainConfig, nil, &coinbase, gp, statedb, header, valid, 0, &used, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to apply bundle: %v", err)
+	}
+	if len(receipts) != 2 {
+		t.Fatalf("receipt count mismatch: have %d, want 2", len(receipts))
+	}
+	if nonce := statedb.GetNonce(addr); nonce != 2 {
+		t.Fatalf("nonce mismatch: have %d, want 2", nonce)
+	}
+	if used != 42000 || gp.Gas() != header.GasLimit-42000 {
+		t.Fatalf("gas mismatch: pool %d, used %d", gp.Gas(), used)
+	}
+}
@@ -0,0 +1,84 @@
+package core
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// deniedTxLimit is the number of denied transactions remembered so that the
+// reasons they were rejected for can be reported.
+const deniedTxLimit = 1024
+
+var deniedTxMeter = metrics.NewRegisteredMeter("txpool/denied", nil)
+
+// DeniedTx is a transaction the pool rejected because it involves a denied
+// address.
+type DeniedTx struct {
+	Hash    common.Hash     `json:"hash"`
+	From    common.Address  `json:"from"`
+	To      *common.Address `json:"to"`
+	Address common.Address  `json:"address"` // Denied address
+	Check   string          `json:"check"`   // "from" if the sender is denied, "to" if the recipient is
+	Reason  string          `json:"reason"`
+	Time    time.Time       `json:"time"`
+}
+
+// deniedTxJournal remembers the most recently denied transactions.
+type deniedTxJournal struct {
+	txs  []*DeniedTx // Ring buffer of the denied transactions
+	next int         // Position of the next transaction in the ring
+	lock sync.RWMutex
+}
+
+func newDeniedTxJournal() *deniedTxJournal {
+	return &deniedTxJournal{txs: make([]*DeniedTx, 0, deniedTxLimit)}
+}
+
+// add records the transaction denied with the given error.
+func (j *deniedTxJournal) add(tx *types.Transaction, from common.Address, err error) {
+	denied := &DeniedTx{
+		Hash:   tx.Hash(),
+		From:   from,
+		To:     tx.To(),
+		Reason: err.Error(),
+		Time:   time.Now(),
+	}
+	var derr *types.DeniedAddressError
+	if errors.As(err, &derr) {
+		denied.Address = derr.Address
+		switch derr.Check {
+		case common.CheckFrom:
+			denied.Check = "from"
+		case common.CheckTo:
+			denied.Check = "to"
+		}
+	}
+	deniedTxMeter.Mark(1)
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if len(j.txs) < deniedTxLimit {
+		j.txs = append(j.txs, denied)
+	} else {
+		j.txs[j.next] = denied
+	}
+	j.next = (j.next + 1) % deniedTxLimit
+}
+
+// list returns the remembered denied transactions, the most recent first.
+func (j *deniedTxJournal) list() []*DeniedTx {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+
+	txs := make([]*DeniedTx, 0, len(j.txs))
+	for i := 1; i <= len(j.txs); i++ {
+		txs = append(txs, j.txs[(j.next-i+len(j.txs))%len(j.txs)])
+	}
+	return txs
+}
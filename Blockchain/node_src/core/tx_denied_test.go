@@ -0,0 +1,69 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// denyingValidator denies the transactions sent to the given recipient.
+type denyingValidator struct {
+	to common.Address
+}
+
+func (v *denyingValidator) ValidateTx(sender common.Address, tx *types.Transaction, header *types.Header, parentState *state.StateDB) error {
+	if tx.To() != nil && *tx.To() == v.to {
+		return &types.DeniedAddressError{Address: v.to, Check: common.CheckTo}
+	}
+	return nil
+}
+
+// Tests that the transactions rejected for involving a denied address are
+// journaled along with the reason, the most recent first.
+func TestTransactionDenied(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, from, big.NewInt(1000000000))
+
+	denied := common.Address{0xd}
+	pool.InitExTxValidator(&denyingValidator{to: denied})
+
+	tx := func(nonce uint64, to common.Address) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, to, big.NewInt(1), 100000, big.NewInt(1), nil), types.HomesteadSigner{}, key)
+		return tx
+	}
+	first, second := tx(0, denied), tx(1, denied)
+	for _, tx := range []*types.Transaction{first, second} {
+		if err := pool.AddRemote(tx); !errors.Is(err, types.ErrAddressDenied) {
+			t.Fatalf("denied transaction error mismatch: have %v, want %v", err, types.ErrAddressDenied)
+		}
+	}
+	if err := pool.AddRemote(tx(0, common.Address{0x1})); err != nil {
+		t.Fatalf("failed to add allowed transaction: %v", err)
+	}
+	list := pool.Denied()
+	if len(list) != 2 || list[0].Hash != second.Hash() || list[1].Hash != first.Hash() {
+		t.Fatalf("denied transactions mismatch: have %v", list)
+	}
+	if list[0].From != from || list[0].Address != denied || list[0].Check != "to" || list[0].Reason == "" {
+		t.Errorf("denied transaction mismatch: have %+v", list[0])
+	}
+	// The journal only remembers the most recent transactions
+	journal := newDeniedTxJournal()
+	for i := 0; i < deniedTxLimit+10; i++ {
+		journal.add(tx(uint64(i), denied), from, types.ErrAddressDenied)
+	}
+	list = journal.list()
+	if len(list) != deniedTxLimit || list[0].Hash != tx(deniedTxLimit+9, denied).Hash() || list[len(list)-1].Hash != tx(10, denied).Hash() {
+		t.Errorf("journal bounds mismatch: have %d transactions", len(list))
+	}
+}
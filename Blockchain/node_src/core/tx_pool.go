@@ -241,8 +241,10 @@ type TxPool struct {
 	pendingNonces *txNoncer      // Pending state tracking virtual nonces
 	currentMaxGas uint64         // Current gas limit for transaction caps
 
-	locals  *accountSet // Set of local transaction to exempt from eviction rules
-	journal *txJournal  // Journal of local transaction to back up to disk
+	locals  *accountSet   // Set of local transaction to exempt from eviction rules
+	journal *txJournal    // Journal of local transaction to back up to disk
+	private *privateTxSet // Set of private transactions which must not be gossiped
+	bundles *bundleSet    // Set of transaction bundles to be included all-or-nothing
 
 	pending map[common.Address]*txList   // All currently processable transactions
 	queue   map[common.Address]*txList   // Queued but non-processable transactions
@@ -250,7 +252,8 @@ type TxPool struct {
 	all     *txLookup                    // All transactions to allow lookups
 	priced  *txPricedList                // All transactions sorted by price
 
-	jamIndexer *txJamIndexer // tx jam indexer
+	jamIndexer *txJamIndexer    // tx jam indexer
+	denied     *deniedTxJournal // Recently denied transactions
 
 	txValidator    exTxValidator // A specific consensus can use this to do some extra validation to a transaction
 	nextFakeHeader *types.Header // A fake header of next block for extra transaction validation
@@ -292,6 +295,9 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		queue:           make(map[common.Address]*txList),
 		beats:           make(map[common.Address]time.Time),
 		all:             newTxLookup(),
+		private:         newPrivateTxSet(),
+		denied:          newDeniedTxJournal(),
+		bundles:         newBundleSet(),
 		chainHeadCh:     make(chan ChainHeadEvent, chainHeadChanSize),
 		reqResetCh:      make(chan *txpoolResetRequest),
 		reqPromoteCh:    make(chan *accountSet),
@@ -575,6 +581,12 @@ func (pool *TxPool) Locals() []common.Address {
 	return pool.locals.flatten()
 }
 
+// Denied returns the transactions recently rejected because they involve a
+// denied address, the most recent first.
+func (pool *TxPool) Denied() []*DeniedTx {
+	return pool.denied.list()
+}
+
 // JamIndex returns the jam index which is evaluated by current pending transactions.
 func (pool *TxPool) JamIndex() int {
 	return pool.jamIndexer.JamIndex()
@@ -592,6 +604,16 @@ func (pool *TxPool) local() map[common.Address]types.Transactions {
 		if queued := pool.queue[addr]; queued != nil {
 			txs[addr] = append(txs[addr], queued.Flatten()...)
 		}
+		// Private transactions are short lived, never persist them
+		for i := 0; i < len(txs[addr]); i++ {
+			if pool.private.contains(txs[addr][i].Hash()) {
+				txs[addr] = append(txs[addr][:i], txs[addr][i+1:]...)
+				i--
+			}
+		}
+		if len(txs[addr]) == 0 {
+			delete(txs, addr)
+		}
 	}
 	return txs
 }
@@ -662,7 +684,8 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	// do some extra validation if needed
 	if pool.txValidator != nil && !pool.disableExValidate {
 		err := pool.txValidator.ValidateTx(from, tx, pool.nextFakeHeader, pool.currentState)
-		if err == types.ErrAddressDenied {
+		if errors.Is(err, types.ErrAddressDenied) {
+			pool.denied.add(tx, from, err)
 			return err
 		}
 		if err != nil {
@@ -826,6 +849,10 @@ func (pool *TxPool) journalTx(from common.Address, tx *types.Transaction) {
 	if pool.journal == nil || !pool.locals.contains(from) {
 		return
 	}
+	// Private transactions expire quickly, don't resurrect them on restart
+	if pool.private.contains(tx.Hash()) {
+		return
+	}
 	if err := pool.journal.insert(tx); err != nil {
 		log.Warn("Failed to journal local transaction", "err", err)
 	}
@@ -1200,6 +1227,13 @@ func (pool *TxPool) runReorg(done chan struct{}, reset *txpoolResetRequest, dirt
 	// because of another transaction (e.g. higher gas price).
 	if reset != nil {
 		pool.demoteUnexecutables()
+		if reset.newHead != nil {
+			pool.demotePrivates(reset.newHead)
+			pool.demoteBundles(reset.newHead)
+		} else {
+			pool.demotePrivates(pool.chain.CurrentBlock().Header())
+			pool.demoteBundles(pool.chain.CurrentBlock().Header())
+		}
 		if reset.newHead != nil && pool.chainconfig.IsLondon(new(big.Int).Add(reset.newHead.Number, big.NewInt(1))) {
 			pendingBaseFee := misc.CalcBaseFee(pool.chainconfig, reset.newHead)
 			pool.priced.SetBaseFee(pendingBaseFee)
@@ -0,0 +1,220 @@
+package core
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// privateTxExpiredLimit is the number of expired private transaction hashes
+// remembered so that their status can still be reported.
+const privateTxExpiredLimit = 4096
+
+var (
+	// ErrPrivateTxExpired is returned if a private transaction is submitted with
+	// a max block number which is already reached by the local chain.
+	ErrPrivateTxExpired = errors.New("private transaction max block number reached")
+
+	privateGauge        = metrics.NewRegisteredGauge("txpool/private", nil)
+	privateExpiredMeter = metrics.NewRegisteredMeter("txpool/private/expired", nil)
+	privateRemovedMeter = metrics.NewRegisteredMeter("txpool/private/removed", nil)
+)
+
+// PrivateTxStatus is the current status of a private transaction as seen by the pool.
+type PrivateTxStatus uint
+
+const (
+	PrivateTxStatusUnknown PrivateTxStatus = iota
+	PrivateTxStatusPending
+	PrivateTxStatusExpired
+)
+
+// String implements fmt.Stringer.
+func (s PrivateTxStatus) String() string {
+	switch s {
+	case PrivateTxStatusPending:
+		return "pending"
+	case PrivateTxStatusExpired:
+		return "expired"
+	default:
+		return "unknown"
+	}
+}
+
+// privateTxSet tracks the transactions which were submitted privately, which
+// must never be gossiped to the network and which are dropped from the pool
+// once their max block number is reached.
+type privateTxSet struct {
+	txs     map[common.Hash]uint64 // Live private transactions and their max block numbers
+	expired map[common.Hash]uint64 // Recently expired private transactions and their max block numbers
+	order   []common.Hash          // Insertion order of the expired set, for bounding it
+	lock    sync.RWMutex
+}
+
+func newPrivateTxSet() *privateTxSet {
+	return &privateTxSet{
+		txs:     make(map[common.Hash]uint64),
+		expired: make(map[common.Hash]uint64),
+	}
+}
+
+// add marks a transaction as private until the given block number.
+func (s *privateTxSet) add(hash common.Hash, maxBlock uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.txs[hash]; !ok {
+		privateGauge.Inc(1)
+	}
+	s.txs[hash] = maxBlock
+}
+
+// remove forgets a private transaction, e.g. when the pool rejected it.
+func (s *privateTxSet) remove(hash common.Hash) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.txs[hash]; ok {
+		delete(s.txs, hash)
+		privateGauge.Dec(1)
+	}
+}
+
+// contains returns whether a transaction is a live private one.
+func (s *privateTxSet) contains(hash common.Hash) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	_, ok := s.txs[hash]
+	return ok
+}
+
+// status returns the tracking status of a private transaction along with its
+// max block number.
+func (s *privateTxSet) status(hash common.Hash) (PrivateTxStatus, uint64) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if max, ok := s.txs[hash]; ok {
+		return PrivateTxStatusPending, max
+	}
+	if max, ok := s.expired[hash]; ok {
+		return PrivateTxStatusExpired, max
+	}
+	return PrivateTxStatusUnknown, 0
+}
+
+// flatten returns the hashes and max block numbers of all live private transactions.
+func (s *privateTxSet) flatten() map[common.Hash]uint64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	txs := make(map[common.Hash]uint64, len(s.txs))
+	for hash, max := range s.txs {
+		txs[hash] = max
+	}
+	return txs
+}
+
+// expire moves a live private transaction into the expired set.
+func (s *privateTxSet) expire(hash common.Hash) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	max, ok := s.txs[hash]
+	if !ok {
+		return
+	}
+	delete(s.txs, hash)
+	privateGauge.Dec(1)
+
+	s.expired[hash] = max
+	s.order = append(s.order, hash)
+	for len(s.order) > privateTxExpiredLimit {
+		delete(s.expired, s.order[0])
+		s.order = s.order[1:]
+	}
+}
+
+// AddPrivate enqueues a single local transaction into the pool if it is valid,
+// marking it as private. Private transactions are never announced or broadcast
+// to the network by the protocol handler, and are dropped from the pool once
+// the chain reaches maxBlock without including them.
+func (pool *TxPool) AddPrivate(tx *types.Transaction, maxBlock uint64) error {
+	return pool.addPrivate(tx, maxBlock, !pool.config.NoLocals)
+}
+
+// AddRemotePrivate enqueues a single private transaction forwarded by a peer
+// into the pool if it is valid. It is treated as a remote transaction, subject
+// to the pricing and eviction rules of any other.
+func (pool *TxPool) AddRemotePrivate(tx *types.Transaction, maxBlock uint64) error {
+	return pool.addPrivate(tx, maxBlock, false)
+}
+
+func (pool *TxPool) addPrivate(tx *types.Transaction, maxBlock uint64, local bool) error {
+	if head := pool.chain.CurrentBlock().NumberU64(); maxBlock <= head {
+		return ErrPrivateTxExpired
+	}
+	if _, err := types.Sender(pool.signer, tx); err != nil {
+		invalidTxMeter.Mark(1)
+		return ErrInvalidSender
+	}
+	// Mark the transaction within the same critical section as the insertion,
+	// so neither a concurrent reorg nor any subscriber of the new transaction
+	// event ever sees it as a public one.
+	hash := tx.Hash()
+
+	pool.mu.Lock()
+	known := pool.private.contains(hash)
+	if !known && pool.all.Get(hash) != nil {
+		pool.mu.Unlock()
+		knownTxMeter.Mark(1)
+		return ErrAlreadyKnown
+	}
+	pool.private.add(hash, maxBlock)
+	errs, dirtyAddrs := pool.addTxsLocked([]*types.Transaction{tx}, local)
+	if errs[0] != nil && !known {
+		pool.private.remove(hash)
+	}
+	pool.mu.Unlock()
+
+	<-pool.requestPromoteExecutables(dirtyAddrs)
+	return errs[0]
+}
+
+// IsPrivate returns whether the given transaction was submitted privately and
+// is still tracked by the pool.
+func (pool *TxPool) IsPrivate(hash common.Hash) bool {
+	return pool.private.contains(hash)
+}
+
+// PrivateStatus returns the status of a privately submitted transaction along
+// with the max block number it was submitted with.
+func (pool *TxPool) PrivateStatus(hash common.Hash) (PrivateTxStatus, uint64) {
+	return pool.private.status(hash)
+}
+
+// demotePrivates drops all private transactions which can no longer be included
+// before their max block number, and forgets those which left the pool (most
+// likely because they were included).
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) demotePrivates(head *types.Header) {
+	next := head.Number.Uint64() + 1
+	for hash, max := range pool.private.flatten() {
+		if pool.all.Get(hash) == nil {
+			pool.private.remove(hash)
+			privateRemovedMeter.Mark(1)
+			continue
+		}
+		if max < next {
+			log.Trace("Dropping expired private transaction", "hash", hash, "max", max, "next", next)
+			pool.removeTx(hash, true)
+			pool.private.expire(hash)
+			privateExpiredMeter.Mark(1)
+		}
+	}
+}
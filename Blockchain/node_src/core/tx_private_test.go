@@ -0,0 +1,85 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Tests that private transactions are tracked by the pool, kept out of the
+// local set used for journaling, and dropped once their max block is reached.
+func TestTransactionPrivateExpiry(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	if err := pool.AddPrivate(transaction(0, 100000, key), 0); err != ErrPrivateTxExpired {
+		t.Fatalf("expired private transaction error mismatch: have %v, want %v", err, ErrPrivateTxExpired)
+	}
+	short, long := transaction(0, 100000, key), transaction(1, 100000, key)
+	if err := pool.AddPrivate(short, 3); err != nil {
+		t.Fatalf("failed to add private transaction: %v", err)
+	}
+	if err := pool.AddPrivate(long, 10); err != nil {
+		t.Fatalf("failed to add private transaction: %v", err)
+	}
+	if err := pool.AddPrivate(long, 10); err != ErrAlreadyKnown {
+		t.Fatalf("private re-add error mismatch: have %v, want %v", err, ErrAlreadyKnown)
+	}
+	if err := pool.AddLocal(short); err != ErrAlreadyKnown {
+		t.Fatalf("public re-add error mismatch: have %v, want %v", err, ErrAlreadyKnown)
+	}
+	if !pool.IsPrivate(short.Hash()) || !pool.IsPrivate(long.Hash()) {
+		t.Fatalf("private transactions not tracked")
+	}
+	pool.mu.Lock()
+	locals := len(pool.local())
+	pool.mu.Unlock()
+	if locals != 0 {
+		t.Fatalf("private transactions journaled: %d accounts", locals)
+	}
+	// Move the head past the first transaction's max block and check expiry
+	head := pool.chain.CurrentBlock().Header()
+	head.Number = big.NewInt(5)
+	<-pool.requestReset(nil, head)
+
+	if status, max := pool.PrivateStatus(short.Hash()); status != PrivateTxStatusExpired || max != 3 {
+		t.Fatalf("expired transaction status mismatch: have %v/%d, want %v/%d", status, max, PrivateTxStatusExpired, 3)
+	}
+	if pool.Get(short.Hash()) != nil {
+		t.Fatalf("expired private transaction still pooled")
+	}
+	if status, max := pool.PrivateStatus(long.Hash()); status != PrivateTxStatusPending || max != 10 {
+		t.Fatalf("pending transaction status mismatch: have %v/%d, want %v/%d", status, max, PrivateTxStatusPending, 10)
+	}
+}
+
+// Tests that private transactions forwarded by peers are pooled as remote ones,
+// subject to the price limit and never making their senders local.
+func TestTransactionPrivateRemote(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, from, big.NewInt(1000000000))
+
+	if err := pool.AddRemotePrivate(pricedTransaction(0, 100000, big.NewInt(0), key), 10); err != ErrUnderpriced {
+		t.Fatalf("underpriced private transaction error mismatch: have %v, want %v", err, ErrUnderpriced)
+	}
+	tx := transaction(0, 100000, key)
+	if err := pool.AddRemotePrivate(tx, 10); err != nil {
+		t.Fatalf("failed to add private transaction: %v", err)
+	}
+	if !pool.IsPrivate(tx.Hash()) {
+		t.Fatalf("private transaction not tracked")
+	}
+	if pool.locals.contains(from) {
+		t.Fatalf("sender of forwarded private transaction made local")
+	}
+}
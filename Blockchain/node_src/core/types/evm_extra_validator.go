@@ -10,3 +10,25 @@ type EvmExtraValidator interface {
 	// IsLogDenied returns whether a log (contract event) is denied.
 	IsLogDenied(log *Log) bool
 }
+
+// DeniedAddressError is returned when a transaction involves a denied address,
+// and tells which one and how.
+type DeniedAddressError struct {
+	Address common.Address
+	Check   common.AddressCheckType // Whether the address is denied as sender or as recipient
+}
+
+func (e *DeniedAddressError) Error() string {
+	switch e.Check {
+	case common.CheckFrom:
+		return ErrAddressDenied.Error() + ": sender " + e.Address.Hex()
+	case common.CheckTo:
+		return ErrAddressDenied.Error() + ": recipient " + e.Address.Hex()
+	}
+	return ErrAddressDenied.Error() + ": " + e.Address.Hex()
+}
+
+// Is makes the error match ErrAddressDenied.
+func (e *DeniedAddressError) Is(target error) bool {
+	return target == ErrAddressDenied
+}
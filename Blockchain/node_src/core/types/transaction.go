@@ -25,6 +25,7 @@ var (
 	ErrGasFeeCapTooLow      = errors.New("fee cap less than base fee")
 	errEmptyTypedTx         = errors.New("empty typed transaction bytes")
 	ErrAddressDenied        = errors.New("address denied")
+	ErrDeveloperNotAllowed  = errors.New("developer not allowed to create contracts")
 )
 
 // Transaction types.
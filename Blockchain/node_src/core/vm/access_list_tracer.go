@@ -149,6 +149,11 @@ func (a *AccessListTracer) CaptureState(pc uint64, op OpCode, gas, cost uint64,
 func (*AccessListTracer) CaptureFault(pc uint64, op OpCode, gas, cost uint64, scope *ScopeContext, depth int, err error) {
 }
 
+func (*AccessListTracer) CaptureTxStart(env *EVM, from common.Address, to *common.Address, gasLimit uint64) {
+}
+
+func (*AccessListTracer) CaptureTxEnd(restGas uint64) {}
+
 func (*AccessListTracer) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) {}
 
 func (*AccessListTracer) CaptureEnter(typ OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
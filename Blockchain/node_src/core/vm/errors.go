@@ -5,6 +5,8 @@ package vm
 import (
 	"errors"
 	"fmt"
+
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 // List evm execution errors
@@ -21,7 +23,7 @@ var (
 	ErrReturnDataOutOfBounds    = errors.New("return data out of bounds")
 	ErrGasUintOverflow          = errors.New("gas uint64 overflow")
 	ErrInvalidCode              = errors.New("invalid code: must not begin with 0xef")
-	ErrUnauthorizedDeveloper    = errors.New("unauthorized developer")
+	ErrUnauthorizedDeveloper    = types.ErrDeveloperNotAllowed
 	ErrNonceUintOverflow        = errors.New("nonce uint64 overflow")
 )
 
@@ -89,7 +89,14 @@ func (s *StructLog) ErrorString() string {
 // current VM state.
 // Note that reference types are actual VM data structures; make copies
 // if you need to retain them beyond the current call.
+//
+// CaptureTxStart and CaptureTxEnd wrap a full state transition, before any
+// gas is bought and after all refunds and fees are settled. They are not
+// invoked for calls executed outside of a state transition (e.g. system
+// transactions of PoSA engines).
 type EVMLogger interface {
+	CaptureTxStart(env *EVM, from common.Address, to *common.Address, gasLimit uint64)
+	CaptureTxEnd(restGas uint64)
 	CaptureStart(env *EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int)
 	CaptureState(pc uint64, op OpCode, gas, cost uint64, scope *ScopeContext, rData []byte, depth int, err error)
 	CaptureEnter(typ OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int)
@@ -201,6 +208,13 @@ func (l *StructLogger) CaptureState(pc uint64, op OpCode, gas, cost uint64, scop
 func (l *StructLogger) CaptureFault(pc uint64, op OpCode, gas, cost uint64, scope *ScopeContext, depth int, err error) {
 }
 
+// CaptureTxStart implements the EVMLogger interface.
+func (l *StructLogger) CaptureTxStart(env *EVM, from common.Address, to *common.Address, gasLimit uint64) {
+}
+
+// CaptureTxEnd implements the EVMLogger interface.
+func (l *StructLogger) CaptureTxEnd(restGas uint64) {}
+
 // CaptureEnd is called after the call finishes to finalize the tracing.
 func (l *StructLogger) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) {
 	l.output = output
@@ -333,6 +347,11 @@ func (t *mdLogger) CaptureFault(pc uint64, op OpCode, gas, cost uint64, scope *S
 	fmt.Fprintf(t.out, "\nError: at pc=%d, op=%v: %v\n", pc, op, err)
 }
 
+func (t *mdLogger) CaptureTxStart(env *EVM, from common.Address, to *common.Address, gasLimit uint64) {
+}
+
+func (t *mdLogger) CaptureTxEnd(restGas uint64) {}
+
 func (t *mdLogger) CaptureEnd(output []byte, gasUsed uint64, tm time.Duration, err error) {
 	fmt.Fprintf(t.out, "\nOutput: `0x%x`\nConsumed gas: `%d`\nError: `%v`\n",
 		output, gasUsed, err)
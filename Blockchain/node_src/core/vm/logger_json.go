@@ -61,6 +61,13 @@ func (l *JSONLogger) CaptureState(pc uint64, op OpCode, gas, cost uint64, scope
 	l.encoder.Encode(log)
 }
 
+// CaptureTxStart implements the EVMLogger interface.
+func (l *JSONLogger) CaptureTxStart(env *EVM, from common.Address, to *common.Address, gasLimit uint64) {
+}
+
+// CaptureTxEnd implements the EVMLogger interface.
+func (l *JSONLogger) CaptureTxEnd(restGas uint64) {}
+
 // CaptureEnd is triggered at end of execution.
 func (l *JSONLogger) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) {
 	type endLog struct {
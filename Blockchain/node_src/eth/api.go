@@ -20,6 +20,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -526,6 +527,58 @@ func (api *PrivateDebugAPI) getModifiedAccounts(startBlock, endBlock *types.Bloc
 	return dirty, nil
 }
 
+// maxAccountHistory is the maximum number of changes returned by a single
+// GetAccountHistory call.
+const maxAccountHistory = 1024
+
+// AccountHistoryEntry is the state of an account after a block changing it.
+type AccountHistoryEntry struct {
+	Block    hexutil.Uint64 `json:"block"`
+	Exists   bool           `json:"exists"`
+	Balance  *hexutil.Big   `json:"balance"`
+	Nonce    hexutil.Uint64 `json:"nonce"`
+	CodeHash common.Hash    `json:"codeHash"`
+}
+
+// GetAccountHistory returns the state of an account after every block in the
+// range [from, to] which changed it, read from the state index. At most
+// maxAccountHistory changes are returned, the next ones can be retrieved by
+// continuing after the last returned block.
+func (api *PrivateDebugAPI) GetAccountHistory(address common.Address, from, to rpc.BlockNumber) ([]AccountHistoryEntry, error) {
+	var resolveNum = func(num rpc.BlockNumber) uint64 {
+		if num.Int64() < 0 {
+			return api.eth.blockchain.CurrentBlock().NumberU64()
+		}
+		return uint64(num.Int64())
+	}
+	start, end := resolveNum(from), resolveNum(to)
+	if start > end {
+		return nil, fmt.Errorf("start block height (%d) must not be greater than end block height (%d)", start, end)
+	}
+	var (
+		db      = api.eth.ChainDb()
+		entries = []AccountHistoryEntry{}
+	)
+	for _, number := range rawdb.ReadAccountIndexBlocks(db, crypto.Keccak256Hash(address.Bytes()), start, end) {
+		if len(entries) == maxAccountHistory {
+			break
+		}
+		hash := rawdb.ReadCanonicalHash(db, number)
+		statedb, err := api.eth.blockchain.HistoricState(hash, number)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, AccountHistoryEntry{
+			Block:    hexutil.Uint64(number),
+			Exists:   statedb.Exist(address),
+			Balance:  (*hexutil.Big)(statedb.GetBalance(address)),
+			Nonce:    hexutil.Uint64(statedb.GetNonce(address)),
+			CodeHash: statedb.GetCodeHash(address),
+		})
+	}
+	return entries, nil
+}
+
 // GetAccessibleState returns the first number where the node has accessible
 // state on disk. Note this being the post-state of that block and the pre-state
 // The (from, to) parameters are the sequence of blocks to search, which can go
@@ -0,0 +1,177 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// bundleDefaultBlocks is the number of blocks a bundle targets if no max
+	// block number is given.
+	bundleDefaultBlocks = 25
+)
+
+// PublicBundleAPI provides an API to submit bundles of transactions which are
+// included into a block all-or-nothing, and to simulate them beforehand.
+type PublicBundleAPI struct {
+	e *Ethereum
+}
+
+// NewPublicBundleAPI creates a new bundle API.
+func NewPublicBundleAPI(e *Ethereum) *PublicBundleAPI {
+	return &PublicBundleAPI{e}
+}
+
+// SendBundleArgs represents the arguments for submitting a bundle.
+type SendBundleArgs struct {
+	Txs            []hexutil.Bytes `json:"txs"`
+	MinBlockNumber *hexutil.Uint64 `json:"minBlockNumber"`
+	MaxBlockNumber *hexutil.Uint64 `json:"maxBlockNumber"`
+}
+
+// CallBundleArgs represents the arguments for simulating a bundle.
+type CallBundleArgs struct {
+	Txs []hexutil.Bytes `json:"txs"`
+}
+
+// BundleTxResult is the outcome of a single transaction of a simulated bundle.
+type BundleTxResult struct {
+	TxHash  common.Hash    `json:"txHash"`
+	GasUsed hexutil.Uint64 `json:"gasUsed"`
+	Logs    []*types.Log   `json:"logs"`
+	Error   string         `json:"error,omitempty"`
+}
+
+// CallBundleResult is the outcome of a simulated bundle.
+type CallBundleResult struct {
+	BundleHash  common.Hash       `json:"bundleHash"`
+	BlockNumber hexutil.Uint64    `json:"blockNumber"`
+	GasUsed     hexutil.Uint64    `json:"gasUsed"`
+	Success     bool              `json:"success"`
+	Results     []*BundleTxResult `json:"results"`
+}
+
+// decodeBundleTxs decodes and sanity checks the signed transactions of a bundle.
+func (api *PublicBundleAPI) decodeBundleTxs(inputs []hexutil.Bytes) (types.Transactions, error) {
+	if len(inputs) == 0 {
+		return nil, core.ErrBundleEmpty
+	}
+	if len(inputs) > core.BundleMaxTxs {
+		return nil, core.ErrBundleTooLarge
+	}
+	txs := make(types.Transactions, 0, len(inputs))
+	for i, input := range inputs {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(input); err != nil {
+			return nil, fmt.Errorf("bundle tx %d: %v", i, err)
+		}
+		if err := ethapi.CheckSubmittable(api.e.APIBackend, tx); err != nil {
+			return nil, fmt.Errorf("bundle tx %d: %v", i, err)
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+// SendBundle submits a bundle of signed transactions to be included into one of
+// the blocks in the given range, all in the given order or none of them. The
+// bundle is only kept by the local node, it's not propagated to the network.
+func (api *PublicBundleAPI) SendBundle(ctx context.Context, args SendBundleArgs) (common.Hash, error) {
+	txs, err := api.decodeBundleTxs(args.Txs)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	head := api.e.blockchain.CurrentBlock().NumberU64()
+	min := head + 1
+	if args.MinBlockNumber != nil && uint64(*args.MinBlockNumber) > min {
+		min = uint64(*args.MinBlockNumber)
+	}
+	max := min + bundleDefaultBlocks - 1
+	if args.MaxBlockNumber != nil {
+		max = uint64(*args.MaxBlockNumber)
+	}
+	if max > head+core.BundleMaxBlocks {
+		return common.Hash{}, fmt.Errorf("max block number too far in the future: have %d, max %d", max, head+core.BundleMaxBlocks)
+	}
+	hash, err := api.e.txPool.AddBundle(&core.Bundle{Txs: txs, MinBlock: min, MaxBlock: max})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	log.Info("Submitted bundle", "hash", hash, "txs", len(txs), "minBlock", min, "maxBlock", max)
+	return hash, nil
+}
+
+// CallBundle simulates a bundle on top of the pending state, reporting the
+// outcome of every transaction up to the first failing one. Nothing is
+// submitted to the pool.
+func (api *PublicBundleAPI) CallBundle(ctx context.Context, args CallBundleArgs) (*CallBundleResult, error) {
+	txs, err := api.decodeBundleTxs(args.Txs)
+	if err != nil {
+		return nil, err
+	}
+	block, statedb := api.e.miner.Pending()
+	if block == nil || statedb == nil {
+		return nil, errors.New("pending state not available")
+	}
+	header := block.Header()
+	header.GasUsed = 0
+
+	var (
+		config    = api.e.blockchain.Config()
+		signer    = types.MakeSigner(config, header.Number)
+		gp        = new(core.GasPool).AddGas(header.GasLimit)
+		extra     types.EvmExtraValidator
+		coinbase  = header.Coinbase
+		vmConfig  = *api.e.blockchain.GetVMConfig()
+		bundle    = &core.Bundle{Txs: txs}
+		txResults = make([]*BundleTxResult, 0, len(txs))
+	)
+	if posa, ok := api.e.engine.(consensus.PoSA); ok {
+		if err := core.ValidateBundle(posa, signer, txs, header, statedb); err != nil {
+			return nil, err
+		}
+		extra = posa.CreateEvmExtraValidator(header, statedb)
+	}
+	success := true
+	for i, tx := range txs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		statedb.Prepare(tx.Hash(), len(block.Transactions())+i)
+
+		result := &BundleTxResult{TxHash: tx.Hash()}
+		txResults = append(txResults, result)
+
+		receipt, err := core.ApplyTransaction(config, api.e.blockchain, &coinbase, gp, statedb, header, tx, &header.GasUsed, vmConfig, extra)
+		if err == nil && receipt.Status != types.ReceiptStatusSuccessful {
+			err = core.ErrBundleReverted
+		}
+		if receipt != nil {
+			result.GasUsed = hexutil.Uint64(receipt.GasUsed)
+			result.Logs = receipt.Logs
+		}
+		if err != nil {
+			result.Error = err.Error()
+			success = false
+			break
+		}
+	}
+	return &CallBundleResult{
+		BundleHash:  bundle.Hash(),
+		BlockNumber: hexutil.Uint64(header.Number.Uint64()),
+		GasUsed:     hexutil.Uint64(header.GasUsed),
+		Success:     success,
+		Results:     txResults,
+	}, nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package eth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// privateTxDefaultBlocks is the number of blocks a private transaction stays
+	// valid for if no max block number is given.
+	privateTxDefaultBlocks = 25
+
+	// privateTxMaxBlocks is the maximum number of blocks into the future a private
+	// transaction is allowed to stay pooled for.
+	privateTxMaxBlocks = 1000
+)
+
+// PublicPrivateTxAPI provides an API to submit transactions straight to the
+// configured validators, keeping them out of the public transaction gossip.
+type PublicPrivateTxAPI struct {
+	e *Ethereum
+}
+
+// NewPublicPrivateTxAPI creates a new private transaction API.
+func NewPublicPrivateTxAPI(e *Ethereum) *PublicPrivateTxAPI {
+	return &PublicPrivateTxAPI{e}
+}
+
+// SendPrivateTransaction adds the signed transaction to the local pool as a
+// private one and forwards it to the configured validator peers only. The
+// transaction is dropped if it's not included up to (and including) the block
+// maxBlockNumber.
+func (api *PublicPrivateTxAPI) SendPrivateTransaction(ctx context.Context, input hexutil.Bytes, maxBlockNumber *hexutil.Uint64) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	if err := ethapi.CheckSubmittable(api.e.APIBackend, tx); err != nil {
+		return common.Hash{}, err
+	}
+	head := api.e.blockchain.CurrentBlock().NumberU64()
+	max := head + privateTxDefaultBlocks
+	if maxBlockNumber != nil {
+		max = uint64(*maxBlockNumber)
+	}
+	if max <= head {
+		return common.Hash{}, core.ErrPrivateTxExpired
+	}
+	if max > head+privateTxMaxBlocks {
+		return common.Hash{}, fmt.Errorf("max block number too far in the future: have %d, max %d", max, head+privateTxMaxBlocks)
+	}
+	if err := api.e.txPool.AddPrivate(tx, max); err != nil {
+		return common.Hash{}, err
+	}
+	peers := api.e.handler.BroadcastPrivateTransactions(eth.PrivateTransactionsPacket{
+		{Tx: tx, MaxBlockNumber: max},
+	})
+	if peers == 0 && !api.e.IsMining() {
+		log.Warn("Private transaction not forwarded, no validator peer connected", "hash", tx.Hash())
+	}
+	log.Info("Submitted private transaction", "hash", tx.Hash().Hex(), "nonce", tx.Nonce(), "recipient", tx.To(), "maxBlock", max, "peers", peers)
+	return tx.Hash(), nil
+}
+
+// PrivateTxStatus is the inclusion status of a privately submitted transaction.
+type PrivateTxStatus struct {
+	Status         string          `json:"status"`
+	MaxBlockNumber *hexutil.Uint64 `json:"maxBlockNumber,omitempty"`
+	BlockHash      *common.Hash    `json:"blockHash,omitempty"`
+	BlockNumber    *hexutil.Uint64 `json:"blockNumber,omitempty"`
+}
+
+// GetPrivateTransactionStatus reports whether a private transaction is still
+// pending, was included into the canonical chain, or expired before inclusion.
+func (api *PublicPrivateTxAPI) GetPrivateTransactionStatus(hash common.Hash) *PrivateTxStatus {
+	if _, blockHash, number, _ := rawdb.ReadTransaction(api.e.ChainDb(), hash); blockHash != (common.Hash{}) {
+		n := hexutil.Uint64(number)
+		return &PrivateTxStatus{Status: "included", BlockHash: &blockHash, BlockNumber: &n}
+	}
+	status, max := api.e.txPool.PrivateStatus(hash)
+	result := &PrivateTxStatus{Status: status.String()}
+	if status != core.PrivateTxStatusUnknown {
+		m := hexutil.Uint64(max)
+		result.MaxBlockNumber = &m
+	}
+	return result
+}
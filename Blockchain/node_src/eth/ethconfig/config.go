@@ -86,6 +86,7 @@ var Defaults = Config{
 	TrieDirtyCache:          256,
 	TrieTimeout:             60 * time.Minute,
 	SnapshotCache:           102,
+	StateHistory:            90000,
 	Miner: miner.Config{
 		GasCeil:  8000000,
 		GasPrice: big.NewInt(params.GWei),
@@ -139,11 +140,18 @@ type Config struct {
 	NoPruning  bool // Whether to disable pruning and flush everything to disk
 	NoPrefetch bool // Whether to disable prefetching and only load state on demand
 
-	TxLookupLimit uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
+	TxLookupLimit    uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
+	HistoryRetention uint64 `toml:",omitempty"` // The number of blocks from head whose bodies and receipts are retained in the freezer.
+	StateIndex       bool   `toml:",omitempty"` // Whether to index the state changes of every block for historical state access.
 
 	// Whitelist of required block number -> hash values to accept
 	Whitelist map[uint64]common.Hash `toml:"-"`
 
+	// PrivateTxPeers is the list of validator enode URLs which privately
+	// submitted transactions are forwarded to, instead of being gossiped. Private
+	// transactions forwarded by any other peer are dropped.
+	PrivateTxPeers []string `toml:",omitempty"`
+
 	// Light client options
 	LightServ          int  `toml:",omitempty"` // Maximum percentage of time allowed for serving LES requests
 	LightIngress       int  `toml:",omitempty"` // Incoming bandwidth limit for light servers
@@ -171,6 +179,8 @@ type Config struct {
 	TrieTimeout             time.Duration `toml:",omitempty"`
 	SnapshotCache           int
 	Preimages               bool
+	StateScheme             string `toml:",omitempty"` // State scheme used to store ethereum state and merkle trie nodes on top
+	StateHistory            uint64 `toml:",omitempty"` // The number of recent blocks to retain state history for (path scheme only)
 
 	// Mining options
 	Miner miner.Config
@@ -218,7 +228,17 @@ func CreateConsensusEngine(stack *node.Node, chainConfig *params.ChainConfig, co
 	}
 	// If proof-of-stake-authority is requested, set it up
 	if chainConfig.Congress != nil {
-		return congress.New(chainConfig, db)
+		engine := congress.New(chainConfig, db)
+
+		// Keep the signing protection out of the chain database, so that it
+		// survives the chain being restored from a backup or resynced
+		protection, err := stack.OpenDatabase("signingprotection", 0, 0, "eth/db/signingprotection/", false)
+		if err != nil {
+			log.Error("Failed to open signing protection database, refusing to sign blocks", "err", err)
+		} else {
+			engine.SetSigningProtection(protection)
+		}
+		return engine
 	}
 	// Otherwise assume proof-of-work
 	switch config.PowMode {
@@ -22,6 +22,7 @@ import (
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/trie"
 )
@@ -50,6 +51,18 @@ type txPool interface {
 	// AddRemotes should add the given transactions to the pool.
 	AddRemotes([]*types.Transaction) []error
 
+	// AddPrivate should add the given transaction to the pool, keeping it
+	// out of the gossip until the given block number is reached.
+	AddPrivate(tx *types.Transaction, maxBlock uint64) error
+
+	// AddRemotePrivate should add the given private transaction forwarded by
+	// a peer to the pool, as a remote one.
+	AddRemotePrivate(tx *types.Transaction, maxBlock uint64) error
+
+	// IsPrivate returns whether the transaction with the given hash was
+	// submitted privately and must not be propagated.
+	IsPrivate(hash common.Hash) bool
+
 	// Pending should return pending transactions.
 	// The slice should be modifiable by the caller.
 	Pending(enforceTips bool) map[common.Address]types.Transactions
@@ -71,6 +84,8 @@ type handlerConfig struct {
 	EventMux   *event.TypeMux            // Legacy event mux, deprecate for `feed`
 	Checkpoint *params.TrustedCheckpoint // Hard coded checkpoint for sync challenges
 	Whitelist  map[uint64]common.Hash    // Hard coded whitelist for sync challenged
+
+	PrivateTxPeers []*enode.Node // Peers to exchange private transactions with
 }
 
 type handler struct {
@@ -102,6 +117,8 @@ type handler struct {
 
 	whitelist map[uint64]common.Hash
 
+	privatePeers map[string]struct{} // Peer ids allowed to exchange private transactions
+
 	// channels for fetcher, syncer, txsyncLoop
 	quitSync chan struct{}
 
@@ -126,6 +143,11 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		peers:      newPeerSet(),
 		whitelist:  config.Whitelist,
 		quitSync:   make(chan struct{}),
+
+		privatePeers: make(map[string]struct{}),
+	}
+	for _, node := range config.PrivateTxPeers {
+		h.privatePeers[node.ID().String()] = struct{}{}
 	}
 	if config.Sync == downloader.FullSync {
 		// The database seems empty as the current block is the genesis. Yet the fast
@@ -490,6 +512,27 @@ func (h *handler) BroadcastTransactions(txs types.Transactions) {
 		"tx packs", directPeers, "broadcast txs", directCount)
 }
 
+// BroadcastPrivateTransactions forwards a batch of private transactions to all
+// the connected validator peers configured to receive them, returning the number
+// of peers the transactions were delivered to. Transactions are never announced
+// to any other peer.
+func (h *handler) BroadcastPrivateTransactions(txs eth.PrivateTransactionsPacket) int {
+	var sent int
+	for id := range h.privatePeers {
+		peer := h.peers.peer(id)
+		if peer == nil {
+			continue
+		}
+		if err := peer.SendPrivateTransactions(txs); err != nil {
+			peer.Log().Debug("Failed to forward private transactions", "count", len(txs), "err", err)
+			continue
+		}
+		sent++
+	}
+	log.Debug("Private transaction forward", "txs", len(txs), "peers", sent)
+	return sent
+}
+
 // minedBroadcastLoop sends mined blocks to connected peers.
 func (h *handler) minedBroadcastLoop() {
 	defer h.wg.Done()
@@ -508,7 +551,17 @@ func (h *handler) txBroadcastLoop() {
 	for {
 		select {
 		case event := <-h.txsCh:
-			h.BroadcastTransactions(event.Txs)
+			// Private transactions are forwarded explicitly upon submission,
+			// never let them leak into the public gossip
+			txs := make(types.Transactions, 0, len(event.Txs))
+			for _, tx := range event.Txs {
+				if !h.txpool.IsPrivate(tx.Hash()) {
+					txs = append(txs, tx)
+				}
+			}
+			if len(txs) > 0 {
+				h.BroadcastTransactions(txs)
+			}
 		case <-h.txsSub.Err():
 			return
 		}
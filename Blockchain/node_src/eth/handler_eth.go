@@ -85,6 +85,9 @@ func (h *ethHandler) Handle(peer *eth.Peer, packet eth.Packet) error {
 	case *eth.PooledTransactionsPacket:
 		return h.txFetcher.Enqueue(peer.ID(), *packet, true)
 
+	case *eth.PrivateTransactionsPacket:
+		return h.handlePrivateTransactions(peer, *packet)
+
 	default:
 		return fmt.Errorf("unexpected eth packet type: %T", packet)
 	}
@@ -200,3 +203,20 @@ func (h *ethHandler) handleBlockBroadcast(peer *eth.Peer, block *types.Block, td
 	}
 	return nil
 }
+
+// handlePrivateTransactions is invoked from a peer's message handler when it
+// forwards privately submitted transactions. They are pooled as remote ones for
+// inclusion by the local validator, but are never propagated any further. Only
+// the configured private transaction peers may forward them.
+func (h *ethHandler) handlePrivateTransactions(peer *eth.Peer, txs eth.PrivateTransactionsPacket) error {
+	if _, ok := h.privatePeers[peer.ID()]; !ok {
+		peer.Log().Debug("Dropping private transactions from unknown peer", "count", len(txs))
+		return nil
+	}
+	for _, ptx := range txs {
+		if err := h.txpool.AddRemotePrivate(ptx.Tx, ptx.MaxBlockNumber); err != nil {
+			peer.Log().Trace("Rejected private transaction", "hash", ptx.Tx.Hash(), "err", err)
+		}
+	}
+	return nil
+}
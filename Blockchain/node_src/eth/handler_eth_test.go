@@ -431,6 +431,118 @@ func testTransactionPropagation(t *testing.T, protocol uint) {
 	}
 }
 
+// Tests that private transactions are only accepted from the configured private
+// peers, and are neither announced nor broadcast to any peer.
+func TestPrivateTransactions67(t *testing.T) { testPrivateTransactions(t, eth.ETH67) }
+
+func testPrivateTransactions(t *testing.T, protocol uint) {
+	t.Parallel()
+
+	handler := newTestHandler()
+	defer handler.close()
+
+	handler.handler.acceptTxs = 1 // mark synced to accept transactions
+	handler.handler.privatePeers[enode.ID{1}.String()] = struct{}{}
+
+	// Connect a private and an unknown peer to forward private transactions
+	var (
+		genesis = handler.chain.Genesis()
+		head    = handler.chain.CurrentBlock()
+		td      = handler.chain.GetTd(head.Hash(), head.NumberU64())
+	)
+	connect := func(id enode.ID) *eth.Peer {
+		p2pSrc, p2pSink := p2p.MsgPipe()
+		t.Cleanup(func() { p2pSrc.Close(); p2pSink.Close() })
+
+		src := eth.NewPeer(protocol, p2p.NewPeerPipe(id, "", nil, p2pSrc), p2pSrc, handler.txpool)
+		sink := eth.NewPeer(protocol, p2p.NewPeerPipe(id, "", nil, p2pSink), p2pSink, handler.txpool)
+		t.Cleanup(func() { src.Close(); sink.Close() })
+
+		go handler.handler.runEthPeer(sink, func(peer *eth.Peer) error {
+			return eth.Handle((*ethHandler)(handler.handler), peer)
+		})
+		if err := src.Handshake(1, td, head.Hash(), genesis.Hash(), forkid.NewIDWithChain(handler.chain), forkid.NewFilter(handler.chain)); err != nil {
+			t.Fatalf("failed to run protocol handshake")
+		}
+		return src
+	}
+	txs := make([]*types.Transaction, 4)
+	for nonce := range txs {
+		tx := types.NewTransaction(uint64(nonce), common.Address{}, big.NewInt(0), 100000, big.NewInt(0), nil)
+		txs[nonce], _ = types.SignTx(tx, types.HomesteadSigner{}, testKey)
+	}
+	private, unknown := connect(enode.ID{1}), connect(enode.ID{2})
+
+	if err := unknown.SendPrivateTransactions(eth.PrivateTransactionsPacket{{Tx: txs[0], MaxBlockNumber: 10}}); err != nil {
+		t.Fatalf("failed to send private transaction: %v", err)
+	}
+	if err := private.SendPrivateTransactions(eth.PrivateTransactionsPacket{{Tx: txs[1], MaxBlockNumber: 10}}); err != nil {
+		t.Fatalf("failed to send private transaction: %v", err)
+	}
+	for start := time.Now(); !handler.txpool.IsPrivate(txs[1].Hash()); time.Sleep(10 * time.Millisecond) {
+		if time.Since(start) > 2*time.Second {
+			t.Fatalf("private transaction from private peer not pooled")
+		}
+	}
+	if handler.txpool.Has(txs[0].Hash()) {
+		t.Errorf("private transaction from unknown peer pooled")
+	}
+	if err := handler.txpool.AddPrivate(txs[2], 10); err != nil {
+		t.Fatalf("failed to add private transaction: %v", err)
+	}
+	// Connect a sink peer, and make sure only the public transaction reaches it
+	p2pSrc, p2pSink := p2p.MsgPipe()
+	defer p2pSrc.Close()
+	defer p2pSink.Close()
+
+	src := eth.NewPeer(protocol, p2p.NewPeerPipe(enode.ID{3}, "", nil, p2pSrc), p2pSrc, handler.txpool)
+	sink := eth.NewPeer(protocol, p2p.NewPeerPipe(enode.ID{3}, "", nil, p2pSink), p2pSink, handler.txpool)
+	defer src.Close()
+	defer sink.Close()
+
+	go handler.handler.runEthPeer(src, func(peer *eth.Peer) error {
+		return eth.Handle((*ethHandler)(handler.handler), peer)
+	})
+	if err := sink.Handshake(1, td, head.Hash(), genesis.Hash(), forkid.NewIDWithChain(handler.chain), forkid.NewFilter(handler.chain)); err != nil {
+		t.Fatalf("failed to run protocol handshake")
+	}
+	backend := new(testEthHandler)
+
+	anns := make(chan []common.Hash)
+	annSub := backend.txAnnounces.Subscribe(anns)
+	defer annSub.Unsubscribe()
+
+	bcasts := make(chan []*types.Transaction)
+	bcastSub := backend.txBroadcasts.Subscribe(bcasts)
+	defer bcastSub.Unsubscribe()
+
+	go eth.Handle(backend, sink)
+
+	time.Sleep(250 * time.Millisecond) // Wait until the peer is registered to receive the broadcast
+	go handler.txpool.AddRemotes(txs[3:])
+
+	for seen := false; !seen; {
+		var hashes []common.Hash
+		select {
+		case hashes = <-anns:
+		case bcast := <-bcasts:
+			for _, tx := range bcast {
+				hashes = append(hashes, tx.Hash())
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("public transaction not propagated")
+		}
+		for _, hash := range hashes {
+			switch hash {
+			case txs[1].Hash(), txs[2].Hash():
+				t.Errorf("private transaction %x propagated", hash)
+			case txs[3].Hash():
+				seen = true
+			}
+		}
+	}
+}
+
 // Tests that post eth protocol handshake, clients perform a mutual checkpoint
 // challenge to validate each other's chains. Hash mismatches, or missing ones
 // during a fast sync should lead to the peer getting dropped.
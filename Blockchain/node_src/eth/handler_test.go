@@ -32,7 +32,8 @@ var (
 // Its goal is to get around setting up a valid statedb for the balance and nonce
 // checks.
 type testTxPool struct {
-	pool map[common.Hash]*types.Transaction // Hash map of collected transactions
+	pool    map[common.Hash]*types.Transaction // Hash map of collected transactions
+	private map[common.Hash]uint64             // Hash map of private transactions and their max blocks
 
 	txFeed event.Feed   // Notification feed to allow waiting for inclusion
 	lock   sync.RWMutex // Protects the transaction pool
@@ -41,7 +42,8 @@ type testTxPool struct {
 // newTestTxPool creates a mock transaction pool.
 func newTestTxPool() *testTxPool {
 	return &testTxPool{
-		pool: make(map[common.Hash]*types.Transaction),
+		pool:    make(map[common.Hash]*types.Transaction),
+		private: make(map[common.Hash]uint64),
 	}
 }
 
@@ -76,6 +78,33 @@ func (p *testTxPool) AddRemotes(txs []*types.Transaction) []error {
 	return make([]error, len(txs))
 }
 
+// AddPrivate appends a private transaction to the pool, and notifies any
+// listeners if the addition channel is non nil
+func (p *testTxPool) AddPrivate(tx *types.Transaction, maxBlock uint64) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.pool[tx.Hash()] = tx
+	p.private[tx.Hash()] = maxBlock
+	p.txFeed.Send(core.NewTxsEvent{Txs: []*types.Transaction{tx}})
+	return nil
+}
+
+// AddRemotePrivate appends a private transaction forwarded by a peer to the
+// pool, and notifies any listeners if the addition channel is non nil
+func (p *testTxPool) AddRemotePrivate(tx *types.Transaction, maxBlock uint64) error {
+	return p.AddPrivate(tx, maxBlock)
+}
+
+// IsPrivate returns whether the transaction was added as a private one.
+func (p *testTxPool) IsPrivate(hash common.Hash) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	_, ok := p.private[hash]
+	return ok
+}
+
 // Pending returns all the transactions known to the pool
 func (p *testTxPool) Pending(enforceTips bool) map[common.Address]types.Transactions {
 	p.lock.RLock()
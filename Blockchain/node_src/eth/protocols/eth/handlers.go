@@ -397,3 +397,23 @@ func handlePooledTransactions66(backend Backend, msg Decoder, peer *Peer) error
 
 	return backend.Handle(peer, &txs.PooledTransactionsPacket)
 }
+
+func handlePrivateTransactions(backend Backend, msg Decoder, peer *Peer) error {
+	// Transactions arrived, make sure we have a valid and fresh chain to handle them
+	if !backend.AcceptTxs() {
+		return nil
+	}
+	// Transactions can be processed, parse all of them and deliver to the pool
+	var txs PrivateTransactionsPacket
+	if err := msg.Decode(&txs); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+	for i, ptx := range txs {
+		// Validate and mark the remote transaction
+		if ptx == nil || ptx.Tx == nil {
+			return fmt.Errorf("%w: private transaction %d is nil", errDecode, i)
+		}
+		peer.markTransaction(ptx.Tx.Hash())
+	}
+	return backend.Handle(peer, &txs)
+}
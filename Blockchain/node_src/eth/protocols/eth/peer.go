@@ -173,6 +173,20 @@ func (p *Peer) SendTransactions(txs types.Transactions) error {
 	return p2p.Send(p.rw, TransactionsMsg, txs)
 }
 
+// SendPrivateTransactions sends private transactions to the peer synchronously,
+// bypassing the broadcast queues. They are marked known so that the peer will
+// never be announced them, should the local pool hold on to them. Only eth/67
+// peers can receive them.
+func (p *Peer) SendPrivateTransactions(txs PrivateTransactionsPacket) error {
+	if p.version < ETH67 {
+		return errPrivateTxUnsupported
+	}
+	for _, ptx := range txs {
+		p.knownTxs.Add(ptx.Tx.Hash())
+	}
+	return p2p.Send(p.rw, PrivateTransactionsMsg, txs)
+}
+
 // AsyncSendTransactions queues a list of transactions (by hash) to eventually
 // propagate to a remote peer. The number of pending sends are capped (new ones
 // will force old sends to be dropped)
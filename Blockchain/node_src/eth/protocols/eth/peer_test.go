@@ -7,6 +7,7 @@ package eth
 
 import (
 	"crypto/rand"
+	"errors"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -72,3 +73,23 @@ func TestPeerSet(t *testing.T) {
 		t.Fatalf("bad size")
 	}
 }
+
+// Tests that private transactions are confined to eth/67, leaving the message
+// space of eth/66 - and thus the offsets of the protocols after it - intact.
+func TestPrivateTransactionsVersion(t *testing.T) {
+	if protocolLengths[ETH66] != 17 {
+		t.Fatalf("eth/66 message count mismatch: have %d, want %d", protocolLengths[ETH66], 17)
+	}
+	if _, ok := eth66[PrivateTransactionsMsg]; ok {
+		t.Fatalf("eth/66 handles private transactions")
+	}
+	app, net := p2p.MsgPipe()
+	defer app.Close()
+
+	peer := NewPeer(ETH66, p2p.NewPeer(enode.ID{}, "peer", nil), net, nil)
+	defer peer.Close()
+
+	if err := peer.SendPrivateTransactions(PrivateTransactionsPacket{}); !errors.Is(err, errPrivateTxUnsupported) {
+		t.Fatalf("eth/66 private transaction send error mismatch: have %v, want %v", err, errPrivateTxUnsupported)
+	}
+}
@@ -17,6 +17,7 @@ import (
 // Constants to match up protocol versions and messages
 const (
 	ETH66 = 66
+	ETH67 = 67
 )
 
 // ProtocolName is the official short name of the `eth` protocol used during
@@ -25,11 +26,11 @@ const ProtocolName = "eth"
 
 // ProtocolVersions are the supported versions of the `eth` protocol (first
 // is primary).
-var ProtocolVersions = []uint{ETH66}
+var ProtocolVersions = []uint{ETH67, ETH66}
 
 // protocolLengths are the number of implemented message corresponding to
 // different protocol versions.
-var protocolLengths = map[uint]uint64{ETH66: 17}
+var protocolLengths = map[uint]uint64{ETH67: 18, ETH66: 17}
 
 // maxMessageSize is the maximum cap on the size of a protocol message.
 const maxMessageSize = 10 * 1024 * 1024
@@ -50,6 +51,9 @@ const (
 	NewPooledTransactionHashesMsg = 0x08
 	GetPooledTransactionsMsg      = 0x09
 	PooledTransactionsMsg         = 0x0a
+
+	// Protocol messages overloaded in eth/67
+	PrivateTransactionsMsg = 0x11
 )
 
 var (
@@ -61,6 +65,7 @@ var (
 	errNetworkIDMismatch       = errors.New("network ID mismatch")
 	errGenesisMismatch         = errors.New("genesis mismatch")
 	errForkIDRejected          = errors.New("fork ID rejected")
+	errPrivateTxUnsupported    = errors.New("private transactions not supported")
 )
 
 // Packet represents a p2p message in the `eth` protocol.
@@ -302,6 +307,17 @@ type PooledTransactionsRLPPacket66 struct {
 	PooledTransactionsRLPPacket
 }
 
+// PrivateTransaction is a transaction submitted to a validator directly, with
+// the last block number it may be included in.
+type PrivateTransaction struct {
+	Tx             *types.Transaction
+	MaxBlockNumber uint64
+}
+
+// PrivateTransactionsPacket is the network packet for private transaction
+// forwarding to validators.
+type PrivateTransactionsPacket []*PrivateTransaction
+
 func (*StatusPacket) Name() string { return "Status" }
 func (*StatusPacket) Kind() byte   { return StatusMsg }
 
@@ -346,3 +362,6 @@ func (*GetPooledTransactionsPacket) Kind() byte   { return GetPooledTransactions
 
 func (*PooledTransactionsPacket) Name() string { return "PooledTransactions" }
 func (*PooledTransactionsPacket) Kind() byte   { return PooledTransactionsMsg }
+
+func (*PrivateTransactionsPacket) Name() string { return "PrivateTransactions" }
+func (*PrivateTransactionsPacket) Kind() byte   { return PrivateTransactionsMsg }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -38,6 +39,16 @@ func (eth *Ethereum) stateAtBlock(block *types.Block, reexec uint64, base *state
 		report   = true
 		origin   = block.NumberU64()
 	)
+	// The path scheme keeps all the states of the datadir in the single layered
+	// trie database of the chain, an isolated one can't be opened on the same
+	// disk to regenerate the missing states.
+	if eth.blockchain.StateCache().TrieDB().Scheme() == rawdb.PathScheme {
+		statedb, err = eth.blockchain.StateAt(block.Root())
+		if err != nil {
+			return nil, fmt.Errorf("state of block %d unavailable, reexec is not supported by the path state scheme: %w", origin, err)
+		}
+		return statedb, nil
+	}
 	// Check the live database first if we have the state fully available, use that.
 	if checkLive {
 		statedb, err = eth.blockchain.StateAt(block.Root())
@@ -6,6 +6,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -157,6 +158,12 @@ type TraceConfig struct {
 	Tracer  *string
 	Timeout *string
 	Reexec  *uint64
+	// Config specific to given tracer. Note struct logger
+	// config are historically embedded in main object.
+	TracerConfig json.RawMessage
+	// SystemCalls also traces the system calls made by the PoSA engine when
+	// finalizing a traced block, e.g. to distribute the block rewards.
+	SystemCalls bool
 }
 
 type BlockOverrides struct {
@@ -177,6 +184,7 @@ type TraceCallConfig struct {
     Tracer         *string
     Timeout        *string
     Reexec         *uint64
+    TracerConfig   json.RawMessage
     StateOverrides *ethapi.StateOverride
     BlockOverrides *BlockOverrides // Add this line
 }
@@ -191,6 +199,7 @@ type StdTraceConfig struct {
 
 // txTraceResult is the result of a single transaction trace.
 type txTraceResult struct {
+	Kind   string      `json:"kind,omitempty"`   // Kind of the traced system call, empty for transactions
 	Result interface{} `json:"result,omitempty"` // Trace results produced by the tracer
 	Error  string      `json:"error,omitempty"`  // Trace failure produced by the tracer
 }
@@ -283,9 +292,10 @@ func (api *API) traceChain(ctx context.Context, start, end *types.Block, config
 				for i, tx := range task.block.Transactions() {
 					msg, _ := tx.AsMessage(signer, task.block.BaseFee())
 					txctx := &Context{
-						BlockHash: task.block.Hash(),
-						TxIndex:   i,
-						TxHash:    tx.Hash(),
+						BlockHash:   task.block.Hash(),
+						BlockNumber: task.block.Number(),
+						TxIndex:     i,
+						TxHash:      tx.Hash(),
 					}
 					var (
 						res     interface{}
@@ -628,9 +638,10 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 			for task := range jobs {
 				msg, _ := txs[task.index].AsMessage(signer, block.BaseFee())
 				txctx := &Context{
-					BlockHash: blockHash,
-					TxIndex:   task.index,
-					TxHash:    txs[task.index].Hash(),
+					BlockHash:   blockHash,
+					BlockNumber: block.Number(),
+					TxIndex:     task.index,
+					TxHash:      txs[task.index].Hash(),
 				}
 				var res interface{}
 				var err error
@@ -649,7 +660,10 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 		}()
 	}
 	// Feed the transactions into the tracers and return
-	var failed error
+	var (
+		failed        error
+		finalizeState *state.StateDB // State the block is finalized on, before any system transaction
+	)
 	blockCtx := core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
 	for i, tx := range txs {
 		var isSysTx bool
@@ -664,6 +678,9 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 		msg, _ := tx.AsMessage(signer, block.BaseFee())
 		vmenv := vm.NewEVM(blockCtx, core.NewEVMTxContext(msg), statedb, api.backend.ChainConfig(), vm.Config{})
 		if isSysTx {
+			if finalizeState == nil && config != nil && config.SystemCalls {
+				finalizeState = statedb.Copy()
+			}
 			if _, _, err := api.posa.ApplySysTx(vmenv, statedb, i, msg.From(), tx); err != nil {
 				failed = err
 				break
@@ -687,6 +704,79 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 	if failed != nil {
 		return nil, failed
 	}
+	if api.isPoSA && config != nil && config.SystemCalls {
+		if finalizeState == nil {
+			finalizeState = statedb
+		}
+		sysResults, err := api.traceSysCalls(ctx, block, finalizeState, config)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, sysResults...)
+	}
+	return results, nil
+}
+
+// traceSysCalls replays the finalization of the block on top of the given state,
+// which must contain all the non-system transactions of the block, and returns
+// a trace of every system call made by the consensus engine along the way.
+func (api *API) traceSysCalls(ctx context.Context, block *types.Block, statedb *state.StateDB, config *TraceConfig) ([]*txTraceResult, error) {
+	var (
+		signer  = types.MakeSigner(api.backend.ChainConfig(), block.Number())
+		header  = types.CopyHeader(block.Header())
+		txs     = make([]*types.Transaction, 0, len(block.Transactions()))
+		sysTxs  = make([]*types.Transaction, 0)
+		txctx   = &Context{BlockHash: block.Hash(), BlockNumber: block.Number()}
+		kinds   []string
+		tracers []vm.EVMLogger
+		failed  error
+	)
+	for _, tx := range block.Transactions() {
+		sender, _ := types.Sender(signer, tx)
+		if isSysTx, _ := api.posa.IsSysTransaction(sender, tx, header); isSysTx {
+			sysTxs = append(sysTxs, tx)
+		} else {
+			txs = append(txs, tx)
+		}
+	}
+	tracerFn := func(kind string) vm.EVMLogger {
+		var tracer vm.EVMLogger
+		switch {
+		case config.Tracer != nil:
+			t, err := New(*config.Tracer, txctx, config.TracerConfig)
+			if err != nil {
+				failed = err
+				return nil
+			}
+			tracer = t
+		default:
+			tracer = vm.NewStructLogger(config.LogConfig)
+		}
+		kinds = append(kinds, kind)
+		tracers = append(tracers, tracer)
+		return tracer
+	}
+	if err := api.posa.TraceFinalize(api.backend.ChainHeaderReader(), header, statedb, &txs, block.Uncles(), nil, sysTxs, tracerFn); err != nil {
+		return nil, fmt.Errorf("tracing system calls failed: %w", err)
+	}
+	if failed != nil {
+		return nil, failed
+	}
+	results := make([]*txTraceResult, len(tracers))
+	for i, tracer := range tracers {
+		// System calls don't go through a state transition, only the
+		// struct logger needs the outcome of the call.
+		result := new(core.ExecutionResult)
+		if logger, ok := tracer.(*vm.StructLogger); ok {
+			result.Err, result.ReturnData = logger.Error(), logger.Output()
+		}
+		res, err := api.traceResult(tracer, result)
+		if err != nil {
+			results[i] = &txTraceResult{Kind: kinds[i], Error: err.Error()}
+			continue
+		}
+		results[i] = &txTraceResult{Kind: kinds[i], Result: res}
+	}
 	return results, nil
 }
 
@@ -857,9 +947,10 @@ func (api *API) TraceTransaction(ctx context.Context, hash common.Hash, config *
 		return nil, err
 	}
 	txctx := &Context{
-		BlockHash: blockHash,
-		TxIndex:   int(index),
-		TxHash:    hash,
+		BlockHash:   blockHash,
+		BlockNumber: block.Number(),
+		TxIndex:     int(index),
+		TxHash:      hash,
 	}
 	if api.isPoSA {
 		tx := block.Transactions()[int(index)]
@@ -919,10 +1010,11 @@ func (api *API) TraceCall(ctx context.Context, args ethapi.TransactionArgs, bloc
 	var traceConfig *TraceConfig
 	if config != nil {
 		traceConfig = &TraceConfig{
-			LogConfig: config.LogConfig,
-			Tracer:    config.Tracer,
-			Timeout:   config.Timeout,
-			Reexec:    config.Reexec,
+			LogConfig:    config.LogConfig,
+			Tracer:       config.Tracer,
+			Timeout:      config.Timeout,
+			Reexec:       config.Reexec,
+			TracerConfig: config.TracerConfig,
 		}
 	}
 	return api.traceTx(ctx, msg, new(Context), vmctx, statedb, traceConfig)
@@ -949,7 +1041,7 @@ func (api *API) traceTx(ctx context.Context, message core.Message, txctx *Contex
 				return nil, err
 			}
 		}
-		if t, err := New(*config.Tracer, txctx); err != nil {
+		if t, err := New(*config.Tracer, txctx, config.TracerConfig); err != nil {
 			return nil, err
 		} else {
 			deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
@@ -998,7 +1090,7 @@ func (api *API) tracePoSASysTx(ctx context.Context, sender common.Address, tx *t
 			}
 		}
 		// Constuct the JavaScript tracer to execute with
-		if tracer, err = New(*config.Tracer, txctx); err != nil {
+		if tracer, err = New(*config.Tracer, txctx, config.TracerConfig); err != nil {
 			return nil, err
 		}
 		// Handle timeouts and RPC cancellations
@@ -1065,5 +1157,11 @@ func APIs(backend Backend) []rpc.API {
 			Service:   NewAPI(backend),
 			Public:    false,
 		},
+		{
+			Namespace: "trace",
+			Version:   "1.0",
+			Service:   NewTraceAPI(backend),
+			Public:    false,
+		},
 	}
 }
@@ -0,0 +1,428 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package tracers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// maxTraceFilterBlocks is the maximum number of blocks a single trace_filter
+	// request is allowed to re-execute.
+	maxTraceFilterBlocks = 1000
+
+	flatCallTracerName = "flatCallTracer"
+	prestateTracerName = "prestateTracer"
+	vmTracerName       = "parityVmTracer"
+)
+
+var (
+	errTraceFilterRange = fmt.Errorf("block range exceeds %d blocks", maxTraceFilterBlocks)
+
+	// flatCallTracerConfig reports errors the way Parity does.
+	flatCallTracerConfig = json.RawMessage(`{"convertParityErrors":true}`)
+)
+
+// TraceAPI is the collection of Parity (OpenEthereum) compatible tracing APIs,
+// built on top of the native tracers. Congress system transactions are part of
+// the block body and are traced like any other transaction. The system calls
+// made when finalizing a block are traced after them, outside of any
+// transaction, the distribution of the block fees being reported as a reward.
+type TraceAPI struct {
+	api *API
+}
+
+// NewTraceAPI creates a new API definition for the Parity compatible tracing
+// methods of the Ethereum service.
+func NewTraceAPI(backend Backend) *TraceAPI {
+	return &TraceAPI{api: NewAPI(backend)}
+}
+
+// Block returns the flat call traces of all the transactions of the given block.
+func (api *TraceAPI) Block(ctx context.Context, number rpc.BlockNumber) ([]json.RawMessage, error) {
+	block, err := api.api.blockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return api.blockTraces(ctx, block)
+}
+
+// Transaction returns the flat call traces of the given transaction.
+func (api *TraceAPI) Transaction(ctx context.Context, hash common.Hash) ([]json.RawMessage, error) {
+	tracer := flatCallTracerName
+	res, err := api.api.TraceTransaction(ctx, hash, &TraceConfig{Tracer: &tracer, TracerConfig: flatCallTracerConfig})
+	if err != nil {
+		return nil, err
+	}
+	var traces []json.RawMessage
+	if err := json.Unmarshal(res.(json.RawMessage), &traces); err != nil {
+		return nil, err
+	}
+	return traces, nil
+}
+
+// TraceFilterArgs represents the arguments of a trace_filter request.
+type TraceFilterArgs struct {
+	FromBlock   *rpc.BlockNumber `json:"fromBlock"`
+	ToBlock     *rpc.BlockNumber `json:"toBlock"`
+	FromAddress []common.Address `json:"fromAddress"`
+	ToAddress   []common.Address `json:"toAddress"`
+	After       *uint64          `json:"after"`
+	Count       *uint64          `json:"count"`
+}
+
+// Filter returns the flat call traces within the given block range matching
+// the sender and recipient filters. A trace matches if both its sender is in
+// fromAddress and its recipient in toAddress, an empty list matching any.
+func (api *TraceAPI) Filter(ctx context.Context, args TraceFilterArgs) ([]json.RawMessage, error) {
+	from, err := api.resolveNumber(ctx, args.FromBlock, rpc.EarliestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	to, err := api.resolveNumber(ctx, args.ToBlock, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if from > to {
+		return nil, errors.New("fromBlock is after toBlock")
+	}
+	if to-from >= maxTraceFilterBlocks {
+		return nil, errTraceFilterRange
+	}
+	// The genesis block has no transactions to trace
+	if from == 0 {
+		from = 1
+	}
+	var (
+		fromAddrs = addressSet(args.FromAddress)
+		toAddrs   = addressSet(args.ToAddress)
+		skip      uint64
+		matches   = []json.RawMessage{}
+	)
+	if args.After != nil {
+		skip = *args.After
+	}
+	for number := from; number <= to; number++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		block, err := api.api.blockByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return nil, err
+		}
+		traces, err := api.blockTraces(ctx, block)
+		if err != nil {
+			return nil, err
+		}
+		for _, trace := range traces {
+			var frame parityTraceAddresses
+			if err := json.Unmarshal(trace, &frame); err != nil {
+				return nil, err
+			}
+			sender, recipient := frame.addresses()
+			if !fromAddrs.matches(sender) || !toAddrs.matches(recipient) {
+				continue
+			}
+			if skip > 0 {
+				skip--
+				continue
+			}
+			matches = append(matches, trace)
+			if args.Count != nil && uint64(len(matches)) >= *args.Count {
+				return matches, nil
+			}
+		}
+	}
+	return matches, nil
+}
+
+// TraceReplayResult is the result of replaying a single transaction with the
+// requested trace types.
+type TraceReplayResult struct {
+	Output          hexutil.Bytes                   `json:"output"`
+	StateDiff       map[common.Address]*accountDiff `json:"stateDiff"`
+	Trace           []json.RawMessage               `json:"trace"`
+	VmTrace         json.RawMessage                 `json:"vmTrace"`
+	TransactionHash *common.Hash                    `json:"transactionHash"` // Nil for system calls
+}
+
+// ReplayBlockTransactions replays all the transactions of the given block and
+// returns the requested trace types for each: "trace", "stateDiff" and "vmTrace".
+// The system calls made when finalizing the block follow the transactions.
+func (api *TraceAPI) ReplayBlockTransactions(ctx context.Context, number rpc.BlockNumber, traceTypes []string) ([]*TraceReplayResult, error) {
+	var withStateDiff, withVmTrace, withTrace bool
+	for _, typ := range traceTypes {
+		switch typ {
+		case "trace":
+			withTrace = true
+		case "stateDiff":
+			withStateDiff = true
+		case "vmTrace":
+			withVmTrace = true
+		default:
+			return nil, fmt.Errorf("unsupported trace type %q", typ)
+		}
+	}
+	block, err := api.api.blockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	// The call traces are always needed for the output of the transaction
+	mux := map[string]json.RawMessage{flatCallTracerName: flatCallTracerConfig}
+	if withStateDiff {
+		mux[prestateTracerName] = json.RawMessage(`{"diffMode":true}`)
+	}
+	if withVmTrace {
+		mux[vmTracerName] = nil
+	}
+	muxConfig, err := json.Marshal(mux)
+	if err != nil {
+		return nil, err
+	}
+	tracer := "muxTracer"
+	results, err := api.api.traceBlock(ctx, block, &TraceConfig{Tracer: &tracer, TracerConfig: muxConfig, SystemCalls: true})
+	if err != nil {
+		return nil, err
+	}
+	replays := make([]*TraceReplayResult, len(results))
+	for i, result := range results {
+		if err := traceFailure(block, i, result); err != nil {
+			return nil, err
+		}
+		var res map[string]json.RawMessage
+		if err := json.Unmarshal(result.Result.(json.RawMessage), &res); err != nil {
+			return nil, err
+		}
+		replay := &TraceReplayResult{Trace: []json.RawMessage{}}
+		traces, output, err := parseFlatCallTraces(res[flatCallTracerName])
+		if err != nil {
+			return nil, err
+		}
+		if result.Kind != "" {
+			if traces, err = sysCallTraces(block.Header(), result.Kind, traces); err != nil {
+				return nil, err
+			}
+		} else {
+			hash := block.Transactions()[i].Hash()
+			replay.TransactionHash = &hash
+		}
+		replay.Output = output
+		if withTrace {
+			replay.Trace = traces
+		}
+		if withStateDiff {
+			if replay.StateDiff, err = parseStateDiff(res[prestateTracerName]); err != nil {
+				return nil, err
+			}
+		}
+		if withVmTrace {
+			replay.VmTrace = res[vmTracerName]
+		}
+		replays[i] = replay
+	}
+	return replays, nil
+}
+
+// blockTraces traces all the transactions of a block, system transactions
+// included, followed by the system calls made when finalizing it, and returns
+// the concatenation of their flat call traces.
+func (api *TraceAPI) blockTraces(ctx context.Context, block *types.Block) ([]json.RawMessage, error) {
+	traces := []json.RawMessage{}
+	if block.NumberU64() == 0 {
+		return traces, nil
+	}
+	tracer := flatCallTracerName
+	results, err := api.api.traceBlock(ctx, block, &TraceConfig{Tracer: &tracer, TracerConfig: flatCallTracerConfig, SystemCalls: true})
+	if err != nil {
+		return nil, err
+	}
+	for i, result := range results {
+		if err := traceFailure(block, i, result); err != nil {
+			return nil, err
+		}
+		txTraces, _, err := parseFlatCallTraces(result.Result.(json.RawMessage))
+		if err != nil {
+			return nil, err
+		}
+		if result.Kind != "" {
+			if txTraces, err = sysCallTraces(block.Header(), result.Kind, txTraces); err != nil {
+				return nil, err
+			}
+		}
+		traces = append(traces, txTraces...)
+	}
+	return traces, nil
+}
+
+// traceFailure returns the error of the i-th trace result of the block, if it
+// failed. The results of the system calls follow the ones of the transactions.
+func traceFailure(block *types.Block, i int, result *txTraceResult) error {
+	if result.Error == "" {
+		return nil
+	}
+	if result.Kind != "" {
+		return fmt.Errorf("tracing %s system call failed: %s", result.Kind, result.Error)
+	}
+	return fmt.Errorf("tracing transaction %#x failed: %s", block.Transactions()[i].Hash(), result.Error)
+}
+
+// resolveNumber converts an optional block number into an absolute one.
+func (api *TraceAPI) resolveNumber(ctx context.Context, number *rpc.BlockNumber, def rpc.BlockNumber) (uint64, error) {
+	if number == nil {
+		number = &def
+	}
+	if *number >= 0 {
+		return uint64(*number), nil
+	}
+	header, err := api.api.backend.HeaderByNumber(ctx, *number)
+	if err != nil {
+		return 0, err
+	}
+	if header == nil {
+		return 0, fmt.Errorf("block #%d not found", *number)
+	}
+	return header.Number.Uint64(), nil
+}
+
+// parityTraceAddresses is the subset of a flat call trace needed for filtering.
+type parityTraceAddresses struct {
+	Action struct {
+		Address       *common.Address `json:"address"`
+		Author        *common.Address `json:"author"`
+		From          *common.Address `json:"from"`
+		RefundAddress *common.Address `json:"refundAddress"`
+		To            *common.Address `json:"to"`
+	} `json:"action"`
+	Result *struct {
+		Address *common.Address `json:"address"`
+	} `json:"result"`
+}
+
+// addresses returns the sender and recipient of the traced call. The recipient
+// of a creation is the created contract, the one of a self destruct is the
+// beneficiary and the one of a reward its author.
+func (t *parityTraceAddresses) addresses() (sender *common.Address, recipient *common.Address) {
+	switch {
+	case t.Action.Author != nil:
+		return nil, t.Action.Author
+	case t.Action.RefundAddress != nil:
+		return t.Action.Address, t.Action.RefundAddress
+	case t.Action.To == nil && t.Result != nil:
+		return t.Action.From, t.Result.Address
+	}
+	return t.Action.From, t.Action.To
+}
+
+type addressFilter map[common.Address]struct{}
+
+func addressSet(addrs []common.Address) addressFilter {
+	set := make(addressFilter, len(addrs))
+	for _, addr := range addrs {
+		set[addr] = struct{}{}
+	}
+	return set
+}
+
+// matches reports whether the address passes the filter. An empty filter
+// matches everything.
+func (f addressFilter) matches(addr *common.Address) bool {
+	if len(f) == 0 {
+		return true
+	}
+	if addr == nil {
+		return false
+	}
+	_, ok := f[*addr]
+	return ok
+}
+
+// parseFlatCallTraces splits the result of the flat call tracer into the
+// individual traces, also returning the output of the outermost call.
+func parseFlatCallTraces(res json.RawMessage) ([]json.RawMessage, hexutil.Bytes, error) {
+	var traces []json.RawMessage
+	if err := json.Unmarshal(res, &traces); err != nil {
+		return nil, nil, err
+	}
+	output := hexutil.Bytes{}
+	if len(traces) > 0 {
+		var top struct {
+			Result *struct {
+				Output hexutil.Bytes `json:"output"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal(traces[0], &top); err != nil {
+			return nil, nil, err
+		}
+		if top.Result != nil && top.Result.Output != nil {
+			output = top.Result.Output
+		}
+	}
+	return traces, output, nil
+}
+
+// parityRewardTrace is a block reward in the format of Parity's trace module.
+type parityRewardTrace struct {
+	Action struct {
+		Author     common.Address `json:"author"`
+		RewardType string         `json:"rewardType"`
+		Value      string         `json:"value"`
+	} `json:"action"`
+	BlockHash           common.Hash  `json:"blockHash"`
+	BlockNumber         uint64       `json:"blockNumber"`
+	Result              *struct{}    `json:"result"`
+	Subtraces           int          `json:"subtraces"`
+	TraceAddress        []int        `json:"traceAddress"`
+	TransactionHash     *common.Hash `json:"transactionHash"`
+	TransactionPosition *uint64      `json:"transactionPosition"`
+	Type                string       `json:"type"`
+}
+
+// sysCallTraces converts the flat call traces of a system call made when
+// finalizing the block into traces outside of any transaction. The traces of
+// the fee distribution are preceded by a reward of the fees to the validator
+// sealing the block, which the engine credits before distributing them.
+func sysCallTraces(header *types.Header, kind string, traces []json.RawMessage) ([]json.RawMessage, error) {
+	converted := make([]json.RawMessage, 0, len(traces)+1)
+	for i, trace := range traces {
+		var frame map[string]json.RawMessage
+		if err := json.Unmarshal(trace, &frame); err != nil {
+			return nil, err
+		}
+		if i == 0 && kind == consensus.SysCallReward {
+			var action struct {
+				Value string `json:"value"`
+			}
+			if err := json.Unmarshal(frame["action"], &action); err != nil {
+				return nil, err
+			}
+			reward := &parityRewardTrace{
+				BlockHash:    header.Hash(),
+				BlockNumber:  header.Number.Uint64(),
+				TraceAddress: []int{},
+				Type:         "reward",
+			}
+			reward.Action.Author, reward.Action.RewardType, reward.Action.Value = header.Coinbase, "block", action.Value
+			blob, err := json.Marshal(reward)
+			if err != nil {
+				return nil, err
+			}
+			converted = append(converted, blob)
+		}
+		frame["transactionHash"], frame["transactionPosition"] = json.RawMessage("null"), json.RawMessage("null")
+		blob, err := json.Marshal(frame)
+		if err != nil {
+			return nil, err
+		}
+		converted = append(converted, blob)
+	}
+	return converted, nil
+}
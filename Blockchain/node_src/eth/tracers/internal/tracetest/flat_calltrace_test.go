@@ -0,0 +1,15 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package tracetest
+
+import "testing"
+
+// Iterates over all the input-output datasets in the tracer test harness and
+// runs the native flat call tracer against them.
+func TestFlatCallTracer(t *testing.T) {
+	testTracer("flatCallTracer", "flat_call_tracer", t)
+}
+
+func TestMuxTracer(t *testing.T) {
+	testTracer("muxTracer", "mux_tracer", t)
+}
@@ -0,0 +1,15 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package tracetest
+
+import "testing"
+
+// Iterates over all the input-output datasets in the tracer test harness and
+// runs the native prestate tracer against them.
+func TestPrestateTracer(t *testing.T) {
+	testTracer("prestateTracer", "prestate_tracer", t)
+}
+
+func TestPrestateWithDiffModeTracer(t *testing.T) {
+	testTracer("prestateTracer", "prestate_tracer_with_diff_mode", t)
+}
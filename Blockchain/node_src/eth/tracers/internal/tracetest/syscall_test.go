@@ -0,0 +1,390 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package tracetest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/congress/vmcaller"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// sysCallReward is the value of the fee distributing system call made by the
+// sysCallEngine.
+const sysCallReward = 1000
+
+// sysCallKinds are the kinds of the system calls made by the sysCallEngine,
+// in order.
+var sysCallKinds = []string{consensus.SysCallReward, consensus.SysCallPunish, consensus.SysCallEpoch, consensus.SysCallProposal}
+
+// sysCallEngine is a PoSA engine on top of a PoW one, which makes a system call
+// of every kind when finalizing a block: the call of a call_tracer fixture.
+type sysCallEngine struct {
+	consensus.Engine
+
+	chain *core.BlockChain
+	from  common.Address
+	to    common.Address
+	input []byte
+}
+
+func (e *sysCallEngine) PreHandle(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB) error {
+	return nil
+}
+
+func (e *sysCallEngine) IsSysTransaction(sender common.Address, tx *types.Transaction, header *types.Header) (bool, error) {
+	return false, nil
+}
+
+func (e *sysCallEngine) CanCreate(state consensus.StateReader, addr common.Address, height *big.Int) bool {
+	return true
+}
+
+func (e *sysCallEngine) ValidateTx(sender common.Address, tx *types.Transaction, header *types.Header, parentState *state.StateDB) error {
+	return nil
+}
+
+func (e *sysCallEngine) CreateEvmExtraValidator(header *types.Header, parentState *state.StateDB) types.EvmExtraValidator {
+	return nil
+}
+
+func (e *sysCallEngine) ApplySysTx(evm *vm.EVM, state *state.StateDB, txIndex int, sender common.Address, tx *types.Transaction) ([]byte, error, error) {
+	return nil, nil, errors.New("no system transactions")
+}
+
+func (e *sysCallEngine) TraceFinalize(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs *[]*types.Transaction, uncles []*types.Header, receipts *[]*types.Receipt, systemTxs []*types.Transaction, tracerFn consensus.SysCallTracerFn) error {
+	for _, kind := range sysCallKinds {
+		value := new(big.Int)
+		if kind == consensus.SysCallReward {
+			value.SetUint64(sysCallReward)
+		}
+		var cfg vm.Config
+		if tracer := tracerFn(kind); tracer != nil {
+			cfg = vm.Config{Debug: true, Tracer: tracer}
+		}
+		msg := vmcaller.NewLegacyMessage(e.from, &e.to, state.GetNonce(e.from), value, 1000000, new(big.Int), e.input, true)
+		if _, err := vmcaller.ExecuteMsgWithConfig(msg, state, header, e.chain, e.chain.Config(), cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sysCallBackend is a tracing backend over an archive chain finalized by the
+// sysCallEngine.
+type sysCallBackend struct {
+	db     ethdb.Database
+	chain  *core.BlockChain
+	engine *sysCallEngine
+}
+
+// newSysCallBackend creates a chain of n blocks, with the accounts of the named
+// call_tracer fixture allocated at genesis and the call of its transaction made
+// as system call by the engine.
+func newSysCallBackend(t *testing.T, fixture string, n int, alloc core.GenesisAlloc, generator func(i int, b *core.BlockGen)) *sysCallBackend {
+	blob, err := ioutil.ReadFile(filepath.Join("testdata", "call_tracer", fixture+".json"))
+	if err != nil {
+		t.Fatalf("failed to read testcase: %v", err)
+	}
+	test := new(tracerTest)
+	if err := json.Unmarshal(blob, test); err != nil {
+		t.Fatalf("failed to parse testcase: %v", err)
+	}
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(common.FromHex(test.Input), tx); err != nil {
+		t.Fatalf("failed to decode transaction: %v", err)
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		t.Fatalf("failed to recover sender: %v", err)
+	}
+	for addr, account := range test.Genesis.Alloc {
+		alloc[addr] = account
+	}
+	var (
+		gspec   = &core.Genesis{Config: params.TestChainConfig, Alloc: alloc}
+		gendb   = rawdb.NewMemoryDatabase()
+		genesis = gspec.MustCommit(gendb)
+		engine  = ethash.NewFaker()
+		db      = rawdb.NewMemoryDatabase()
+	)
+	blocks, _ := core.GenerateChain(gspec.Config, genesis, engine, gendb, n, generator)
+	gspec.MustCommit(db)
+	chain, err := core.NewBlockChain(db, &core.CacheConfig{TrieDirtyDisabled: true}, gspec.Config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	return &sysCallBackend{
+		db:     db,
+		chain:  chain,
+		engine: &sysCallEngine{Engine: engine, chain: chain, from: from, to: *tx.To(), input: tx.Data()},
+	}
+}
+
+func (b *sysCallBackend) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	return b.chain.GetHeaderByHash(hash), nil
+}
+
+func (b *sysCallBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	if number == rpc.PendingBlockNumber || number == rpc.LatestBlockNumber {
+		return b.chain.CurrentHeader(), nil
+	}
+	return b.chain.GetHeaderByNumber(uint64(number)), nil
+}
+
+func (b *sysCallBackend) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	return b.chain.GetBlockByHash(hash), nil
+}
+
+func (b *sysCallBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
+	if number == rpc.PendingBlockNumber || number == rpc.LatestBlockNumber {
+		return b.chain.CurrentBlock(), nil
+	}
+	return b.chain.GetBlockByNumber(uint64(number)), nil
+}
+
+func (b *sysCallBackend) GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, common.Hash, uint64, uint64, error) {
+	tx, hash, number, index := rawdb.ReadTransaction(b.db, txHash)
+	if tx == nil {
+		return nil, common.Hash{}, 0, 0, errors.New("transaction not found")
+	}
+	return tx, hash, number, index, nil
+}
+
+func (b *sysCallBackend) RPCGasCap() uint64                              { return 25000000 }
+func (b *sysCallBackend) ChainConfig() *params.ChainConfig               { return b.chain.Config() }
+func (b *sysCallBackend) Engine() consensus.Engine                       { return b.engine }
+func (b *sysCallBackend) ChainDb() ethdb.Database                        { return b.db }
+func (b *sysCallBackend) ChainHeaderReader() consensus.ChainHeaderReader { return b.chain }
+
+func (b *sysCallBackend) StateAtBlock(ctx context.Context, block *types.Block, reexec uint64, base *state.StateDB, checkLive bool, preferDisk bool) (*state.StateDB, error) {
+	return b.chain.StateAt(block.Root())
+}
+
+func (b *sysCallBackend) StateAtTransaction(ctx context.Context, block *types.Block, txIndex int, reexec uint64) (core.Message, vm.BlockContext, *state.StateDB, error) {
+	return nil, vm.BlockContext{}, nil, errors.New("not supported")
+}
+
+// parityTrace is the subset of a Parity trace checked by the tests.
+type parityTrace struct {
+	Action struct {
+		Author     *common.Address `json:"author"`
+		From       *common.Address `json:"from"`
+		RewardType string          `json:"rewardType"`
+		To         *common.Address `json:"to"`
+		Value      *hexutil.Big    `json:"value"`
+	} `json:"action"`
+	TraceAddress    []int        `json:"traceAddress"`
+	TransactionHash *common.Hash `json:"transactionHash"`
+	Type            string       `json:"type"`
+}
+
+// Tests that the Parity compatible tracing methods report the system calls made
+// when finalizing a block after its transactions, outside of any transaction,
+// and the distribution of the block fees as a block reward.
+func TestParitySystemCallTraces(t *testing.T) {
+	var (
+		key, _   = crypto.GenerateKey()
+		sender   = crypto.PubkeyToAddress(key.PublicKey)
+		coinbase = common.Address{0xc0}
+		txHash   common.Hash
+	)
+	backend := newSysCallBackend(t, "simple", 1, core.GenesisAlloc{sender: {Balance: big.NewInt(params.Ether)}}, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(coinbase)
+		tx, _ := types.SignTx(types.NewTransaction(uint64(i), common.Address{0x1}, big.NewInt(1000), params.TxGas, b.BaseFee(), nil), types.HomesteadSigner{}, key)
+		b.AddTx(tx)
+		txHash = tx.Hash()
+	})
+	api := tracers.NewTraceAPI(backend)
+
+	// The transaction comes first, then every system call, the fee distribution
+	// preceded by the reward
+	raw, err := api.Block(context.Background(), rpc.LatestBlockNumber)
+	if err != nil {
+		t.Fatalf("failed to trace block: %v", err)
+	}
+	var traces []*parityTrace
+	for _, blob := range raw {
+		trace := new(parityTrace)
+		if err := json.Unmarshal(blob, trace); err != nil {
+			t.Fatalf("failed to parse trace: %v", err)
+		}
+		traces = append(traces, trace)
+	}
+	if len(traces) < 2+len(sysCallKinds) {
+		t.Fatalf("trace count mismatch: have %d, want at least %d", len(traces), 2+len(sysCallKinds))
+	}
+	if traces[0].TransactionHash == nil || *traces[0].TransactionHash != txHash {
+		t.Errorf("transaction trace hash mismatch: have %v, want %x", traces[0].TransactionHash, txHash)
+	}
+	var rewards, calls int
+	for i, trace := range traces[1:] {
+		if trace.TransactionHash != nil {
+			t.Errorf("system call trace %d: transaction hash %x, want none", i, *trace.TransactionHash)
+		}
+		switch {
+		case trace.Type == "reward":
+			if i != 0 {
+				t.Errorf("reward trace at %d, want first", i)
+			}
+			if trace.Action.Author == nil || *trace.Action.Author != coinbase || trace.Action.RewardType != "block" {
+				t.Errorf("reward trace mismatch: author %v, type %q", trace.Action.Author, trace.Action.RewardType)
+			}
+			if trace.Action.Value == nil || trace.Action.Value.ToInt().Uint64() != sysCallReward {
+				t.Errorf("reward value mismatch: have %v, want %d", trace.Action.Value, sysCallReward)
+			}
+			rewards++
+
+		case len(trace.TraceAddress) == 0:
+			if trace.Type != "call" || trace.Action.From == nil || *trace.Action.From != backend.engine.from || trace.Action.To == nil || *trace.Action.To != backend.engine.to {
+				t.Errorf("system call trace %d mismatch: type %q, from %v, to %v", i, trace.Type, trace.Action.From, trace.Action.To)
+			}
+			calls++
+		}
+	}
+	if rewards != 1 || calls != len(sysCallKinds) {
+		t.Errorf("system call traces mismatch: have %d rewards and %d calls, want 1 and %d", rewards, calls, len(sysCallKinds))
+	}
+	// The reward is filtered by its author
+	filtered, err := api.Filter(context.Background(), tracers.TraceFilterArgs{ToAddress: []common.Address{coinbase}})
+	if err != nil {
+		t.Fatalf("failed to filter traces: %v", err)
+	}
+	if len(filtered) != 1 {
+		t.Fatalf("filtered trace count mismatch: have %d, want 1", len(filtered))
+	}
+	trace := new(parityTrace)
+	if err := json.Unmarshal(filtered[0], trace); err != nil || trace.Type != "reward" {
+		t.Errorf("filtered trace mismatch: have %s, want the reward", filtered[0])
+	}
+	// Replaying the block reports the system calls after the transaction
+	replays, err := api.ReplayBlockTransactions(context.Background(), rpc.LatestBlockNumber, []string{"trace", "stateDiff"})
+	if err != nil {
+		t.Fatalf("failed to replay block: %v", err)
+	}
+	if len(replays) != 1+len(sysCallKinds) {
+		t.Fatalf("replay count mismatch: have %d, want %d", len(replays), 1+len(sysCallKinds))
+	}
+	if replays[0].TransactionHash == nil || *replays[0].TransactionHash != txHash {
+		t.Errorf("transaction replay hash mismatch: have %v, want %x", replays[0].TransactionHash, txHash)
+	}
+	for i, replay := range replays[1:] {
+		if replay.TransactionHash != nil {
+			t.Errorf("system call replay %d: transaction hash %x, want none", i, *replay.TransactionHash)
+		}
+		if len(replay.Trace) == 0 {
+			t.Fatalf("system call replay %d: no traces", i)
+		}
+	}
+	// The distributed fees are credited to the called contract
+	if diff := replays[1].StateDiff[backend.engine.to]; diff == nil || diff.Balance == nil {
+		t.Errorf("fee distribution replay: no balance diff of the called contract")
+	}
+	if err := json.Unmarshal(replays[1].Trace[0], trace); err != nil || trace.Type != "reward" {
+		t.Errorf("fee distribution replay mismatch: have %s, want the reward first", replays[1].Trace[0])
+	}
+}
+
+// Tests that tracing a block with system calls enabled returns a trace of every
+// system call made when finalizing it after the ones of its transactions, each
+// labelled with its kind.
+func TestTraceBlockSystemCalls(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey()
+		sender = crypto.PubkeyToAddress(key.PublicKey)
+	)
+	backend := newSysCallBackend(t, "simple", 1, core.GenesisAlloc{sender: {Balance: big.NewInt(params.Ether)}}, func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(uint64(i), common.Address{0x1}, big.NewInt(1000), params.TxGas, b.BaseFee(), nil), types.HomesteadSigner{}, key)
+		b.AddTx(tx)
+	})
+	api := tracers.NewAPI(backend)
+
+	tracer := "callTracer"
+	for _, config := range []*tracers.TraceConfig{
+		{Tracer: &tracer, SystemCalls: true},
+		{SystemCalls: true},
+		{Tracer: &tracer},
+	} {
+		res, err := api.TraceBlockByNumber(context.Background(), rpc.LatestBlockNumber, config)
+		if err != nil {
+			t.Fatalf("failed to trace block: %v", err)
+		}
+		blob, err := json.Marshal(res)
+		if err != nil {
+			t.Fatalf("failed to encode traces: %v", err)
+		}
+		var results []struct {
+			Kind   string          `json:"kind"`
+			Result json.RawMessage `json:"result"`
+			Error  string          `json:"error"`
+		}
+		if err := json.Unmarshal(blob, &results); err != nil {
+			t.Fatalf("failed to decode traces: %v", err)
+		}
+		want := 1
+		if config.SystemCalls {
+			want += len(sysCallKinds)
+		}
+		if len(results) != want {
+			t.Fatalf("system calls %v: result count mismatch: have %d, want %d", config.SystemCalls, len(results), want)
+		}
+		if results[0].Kind != "" {
+			t.Errorf("transaction trace kind mismatch: have %q, want none", results[0].Kind)
+		}
+		for i, result := range results[1:] {
+			if result.Kind != sysCallKinds[i] {
+				t.Errorf("system call %d: kind mismatch: have %q, want %q", i, result.Kind, sysCallKinds[i])
+			}
+			if result.Error != "" {
+				t.Errorf("system call %d: trace failed: %v", i, result.Error)
+			}
+			if config.Tracer == nil {
+				var logs struct {
+					Failed     bool              `json:"failed"`
+					StructLogs []json.RawMessage `json:"structLogs"`
+				}
+				if err := json.Unmarshal(result.Result, &logs); err != nil || logs.Failed || len(logs.StructLogs) == 0 {
+					t.Errorf("system call %d: struct logs mismatch: %s", i, result.Result)
+				}
+				continue
+			}
+			var call struct {
+				From  common.Address `json:"from"`
+				To    common.Address `json:"to"`
+				Value *hexutil.Big   `json:"value"`
+			}
+			if err := json.Unmarshal(result.Result, &call); err != nil {
+				t.Fatalf("system call %d: failed to decode trace: %v", i, err)
+			}
+			if call.From != backend.engine.from || call.To != backend.engine.to {
+				t.Errorf("system call %d: call mismatch: have %x -> %x, want %x -> %x", i, call.From, call.To, backend.engine.from, backend.engine.to)
+			}
+			if result.Kind == consensus.SysCallReward && (call.Value == nil || call.Value.ToInt().Uint64() != sysCallReward) {
+				t.Errorf("system call %d: value mismatch: have %v, want %d", i, call.Value, sysCallReward)
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package tracetest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+type vmTraceResult struct {
+	Code string `json:"code"`
+	Ops  []struct {
+		Cost uint64 `json:"cost"`
+		Ex   *struct {
+			Push []string `json:"push"`
+			Used uint64   `json:"used"`
+		} `json:"ex"`
+		Pc  uint64         `json:"pc"`
+		Sub *vmTraceResult `json:"sub"`
+	} `json:"ops"`
+}
+
+type callTraceResult struct {
+	Type  string            `json:"type"`
+	Calls []callTraceResult `json:"calls"`
+}
+
+// countCalls returns the number of inner calls, self destructs excluded.
+func (c *callTraceResult) countCalls() (n int) {
+	for i := range c.Calls {
+		if c.Calls[i].Type != "SELFDESTRUCT" {
+			n++
+		}
+		n += c.Calls[i].countCalls()
+	}
+	return n
+}
+
+// countSubs returns the number of nested call frames and checks that every
+// operation reports its effects.
+func (v *vmTraceResult) countSubs(t *testing.T) (n int) {
+	for i, op := range v.Ops {
+		if op.Ex == nil || op.Ex.Push == nil {
+			t.Fatalf("op %d at pc %d has no effects", i, op.Pc)
+		}
+		if op.Sub != nil {
+			n += 1 + op.Sub.countSubs(t)
+		}
+	}
+	return n
+}
+
+// Tests that the vmTrace of a transaction nests the operations of every inner
+// call made, as reported by the call tracer.
+func TestParityVmTracer(t *testing.T) {
+	for _, name := range []string{"deep_calls", "delegatecall", "create", "selfdestruct"} {
+		t.Run(camel(name), func(t *testing.T) {
+			test := new(tracerTest)
+			blob, err := ioutil.ReadFile(filepath.Join("testdata", "call_tracer", name+".json"))
+			if err != nil {
+				t.Fatalf("failed to read testcase: %v", err)
+			}
+			if err := json.Unmarshal(blob, test); err != nil {
+				t.Fatalf("failed to parse testcase: %v", err)
+			}
+			test.TracerConfig = json.RawMessage(`{"callTracer":null,"parityVmTracer":null}`)
+			res, err := runTracerTest("muxTracer", test)
+			if err != nil {
+				t.Fatalf("failed to trace transaction: %v", err)
+			}
+			var result struct {
+				Call    callTraceResult `json:"callTracer"`
+				VmTrace vmTraceResult   `json:"parityVmTracer"`
+			}
+			if err := json.Unmarshal(res, &result); err != nil {
+				t.Fatalf("failed to unmarshal trace result: %v", err)
+			}
+			if len(result.VmTrace.Ops) == 0 || result.VmTrace.Code == "0x" {
+				t.Fatalf("empty vmTrace: %s", res)
+			}
+			if have, want := result.VmTrace.countSubs(t), result.Call.countCalls(); have != want {
+				t.Fatalf("nested frame count mismatch: have %d, want %d", have, want)
+			}
+		})
+	}
+}
@@ -409,7 +409,7 @@ type jsTracer struct {
 // New instantiates a new tracer instance. code specifies a Javascript snippet,
 // which must evaluate to an expression returning an object with 'step', 'fault'
 // and 'result' functions.
-func newJsTracer(code string, ctx *tracers2.Context) (tracers2.Tracer, error) {
+func newJsTracer(code string, ctx *tracers2.Context, _ json.RawMessage) (tracers2.Tracer, error) {
 	if c, ok := assetTracers[code]; ok {
 		code = c
 	}
@@ -745,6 +745,13 @@ func (jst *jsTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, sco
 	}
 }
 
+// CaptureTxStart implements the EVMLogger interface.
+func (jst *jsTracer) CaptureTxStart(env *vm.EVM, from common.Address, to *common.Address, gasLimit uint64) {
+}
+
+// CaptureTxEnd implements the EVMLogger interface.
+func (jst *jsTracer) CaptureTxEnd(restGas uint64) {}
+
 // CaptureEnd is called after the call finishes to finalize the tracing.
 func (jst *jsTracer) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) {
 	jst.ctx["output"] = output
@@ -40,11 +40,11 @@ type fourByteTracer struct {
 
 // newFourByteTracer returns a native go tracer which collects
 // 4 byte-identifiers of a tx, and implements vm.EVMLogger.
-func newFourByteTracer() tracers.Tracer {
+func newFourByteTracer(ctx *tracers.Context, _ json.RawMessage) (tracers.Tracer, error) {
 	t := &fourByteTracer{
 		ids: make(map[string]int),
 	}
-	return t
+	return t, nil
 }
 
 // isPrecompiled returns whether the addr is a precompile. Logic borrowed from newJsTracer in eth/tracers/js/tracer.go
@@ -112,6 +112,13 @@ func (t *fourByteTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
 func (t *fourByteTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
 }
 
+// CaptureTxStart implements the EVMLogger interface.
+func (t *fourByteTracer) CaptureTxStart(env *vm.EVM, from common.Address, to *common.Address, gasLimit uint64) {
+}
+
+// CaptureTxEnd implements the EVMLogger interface.
+func (t *fourByteTracer) CaptureTxEnd(restGas uint64) {}
+
 // CaptureEnd is called after the call finishes to finalize the tracing.
 func (t *fourByteTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) {
 }
@@ -0,0 +1,278 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package native
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+)
+
+func init() {
+	register("flatCallTracer", newFlatCallTracer)
+}
+
+var parityErrorMapping = map[string]string{
+	"contract creation code storage out of gas": "Out of gas",
+	"out of gas":                      "Out of gas",
+	"gas uint64 overflow":             "Out of gas",
+	"max code size exceeded":          "Out of gas",
+	"invalid jump destination":        "Bad jump destination",
+	"execution reverted":              "Reverted",
+	"return data out of bounds":       "Out of bounds",
+	"stack limit reached 1024 (1023)": "Out of stack",
+	"precompiled failed":              "Built-in failed",
+	"invalid input length":            "Built-in failed",
+}
+
+var parityErrorMappingStartingWith = map[string]string{
+	"invalid opcode:": "Bad instruction",
+	"stack underflow": "Stack underflow",
+}
+
+// flatCallFrame is a single call in the flat, Parity compatible format.
+type flatCallFrame struct {
+	Action              flatCallAction  `json:"action"`
+	BlockHash           *common.Hash    `json:"blockHash"`
+	BlockNumber         uint64          `json:"blockNumber"`
+	Error               string          `json:"error,omitempty"`
+	Result              *flatCallResult `json:"result,omitempty"`
+	Subtraces           int             `json:"subtraces"`
+	TraceAddress        []int           `json:"traceAddress"`
+	TransactionHash     *common.Hash    `json:"transactionHash"`
+	TransactionPosition uint64          `json:"transactionPosition"`
+	Type                string          `json:"type"`
+}
+
+type flatCallAction struct {
+	Address        string `json:"address,omitempty"`
+	Balance        string `json:"balance,omitempty"`
+	CallType       string `json:"callType,omitempty"`
+	CreationMethod string `json:"creationMethod,omitempty"`
+	From           string `json:"from,omitempty"`
+	Gas            string `json:"gas,omitempty"`
+	Init           string `json:"init,omitempty"`
+	Input          string `json:"input,omitempty"`
+	RefundAddress  string `json:"refundAddress,omitempty"`
+	To             string `json:"to,omitempty"`
+	Value          string `json:"value,omitempty"`
+}
+
+type flatCallResult struct {
+	Address string `json:"address,omitempty"`
+	Code    string `json:"code,omitempty"`
+	GasUsed string `json:"gasUsed,omitempty"`
+	Output  string `json:"output,omitempty"`
+}
+
+type flatCallTracerConfig struct {
+	ConvertParityErrors bool `json:"convertParityErrors"` // If true, call tracer converts errors to parity format
+	IncludePrecompiles  bool `json:"includePrecompiles"`  // If true, call tracer includes calls to precompiled contracts
+}
+
+// flatCallTracer reports the calls of a transaction as a flat list in the
+// format of Parity's trace module. It runs the call tracer and converts its
+// nested output.
+type flatCallTracer struct {
+	tracer            *callTracer
+	config            flatCallTracerConfig
+	ctx               *tracers.Context // Holds tracer context data
+	activePrecompiles []common.Address // Updated on CaptureStart based on given rules
+}
+
+// newFlatCallTracer returns a native go tracer which reports the call frames
+// of a tx as a flat list, and implements vm.EVMLogger.
+func newFlatCallTracer(ctx *tracers.Context, cfg json.RawMessage) (tracers.Tracer, error) {
+	var config flatCallTracerConfig
+	if cfg != nil {
+		if err := json.Unmarshal(cfg, &config); err != nil {
+			return nil, err
+		}
+	}
+	tracer, err := newCallTracer(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if ctx == nil {
+		ctx = new(tracers.Context)
+	}
+	return &flatCallTracer{tracer: tracer.(*callTracer), config: config, ctx: ctx}, nil
+}
+
+// CaptureTxStart implements the EVMLogger interface.
+func (t *flatCallTracer) CaptureTxStart(env *vm.EVM, from common.Address, to *common.Address, gasLimit uint64) {
+	t.tracer.CaptureTxStart(env, from, to, gasLimit)
+}
+
+// CaptureTxEnd implements the EVMLogger interface.
+func (t *flatCallTracer) CaptureTxEnd(restGas uint64) {
+	t.tracer.CaptureTxEnd(restGas)
+}
+
+// CaptureStart implements the EVMLogger interface to initialize the tracing operation.
+func (t *flatCallTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.tracer.CaptureStart(env, from, to, create, input, gas, value)
+
+	// Update list of precompiles based on current block
+	rules := env.ChainConfig().Rules(env.Context.BlockNumber)
+	t.activePrecompiles = vm.ActivePrecompiles(rules)
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *flatCallTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) {
+	t.tracer.CaptureEnd(output, gasUsed, d, err)
+}
+
+// CaptureState implements the EVMLogger interface to trace a single step of VM execution.
+func (t *flatCallTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	t.tracer.CaptureState(pc, op, gas, cost, scope, rData, depth, err)
+}
+
+// CaptureFault implements the EVMLogger interface to trace an execution fault.
+func (t *flatCallTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+	t.tracer.CaptureFault(pc, op, gas, cost, scope, depth, err)
+}
+
+// CaptureEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *flatCallTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	t.tracer.CaptureEnter(typ, from, to, input, gas, value)
+}
+
+// CaptureExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *flatCallTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	t.tracer.CaptureExit(output, gasUsed, err)
+
+	// Parity traces don't include CALL/STATICCALLs to precompiles.
+	if t.config.IncludePrecompiles || len(t.tracer.callstack) == 0 {
+		return
+	}
+	parent := &t.tracer.callstack[len(t.tracer.callstack)-1]
+	if len(parent.Calls) == 0 {
+		return
+	}
+	call := parent.Calls[len(parent.Calls)-1]
+	if (call.Type == vm.CALL.String() || call.Type == vm.STATICCALL.String()) && t.isPrecompiled(call.To) {
+		parent.Calls = parent.Calls[:len(parent.Calls)-1]
+	}
+}
+
+// GetResult returns the json-encoded flat list of call traces, and any
+// error arising from the encoding or forceful termination (via `Stop`).
+func (t *flatCallTracer) GetResult() (json.RawMessage, error) {
+	if len(t.tracer.callstack) != 1 {
+		return nil, errors.New("incorrect number of top-level calls")
+	}
+	flat := t.flatten(&t.tracer.callstack[0], []int{}, nil)
+
+	res, err := json.Marshal(flat)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(res), t.tracer.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *flatCallTracer) Stop(err error) {
+	t.tracer.Stop(err)
+}
+
+// isPrecompiled returns whether the hex encoded addr is a precompile.
+func (t *flatCallTracer) isPrecompiled(addr string) bool {
+	for _, p := range t.activePrecompiles {
+		if addrToHex(p) == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// flatten appends the given call frame and all its children, depth first, to
+// the flat list of calls.
+func (t *flatCallTracer) flatten(call *callFrame, traceAddress []int, flat []*flatCallFrame) []*flatCallFrame {
+	frame := &flatCallFrame{
+		BlockHash:           &t.ctx.BlockHash,
+		Subtraces:           len(call.Calls),
+		TraceAddress:        traceAddress,
+		TransactionHash:     &t.ctx.TxHash,
+		TransactionPosition: uint64(t.ctx.TxIndex),
+	}
+	if t.ctx.BlockNumber != nil {
+		frame.BlockNumber = t.ctx.BlockNumber.Uint64()
+	}
+	value := call.Value
+	if value == "" {
+		value = "0x0"
+	}
+	switch call.Type {
+	case vm.CREATE.String(), vm.CREATE2.String():
+		frame.Type = "create"
+		frame.Action = flatCallAction{
+			CreationMethod: strings.ToLower(call.Type),
+			From:           call.From,
+			Gas:            call.Gas,
+			Init:           call.Input,
+			Value:          value,
+		}
+		frame.Result = &flatCallResult{
+			Address: call.To,
+			Code:    call.Output,
+			GasUsed: call.GasUsed,
+		}
+	case vm.SELFDESTRUCT.String():
+		frame.Type = "suicide"
+		frame.Action = flatCallAction{
+			Address:       call.From,
+			Balance:       value,
+			RefundAddress: call.To,
+		}
+	default:
+		frame.Type = "call"
+		frame.Action = flatCallAction{
+			CallType: strings.ToLower(call.Type),
+			From:     call.From,
+			Gas:      call.Gas,
+			Input:    call.Input,
+			To:       call.To,
+			Value:    value,
+		}
+		frame.Result = &flatCallResult{
+			GasUsed: call.GasUsed,
+			Output:  call.Output,
+		}
+	}
+	if call.Error != "" {
+		frame.Error = call.Error
+		if t.config.ConvertParityErrors {
+			frame.Error = convertErrorToParity(call.Error)
+		}
+		frame.Result = nil
+	}
+	flat = append(flat, frame)
+
+	for i := range call.Calls {
+		childAddress := make([]int, len(traceAddress), len(traceAddress)+1)
+		copy(childAddress, traceAddress)
+		flat = t.flatten(&call.Calls[i], append(childAddress, i), flat)
+	}
+	return flat
+}
+
+// convertErrorToParity maps an EVM error to its Parity counterpart.
+func convertErrorToParity(err string) string {
+	if parityErr, ok := parityErrorMapping[err]; ok {
+		return parityErr
+	}
+	for prefix, parityErr := range parityErrorMappingStartingWith {
+		if strings.HasPrefix(err, prefix) {
+			return parityErr
+		}
+	}
+	return err
+}
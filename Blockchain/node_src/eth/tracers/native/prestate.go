@@ -0,0 +1,318 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package native
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+)
+
+func init() {
+	register("prestateTracer", newPrestateTracer)
+}
+
+type state = map[common.Address]*account
+
+type account struct {
+	Balance *big.Int
+	Code    []byte
+	Nonce   uint64
+	Storage map[common.Hash]common.Hash
+}
+
+// exists returns whether the account has any content, i.e. wasn't empty
+// before the transaction.
+func (a *account) exists() bool {
+	return a.Nonce > 0 || len(a.Code) > 0 || len(a.Storage) > 0 || (a.Balance != nil && a.Balance.Sign() != 0)
+}
+
+// MarshalJSON encodes the account in the format of the legacy JavaScript
+// prestate tracer, which is also accepted as a genesis allocation.
+func (a *account) MarshalJSON() ([]byte, error) {
+	type account struct {
+		Balance *hexutil.Big                `json:"balance,omitempty"`
+		Code    hexutil.Bytes               `json:"code,omitempty"`
+		Nonce   uint64                      `json:"nonce,omitempty"`
+		Storage map[common.Hash]common.Hash `json:"storage,omitempty"`
+	}
+	return json.Marshal(&account{
+		Balance: (*hexutil.Big)(a.Balance),
+		Code:    a.Code,
+		Nonce:   a.Nonce,
+		Storage: a.Storage,
+	})
+}
+
+type prestateTracerConfig struct {
+	DiffMode bool `json:"diffMode"` // If true, this tracer will return state modifications
+}
+
+// prestateTracer collects all the accounts and storage slots touched by a
+// transaction along with their values before the transaction. In diff mode
+// the values after the transaction are reported too, limited to the ones
+// which actually changed.
+type prestateTracer struct {
+	env       *vm.EVM
+	pre       state
+	post      state
+	config    prestateTracerConfig
+	txStarted bool                    // Whether the trace wraps a full state transition
+	processed bool                    // Whether the post state was already assembled
+	created   map[common.Address]bool // Accounts created by the transaction
+	deleted   map[common.Address]bool // Accounts self destructed by the transaction
+	interrupt uint32                  // Atomic flag to signal execution interruption
+	reason    error                   // Textual reason for the interruption
+}
+
+// newPrestateTracer returns a native go tracer which collects the pre-state
+// of all accounts touched by a tx, and implements vm.EVMLogger.
+func newPrestateTracer(ctx *tracers.Context, cfg json.RawMessage) (tracers.Tracer, error) {
+	var config prestateTracerConfig
+	if cfg != nil {
+		if err := json.Unmarshal(cfg, &config); err != nil {
+			return nil, err
+		}
+	}
+	return &prestateTracer{
+		pre:     state{},
+		post:    state{},
+		config:  config,
+		created: make(map[common.Address]bool),
+		deleted: make(map[common.Address]bool),
+	}, nil
+}
+
+// CaptureTxStart implements the EVMLogger interface. It's invoked before any
+// gas is bought, so the accounts touched by the fee handling are read here.
+func (t *prestateTracer) CaptureTxStart(env *vm.EVM, from common.Address, to *common.Address, gasLimit uint64) {
+	t.env = env
+	t.txStarted = true
+
+	t.lookupAccount(from)
+	if to != nil {
+		t.lookupAccount(*to)
+	}
+	t.lookupAccount(env.Context.Coinbase)
+	if env.ChainConfig().Congress != nil {
+		t.lookupAccount(consensus.FeeRecoder)
+	}
+}
+
+// CaptureTxEnd implements the EVMLogger interface. All refunds and fees are
+// settled at this point, so the post state is assembled here.
+func (t *prestateTracer) CaptureTxEnd(restGas uint64) {
+	t.processDiffState()
+}
+
+// CaptureStart implements the EVMLogger interface to initialize the tracing operation.
+func (t *prestateTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.env = env
+
+	// Outside of a state transition (e.g. a system transaction) the value has
+	// already been transferred, and the sender nonce increased on creation.
+	if !t.txStarted {
+		t.lookupAccount(from)
+		t.pre[from].Balance = new(big.Int).Add(t.pre[from].Balance, value)
+		if create && t.pre[from].Nonce > 0 {
+			t.pre[from].Nonce--
+		}
+		if !create {
+			t.lookupAccount(to)
+			t.pre[to].Balance = new(big.Int).Sub(t.pre[to].Balance, value)
+		}
+	}
+	// A created contract already received the value and its initial nonce.
+	if create {
+		t.lookupAccount(to)
+		t.pre[to].Balance = new(big.Int).Sub(t.pre[to].Balance, value)
+		if env.ChainConfig().IsEIP158(env.Context.BlockNumber) && t.pre[to].Nonce > 0 {
+			t.pre[to].Nonce--
+		}
+		t.created[to] = true
+	}
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *prestateTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) {
+}
+
+// CaptureState implements the EVMLogger interface to trace a single step of VM execution.
+func (t *prestateTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if err != nil {
+		return
+	}
+	// Skip if tracing was interrupted
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		t.env.Cancel()
+		return
+	}
+	var (
+		stackData = scope.Stack.Data()
+		stackLen  = len(stackData)
+		caller    = scope.Contract.Address()
+	)
+	switch {
+	case stackLen >= 1 && (op == vm.SLOAD || op == vm.SSTORE):
+		slot := common.Hash(stackData[stackLen-1].Bytes32())
+		t.lookupStorage(caller, slot)
+	case stackLen >= 1 && (op == vm.EXTCODECOPY || op == vm.EXTCODEHASH || op == vm.EXTCODESIZE || op == vm.BALANCE || op == vm.SELFDESTRUCT):
+		addr := common.Address(stackData[stackLen-1].Bytes20())
+		t.lookupAccount(addr)
+		if op == vm.SELFDESTRUCT {
+			t.lookupAccount(caller)
+			t.deleted[caller] = true
+		}
+	case stackLen >= 5 && (op == vm.DELEGATECALL || op == vm.CALL || op == vm.STATICCALL || op == vm.CALLCODE):
+		addr := common.Address(stackData[stackLen-2].Bytes20())
+		t.lookupAccount(addr)
+	case op == vm.CREATE:
+		addr := crypto.CreateAddress(caller, t.env.StateDB.GetNonce(caller))
+		t.lookupAccount(addr)
+		t.created[addr] = true
+	case stackLen >= 4 && op == vm.CREATE2:
+		offset, size := stackData[stackLen-2], stackData[stackLen-3]
+		init := scope.Memory.GetCopy(int64(offset.Uint64()), int64(size.Uint64()))
+		addr := crypto.CreateAddress2(caller, stackData[stackLen-4].Bytes32(), crypto.Keccak256(init))
+		t.lookupAccount(addr)
+		t.created[addr] = true
+	}
+}
+
+// CaptureFault implements the EVMLogger interface to trace an execution fault.
+func (t *prestateTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, _ *vm.ScopeContext, depth int, err error) {
+}
+
+// CaptureEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *prestateTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+}
+
+// CaptureExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *prestateTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+}
+
+// GetResult returns the json-encoded pre-state, or the pre- and post-state in
+// diff mode, and any error arising from the encoding or forceful termination
+// (via `Stop`).
+func (t *prestateTracer) GetResult() (json.RawMessage, error) {
+	var (
+		res []byte
+		err error
+	)
+	if t.config.DiffMode {
+		// Traces outside of a state transition have no end hook
+		t.processDiffState()
+		res, err = json.Marshal(struct {
+			Post state `json:"post"`
+			Pre  state `json:"pre"`
+		}{t.post, t.pre})
+	} else {
+		res, err = json.Marshal(t.pre)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(res), t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *prestateTracer) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
+
+// processDiffState assembles the post state of all touched accounts and drops
+// everything which wasn't modified from both the pre and the post state.
+func (t *prestateTracer) processDiffState() {
+	if !t.config.DiffMode || t.processed || t.env == nil {
+		return
+	}
+	t.processed = true
+
+	for addr, pre := range t.pre {
+		// The deleted account's state is pruned from post but kept in pre
+		if t.deleted[addr] {
+			continue
+		}
+		var (
+			modified = false
+			post     = &account{Storage: make(map[common.Hash]common.Hash)}
+			balance  = t.env.StateDB.GetBalance(addr)
+			nonce    = t.env.StateDB.GetNonce(addr)
+			code     = t.env.StateDB.GetCode(addr)
+		)
+		if balance.Cmp(pre.Balance) != 0 {
+			modified = true
+			post.Balance = new(big.Int).Set(balance)
+		}
+		if nonce != pre.Nonce {
+			modified = true
+			post.Nonce = nonce
+		}
+		if !bytes.Equal(code, pre.Code) {
+			modified = true
+			post.Code = common.CopyBytes(code)
+		}
+		for key, val := range pre.Storage {
+			newVal := t.env.StateDB.GetState(addr, key)
+			if val == newVal {
+				// Omit unchanged slots
+				delete(pre.Storage, key)
+				continue
+			}
+			modified = true
+			if val == (common.Hash{}) {
+				delete(pre.Storage, key)
+			}
+			if newVal != (common.Hash{}) {
+				post.Storage[key] = newVal
+			}
+		}
+		if modified {
+			t.post[addr] = post
+		} else {
+			// Unmodified accounts are not part of the diff
+			delete(t.pre, addr)
+		}
+	}
+	// Created contracts didn't exist before, so they have no pre state
+	for addr := range t.created {
+		if pre := t.pre[addr]; pre != nil && !pre.exists() {
+			delete(t.pre, addr)
+		}
+	}
+}
+
+// lookupAccount fetches details of an account and adds it to the prestate
+// if it doesn't exist there.
+func (t *prestateTracer) lookupAccount(addr common.Address) {
+	if _, ok := t.pre[addr]; ok {
+		return
+	}
+	t.pre[addr] = &account{
+		Balance: new(big.Int).Set(t.env.StateDB.GetBalance(addr)),
+		Nonce:   t.env.StateDB.GetNonce(addr),
+		Code:    common.CopyBytes(t.env.StateDB.GetCode(addr)),
+		Storage: make(map[common.Hash]common.Hash),
+	}
+}
+
+// lookupStorage fetches the value of a storage slot as of the beginning of
+// the transaction and adds it to the prestate if not yet there.
+func (t *prestateTracer) lookupStorage(addr common.Address, key common.Hash) {
+	t.lookupAccount(addr)
+	if _, ok := t.pre[addr].Storage[key]; ok {
+		return
+	}
+	t.pre[addr].Storage[key] = t.env.StateDB.GetCommittedState(addr, key)
+}
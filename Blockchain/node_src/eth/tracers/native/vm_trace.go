@@ -0,0 +1,283 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package native
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/holiman/uint256"
+)
+
+func init() {
+	register("parityVmTracer", newParityVmTracer)
+}
+
+// vmTrace is the execution of a single call frame in the vmTrace format of
+// Parity's trace module.
+type vmTrace struct {
+	Code hexutil.Bytes `json:"code"`
+	Ops  []*vmTraceOp  `json:"ops"`
+}
+
+type vmTraceOp struct {
+	Cost uint64     `json:"cost"`
+	Ex   *vmTraceEx `json:"ex"`
+	Pc   uint64     `json:"pc"`
+	Sub  *vmTrace   `json:"sub"`
+}
+
+type vmTraceEx struct {
+	Mem   *vmTraceMem   `json:"mem"`
+	Push  []string      `json:"push"`
+	Store *vmTraceStore `json:"store"`
+	Used  uint64        `json:"used"`
+}
+
+type vmTraceMem struct {
+	Data hexutil.Bytes `json:"data"`
+	Off  uint64        `json:"off"`
+}
+
+type vmTraceStore struct {
+	Key string `json:"key"`
+	Val string `json:"val"`
+}
+
+// vmTraceFrame tracks the call frame being executed and the last operation
+// within it, whose effects are only known once the next one starts.
+type vmTraceFrame struct {
+	trace   *vmTrace
+	pending *vmTraceOp
+	gas     uint64 // Gas available before the pending operation
+	pushes  int    // Number of stack items produced by the pending operation
+	memOff  uint64 // Offset of the memory written by the pending operation
+	memSize uint64 // Size of the memory written by the pending operation
+}
+
+// parityVmTracer records every executed operation along with the stack items,
+// memory and storage it wrote, nesting the operations of inner calls.
+type parityVmTracer struct {
+	callstack []*vmTraceFrame
+	root      *vmTrace
+	interrupt uint32 // Atomic flag to signal execution interruption
+	reason    error  // Textual reason for the interruption
+}
+
+// newParityVmTracer returns a native go tracer which produces Parity's
+// vmTrace output, and implements vm.EVMLogger.
+func newParityVmTracer(ctx *tracers.Context, _ json.RawMessage) (tracers.Tracer, error) {
+	return &parityVmTracer{}, nil
+}
+
+// CaptureTxStart implements the EVMLogger interface.
+func (t *parityVmTracer) CaptureTxStart(env *vm.EVM, from common.Address, to *common.Address, gasLimit uint64) {
+}
+
+// CaptureTxEnd implements the EVMLogger interface.
+func (t *parityVmTracer) CaptureTxEnd(restGas uint64) {}
+
+// CaptureStart implements the EVMLogger interface to initialize the tracing operation.
+func (t *parityVmTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.root = &vmTrace{Ops: []*vmTraceOp{}}
+	if create {
+		t.root.Code = common.CopyBytes(input)
+	} else {
+		t.root.Code = common.CopyBytes(env.StateDB.GetCode(to))
+	}
+	t.callstack = []*vmTraceFrame{{trace: t.root}}
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *parityVmTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) {
+	if len(t.callstack) > 0 {
+		t.callstack[0].settle(nil)
+	}
+}
+
+// CaptureState implements the EVMLogger interface to trace a single step of VM execution.
+func (t *parityVmTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if err != nil || len(t.callstack) == 0 {
+		return
+	}
+	// Skip if tracing was interrupted
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		return
+	}
+	frame := t.callstack[len(t.callstack)-1]
+	frame.settle(&vmTraceEffects{gas: gas, scope: scope})
+	if frame.trace.Code == nil {
+		frame.trace.Code = common.CopyBytes(scope.Contract.Code)
+	}
+
+	step := &vmTraceOp{Cost: cost, Pc: pc}
+	frame.trace.Ops = append(frame.trace.Ops, step)
+	frame.pending, frame.gas, frame.pushes = step, gas, vmTracePushes(op)
+	frame.memOff, frame.memSize = vmTraceMemWrite(op, scope.Stack.Data())
+
+	if stack := scope.Stack.Data(); op == vm.SSTORE && len(stack) >= 2 {
+		step.Ex = &vmTraceEx{Store: &vmTraceStore{
+			Key: stack[len(stack)-1].Hex(),
+			Val: stack[len(stack)-2].Hex(),
+		}}
+	}
+}
+
+// CaptureFault implements the EVMLogger interface to trace an execution fault.
+func (t *parityVmTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, _ *vm.ScopeContext, depth int, err error) {
+}
+
+// CaptureEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *parityVmTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if len(t.callstack) == 0 || typ == vm.SELFDESTRUCT {
+		return
+	}
+	sub := &vmTrace{Ops: []*vmTraceOp{}}
+	if typ == vm.CREATE || typ == vm.CREATE2 {
+		sub.Code = common.CopyBytes(input)
+	}
+	if parent := t.callstack[len(t.callstack)-1]; parent.pending != nil {
+		parent.pending.Sub = sub
+	}
+	t.callstack = append(t.callstack, &vmTraceFrame{trace: sub})
+}
+
+// CaptureExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *parityVmTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if len(t.callstack) <= 1 {
+		return
+	}
+	frame := t.callstack[len(t.callstack)-1]
+	frame.settle(nil)
+	t.callstack = t.callstack[:len(t.callstack)-1]
+
+	// Pure value transfers and precompiles don't execute any code
+	if len(frame.trace.Ops) == 0 {
+		frame.trace.Code = hexutil.Bytes{}
+	}
+}
+
+// GetResult returns the json-encoded vmTrace of the outermost call frame, and
+// any error arising from the encoding or forceful termination (via `Stop`).
+func (t *parityVmTracer) GetResult() (json.RawMessage, error) {
+	res, err := json.Marshal(t.root)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(res), t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *parityVmTracer) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
+
+// vmTraceEffects is the execution context right after an operation, from
+// which its effects are read.
+type vmTraceEffects struct {
+	gas   uint64
+	scope *vm.ScopeContext
+}
+
+// settle fills in the effects of the pending operation of the frame. Without
+// a following step (i.e. the frame exited) only the gas usage is known.
+func (f *vmTraceFrame) settle(next *vmTraceEffects) {
+	if f.pending == nil {
+		return
+	}
+	step := f.pending
+	f.pending = nil
+
+	if step.Ex == nil {
+		step.Ex = new(vmTraceEx)
+	}
+	step.Ex.Push = []string{}
+	if next == nil {
+		if f.gas > step.Cost {
+			step.Ex.Used = f.gas - step.Cost
+		}
+		return
+	}
+	step.Ex.Used = next.gas
+
+	stack := next.scope.Stack.Data()
+	if f.pushes > len(stack) {
+		f.pushes = len(stack)
+	}
+	for _, item := range stack[len(stack)-f.pushes:] {
+		step.Ex.Push = append(step.Ex.Push, item.Hex())
+	}
+	if f.memSize > 0 && f.memOff+f.memSize <= uint64(next.scope.Memory.Len()) {
+		step.Ex.Mem = &vmTraceMem{
+			Data: next.scope.Memory.GetCopy(int64(f.memOff), int64(f.memSize)),
+			Off:  f.memOff,
+		}
+	}
+}
+
+// vmTracePushes returns the number of stack items reported as written by the
+// given operation.
+func vmTracePushes(op vm.OpCode) int {
+	switch {
+	case op >= vm.PUSH1 && op <= vm.PUSH32:
+		return 1
+	case op >= vm.DUP1 && op <= vm.DUP16:
+		return int(op-vm.DUP1) + 2
+	case op >= vm.SWAP1 && op <= vm.SWAP16:
+		return int(op-vm.SWAP1) + 2
+	case op >= vm.LOG0 && op <= vm.LOG4:
+		return 0
+	}
+	switch op {
+	case vm.STOP, vm.POP, vm.MSTORE, vm.MSTORE8, vm.SSTORE, vm.JUMP, vm.JUMPI, vm.JUMPDEST,
+		vm.RETURN, vm.REVERT, vm.SELFDESTRUCT,
+		vm.CALLDATACOPY, vm.CODECOPY, vm.EXTCODECOPY, vm.RETURNDATACOPY:
+		return 0
+	}
+	return 1
+}
+
+// vmTraceMemWrite returns the memory region written by the given operation,
+// based on the stack before its execution.
+func vmTraceMemWrite(op vm.OpCode, stack []uint256.Int) (uint64, uint64) {
+	var off, size int
+	switch op {
+	case vm.MSTORE:
+		off, size = 1, -32
+	case vm.MSTORE8:
+		off, size = 1, -1
+	case vm.CALLDATACOPY, vm.CODECOPY, vm.RETURNDATACOPY:
+		off, size = 1, 3
+	case vm.EXTCODECOPY:
+		off, size = 2, 4
+	case vm.CALL, vm.CALLCODE:
+		off, size = 6, 7
+	case vm.DELEGATECALL, vm.STATICCALL:
+		off, size = 5, 6
+	default:
+		return 0, 0
+	}
+	if len(stack) < off || (size > 0 && len(stack) < size) {
+		return 0, 0
+	}
+	offset := stack[len(stack)-off]
+	if !offset.IsUint64() {
+		return 0, 0
+	}
+	if size < 0 {
+		return offset.Uint64(), uint64(-size)
+	}
+	length := stack[len(stack)-size]
+	if !length.IsUint64() || offset.Uint64()+length.Uint64() < offset.Uint64() {
+		return 0, 0
+	}
+	return offset.Uint64(), length.Uint64()
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package tracers
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// diffValue is a single entry of a Parity state diff. It's either "=" for an
+// unchanged value, {"+": new} for a born one, {"-": old} for a dead one or
+// {"*": {"from": old, "to": new}} for a modified one.
+type diffValue struct {
+	born, died, changed bool
+	from, to            interface{}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d *diffValue) MarshalJSON() ([]byte, error) {
+	switch {
+	case d.born:
+		return json.Marshal(map[string]interface{}{"+": d.to})
+	case d.died:
+		return json.Marshal(map[string]interface{}{"-": d.from})
+	case d.changed:
+		return json.Marshal(map[string]interface{}{"*": map[string]interface{}{"from": d.from, "to": d.to}})
+	}
+	return json.Marshal("=")
+}
+
+// accountDiff is the Parity state diff of a single account.
+type accountDiff struct {
+	Balance *diffValue                 `json:"balance"`
+	Code    *diffValue                 `json:"code"`
+	Nonce   *diffValue                 `json:"nonce"`
+	Storage map[common.Hash]*diffValue `json:"storage"`
+}
+
+// prestateAccount is an account as reported by the prestate tracer.
+type prestateAccount struct {
+	Balance *hexutil.Big                `json:"balance"`
+	Code    hexutil.Bytes               `json:"code"`
+	Nonce   uint64                      `json:"nonce"`
+	Storage map[common.Hash]common.Hash `json:"storage"`
+}
+
+func (a *prestateAccount) code() hexutil.Bytes {
+	if a.Code == nil {
+		return hexutil.Bytes{}
+	}
+	return a.Code
+}
+
+func (a *prestateAccount) balance() *hexutil.Big {
+	if a.Balance == nil {
+		return new(hexutil.Big)
+	}
+	return a.Balance
+}
+
+// parseStateDiff converts the result of the prestate tracer in diff mode into
+// a Parity state diff.
+func parseStateDiff(res json.RawMessage) (map[common.Address]*accountDiff, error) {
+	var prestate struct {
+		Pre  map[common.Address]*prestateAccount `json:"pre"`
+		Post map[common.Address]*prestateAccount `json:"post"`
+	}
+	if err := json.Unmarshal(res, &prestate); err != nil {
+		return nil, err
+	}
+	diff := make(map[common.Address]*accountDiff)
+	for addr, post := range prestate.Post {
+		pre, ok := prestate.Pre[addr]
+		if !ok {
+			// The account was created by the transaction
+			d := &accountDiff{
+				Balance: &diffValue{born: true, to: post.balance()},
+				Code:    &diffValue{born: true, to: post.code()},
+				Nonce:   &diffValue{born: true, to: hexutil.Uint64(post.Nonce)},
+				Storage: make(map[common.Hash]*diffValue),
+			}
+			for key, val := range post.Storage {
+				d.Storage[key] = &diffValue{born: true, to: val}
+			}
+			diff[addr] = d
+			continue
+		}
+		d := &accountDiff{
+			Balance: &diffValue{},
+			Code:    &diffValue{},
+			Nonce:   &diffValue{},
+			Storage: make(map[common.Hash]*diffValue),
+		}
+		if post.Balance != nil {
+			d.Balance = &diffValue{changed: true, from: pre.balance(), to: post.Balance}
+		}
+		if post.Code != nil {
+			d.Code = &diffValue{changed: true, from: pre.code(), to: post.Code}
+		}
+		if post.Nonce != 0 {
+			d.Nonce = &diffValue{changed: true, from: hexutil.Uint64(pre.Nonce), to: hexutil.Uint64(post.Nonce)}
+		}
+		// Changed slots are only reported with a non-zero value on either side
+		for key, val := range pre.Storage {
+			d.Storage[key] = &diffValue{changed: true, from: val, to: post.Storage[key]}
+		}
+		for key, val := range post.Storage {
+			if _, ok := pre.Storage[key]; !ok {
+				d.Storage[key] = &diffValue{changed: true, from: common.Hash{}, to: val}
+			}
+		}
+		diff[addr] = d
+	}
+	// Accounts only present in the pre state were destructed
+	for addr, pre := range prestate.Pre {
+		if _, ok := prestate.Post[addr]; ok {
+			continue
+		}
+		d := &accountDiff{
+			Balance: &diffValue{died: true, from: pre.balance()},
+			Code:    &diffValue{died: true, from: pre.code()},
+			Nonce:   &diffValue{died: true, from: hexutil.Uint64(pre.Nonce)},
+			Storage: make(map[common.Hash]*diffValue),
+		}
+		for key, val := range pre.Storage {
+			d.Storage[key] = &diffValue{died: true, from: val}
+		}
+		diff[addr] = d
+	}
+	return diff, nil
+}
@@ -73,6 +73,10 @@ type AncientReader interface {
 
 	// AncientSize returns the ancient size of the specified category.
 	AncientSize(kind string) (uint64, error)
+
+	// AncientTail returns the number of the first item retained in the specified
+	// category, the items below it were discarded from the tail.
+	AncientTail(kind string) (uint64, error)
 }
 
 // AncientBatchReader is the interface for 'batched' or 'atomic' reading.
@@ -94,6 +98,15 @@ type AncientWriter interface {
 	// TruncateAncients discards all but the first n ancient data from the ancient store.
 	TruncateAncients(n uint64) error
 
+	// TruncateAncientTail discards the items below the given number from the
+	// specified category. Data is discarded in whole files, so some of the items
+	// below the threshold might be retained.
+	TruncateAncientTail(kind string, tail uint64) error
+
+	// ResetAncients discards all the ancient data and restarts the empty ancient
+	// store at the given item number.
+	ResetAncients(tail uint64) error
+
 	// Sync flushes all in-memory ancient store data to disk.
 	Sync() error
 }
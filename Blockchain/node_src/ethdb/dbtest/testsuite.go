@@ -299,6 +299,57 @@ func TestDatabaseSuite(t *testing.T, New func() ethdb.KeyValueStore) {
 		}
 	})
 
+	t.Run("Snapshot", func(t *testing.T) {
+		db := New()
+		defer db.Close()
+
+		snapshotter, ok := db.(ethdb.Snapshotter)
+		if !ok {
+			t.Skip("snapshots not supported")
+		}
+		initial := map[string]string{"k1": "v1", "k2": "v2", "k3": ""}
+		for k, v := range initial {
+			if err := db.Put([]byte(k), []byte(v)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		snapshot, err := snapshotter.NewSnapshot()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer snapshot.Release()
+
+		// Mutations after the snapshot was taken must not be visible through it
+		if err := db.Put([]byte("k1"), []byte("v1-new")); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Delete([]byte("k2")); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Put([]byte("k4"), []byte("v4")); err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range initial {
+			got, err := snapshot.Get([]byte(k))
+			if err != nil {
+				t.Fatalf("failed to get %s: %v", k, err)
+			}
+			if !bytes.Equal(got, []byte(v)) {
+				t.Errorf("wrong value for %s: got %q, want %q", k, got, v)
+			}
+			if has, err := snapshot.Has([]byte(k)); err != nil || !has {
+				t.Errorf("key %s missing from snapshot: %v", k, err)
+			}
+		}
+		if has, err := snapshot.Has([]byte("k4")); err != nil || has {
+			t.Errorf("later key visible in snapshot: %v", err)
+		}
+		if _, err := snapshot.Get([]byte("k4")); err == nil {
+			t.Error("expected error for later key")
+		}
+		// Releasing multiple times must be allowed
+		snapshot.Release()
+	})
 }
 
 func iterateKeys(it ethdb.Iterator) []string {
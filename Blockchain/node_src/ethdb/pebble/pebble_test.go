@@ -0,0 +1,25 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+//go:build arm64 || amd64
+// +build arm64 amd64
+
+package pebble
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/dbtest"
+)
+
+func TestPebbleDB(t *testing.T) {
+	t.Run("DatabaseSuite", func(t *testing.T) {
+		dbtest.TestDatabaseSuite(t, func() ethdb.KeyValueStore {
+			db, err := NewMemory()
+			if err != nil {
+				t.Fatal(err)
+			}
+			return db
+		})
+	})
+}
@@ -0,0 +1,23 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package ethdb
+
+// Snapshot is a read-only view of a key-value store frozen at the point in time
+// it was taken. Writes made to the store afterwards are not visible through it.
+type Snapshot interface {
+	KeyValueReader
+
+	// Release releases associated resources. Release should always succeed and can
+	// be called multiple times without causing error.
+	Release()
+}
+
+// Snapshotter wraps the NewSnapshot method of a backing data store.
+type Snapshotter interface {
+	// NewSnapshot creates a database snapshot based on the current state.
+	// The created snapshot will not be affected by all following mutations
+	// happened on the database.
+	// Note don't forget to release the snapshot once it's used up, otherwise
+	// the stale data will never be cleaned up by the underlying compactor.
+	NewSnapshot() (Snapshot, error)
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package era
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/golang/snappy"
+)
+
+// Builder writes consecutive blocks into an archive.
+//
+//	b := era.NewBuilder(w)
+//	for ... {
+//		b.Add(number, hash, header, body, receipts, td)
+//	}
+//	root, err := b.Finalize()
+type Builder struct {
+	w       io.Writer
+	written int64 // Number of bytes written so far
+
+	start   *uint64
+	offsets []int64
+	hashes  []common.Hash
+	tds     []*big.Int
+}
+
+// NewBuilder creates a builder writing the archive into w.
+func NewBuilder(w io.Writer) *Builder {
+	return &Builder{w: w}
+}
+
+// write appends an entry to the archive, tracking the written size.
+func (b *Builder) write(typ uint16, data []byte) error {
+	n, err := writeEntry(b.w, typ, data)
+	b.written += int64(n)
+	return err
+}
+
+// Add appends the RLP encoded header, body and storage receipts of the block
+// with the given number and hash. Blocks have to be added in order.
+func (b *Builder) Add(number uint64, hash common.Hash, header, body, receipts rlp.RawValue, td *big.Int) error {
+	if len(b.offsets) >= MaxEraSize {
+		return errTooLarge
+	}
+	if b.start == nil {
+		if err := b.write(typeVersion, nil); err != nil {
+			return err
+		}
+		b.start = &number
+	} else if want := *b.start + uint64(len(b.offsets)); number != want {
+		return fmt.Errorf("block out of order: have %d, want %d", number, want)
+	}
+	b.offsets = append(b.offsets, b.written)
+	for _, entry := range []struct {
+		typ  uint16
+		data []byte
+	}{
+		{typeCompressedHeader, snappy.Encode(nil, header)},
+		{typeCompressedBody, snappy.Encode(nil, body)},
+		{typeCompressedReceipts, snappy.Encode(nil, receipts)},
+		{typeTotalDifficulty, td.Bytes()},
+	} {
+		if err := b.write(entry.typ, entry.data); err != nil {
+			return err
+		}
+	}
+	b.hashes = append(b.hashes, hash)
+	b.tds = append(b.tds, new(big.Int).Set(td))
+	return nil
+}
+
+// Finalize writes the accumulator and the block index, returning the
+// accumulator root.
+func (b *Builder) Finalize() (common.Hash, error) {
+	if b.start == nil {
+		return common.Hash{}, errors.New("finalizing empty archive")
+	}
+	root, err := ComputeAccumulator(b.hashes, b.tds)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := b.write(typeAccumulator, root[:]); err != nil {
+		return common.Hash{}, err
+	}
+	// The index offsets are relative to the beginning of the index entry
+	var (
+		base  = b.written
+		count = len(b.offsets)
+		index = make([]byte, 16+8*count)
+	)
+	binary.LittleEndian.PutUint64(index, *b.start)
+	for i, offset := range b.offsets {
+		binary.LittleEndian.PutUint64(index[8+8*i:], uint64(offset-base))
+	}
+	binary.LittleEndian.PutUint64(index[8+8*count:], uint64(count))
+	if err := b.write(typeBlockIndex, index); err != nil {
+		return common.Hash{}, err
+	}
+	return root, nil
+}
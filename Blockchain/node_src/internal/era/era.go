@@ -0,0 +1,342 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+// Package era implements the archive format used to export expired chain
+// history. An archive holds up to MaxEraSize consecutive blocks with their
+// receipts and total difficulties, followed by an accumulator root committing
+// to all of them, which makes every archive verifiable on its own.
+//
+// The archive is a sequence of type-length-value entries:
+//
+//	Version | (Header | Body | Receipts | TotalDifficulty)* | Accumulator | BlockIndex
+//
+// Headers, bodies and receipts are snappy compressed RLP, receipts are kept in
+// their storage encoding. The block index at the end maps block numbers to the
+// offsets of their header entries.
+package era
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/golang/snappy"
+)
+
+const (
+	// MaxEraSize is the maximum number of blocks in a single archive. Archives
+	// cover aligned epochs of this size.
+	MaxEraSize = 8192
+
+	typeVersion            uint16 = 0x3265
+	typeCompressedHeader   uint16 = 0x03
+	typeCompressedBody     uint16 = 0x04
+	typeCompressedReceipts uint16 = 0x05
+	typeTotalDifficulty    uint16 = 0x06
+	typeAccumulator        uint16 = 0x07
+	typeBlockIndex         uint16 = 0x3266
+
+	headerSize = 8 // type (2 bytes) | length (4 bytes) | reserved (2 bytes)
+)
+
+var (
+	errMalformed = errors.New("malformed archive")
+	errTooLarge  = errors.New("archive is full")
+)
+
+// Filename returns the canonical name of the archive of the given network and
+// epoch, tagged with the leading bytes of its accumulator root.
+func Filename(network string, epoch uint64, root common.Hash) string {
+	return fmt.Sprintf("%s-%05d-%x.era1", network, epoch, root[:4])
+}
+
+// ReadDir returns the archives of the given network in the directory, sorted
+// by epoch.
+func ReadDir(dir string, network string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".era1" || !strings.HasPrefix(name, network+"-") {
+			continue
+		}
+		files = append(files, filepath.Join(dir, name))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// writeEntry writes a single type-length-value entry.
+func writeEntry(w io.Writer, typ uint16, data []byte) (int, error) {
+	var header [headerSize]byte
+	binary.LittleEndian.PutUint16(header[:2], typ)
+	binary.LittleEndian.PutUint32(header[2:6], uint32(len(data)))
+	n, err := w.Write(header[:])
+	if err != nil {
+		return n, err
+	}
+	m, err := w.Write(data)
+	return n + m, err
+}
+
+// readEntry reads the entry at the given offset, returning its type, data and
+// the offset of the next entry.
+func readEntry(r io.ReaderAt, offset int64) (uint16, []byte, int64, error) {
+	var header [headerSize]byte
+	if _, err := r.ReadAt(header[:], offset); err != nil {
+		return 0, nil, 0, err
+	}
+	typ := binary.LittleEndian.Uint16(header[:2])
+	data := make([]byte, binary.LittleEndian.Uint32(header[2:6]))
+	if _, err := r.ReadAt(data, offset+headerSize); err != nil {
+		return 0, nil, 0, err
+	}
+	return typ, data, offset + headerSize + int64(len(data)), nil
+}
+
+// accumulatorLeaf is the commitment of a single block in the accumulator.
+func accumulatorLeaf(hash common.Hash, td *big.Int) common.Hash {
+	return crypto.Keccak256Hash(hash[:], common.BigToHash(td).Bytes())
+}
+
+// ComputeAccumulator returns the root of the binary merkle tree over the block
+// hashes and total difficulties, padded to MaxEraSize leaves.
+func ComputeAccumulator(hashes []common.Hash, tds []*big.Int) (common.Hash, error) {
+	if len(hashes) != len(tds) {
+		return common.Hash{}, fmt.Errorf("hash and difficulty count mismatch: %d != %d", len(hashes), len(tds))
+	}
+	if len(hashes) > MaxEraSize {
+		return common.Hash{}, errTooLarge
+	}
+	level := make([]common.Hash, MaxEraSize)
+	for i := range hashes {
+		level[i] = accumulatorLeaf(hashes[i], tds[i])
+	}
+	for len(level) > 1 {
+		next := make([]common.Hash, len(level)/2)
+		for i := range next {
+			next[i] = crypto.Keccak256Hash(level[2*i][:], level[2*i+1][:])
+		}
+		level = next
+	}
+	return level[0], nil
+}
+
+// Era is an opened archive.
+type Era struct {
+	f       *os.File
+	start   uint64  // Number of the first block in the archive
+	offsets []int64 // Offsets of the header entries of the blocks
+}
+
+// Open opens the archive at the given path and loads its block index.
+func Open(path string) (*Era, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	e, err := newEra(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return e, nil
+}
+
+func newEra(f *os.File) (*Era, error) {
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	// The index ends with the block count, which determines its length
+	var buf [8]byte
+	if stat.Size() < headerSize+16 {
+		return nil, errMalformed
+	}
+	if _, err := f.ReadAt(buf[:], stat.Size()-8); err != nil {
+		return nil, err
+	}
+	count := binary.LittleEndian.Uint64(buf[:])
+	if count == 0 || count > MaxEraSize {
+		return nil, errMalformed
+	}
+	indexOffset := stat.Size() - headerSize - int64(16+8*count)
+	if indexOffset < 0 {
+		return nil, errMalformed
+	}
+	typ, data, _, err := readEntry(f, indexOffset)
+	if err != nil {
+		return nil, err
+	}
+	if typ != typeBlockIndex {
+		return nil, errMalformed
+	}
+	e := &Era{
+		f:       f,
+		start:   binary.LittleEndian.Uint64(data[:8]),
+		offsets: make([]int64, count),
+	}
+	for i := range e.offsets {
+		rel := int64(binary.LittleEndian.Uint64(data[8+8*i:]))
+		e.offsets[i] = indexOffset + rel
+		if e.offsets[i] < 0 || e.offsets[i] >= indexOffset {
+			return nil, errMalformed
+		}
+	}
+	return e, nil
+}
+
+// Close closes the archive file.
+func (e *Era) Close() error {
+	return e.f.Close()
+}
+
+// Start returns the number of the first block in the archive.
+func (e *Era) Start() uint64 {
+	return e.start
+}
+
+// Count returns the number of blocks in the archive.
+func (e *Era) Count() uint64 {
+	return uint64(len(e.offsets))
+}
+
+// GetRawBlock returns the RLP encoded header, body and storage receipts of the
+// block with the given number along with its total difficulty.
+func (e *Era) GetRawBlock(number uint64) (header, body, receipts rlp.RawValue, td *big.Int, err error) {
+	if number < e.start || number-e.start >= uint64(len(e.offsets)) {
+		return nil, nil, nil, nil, fmt.Errorf("block %d not in archive [%d, %d)", number, e.start, e.start+e.Count())
+	}
+	offset := e.offsets[number-e.start]
+	var blobs [3][]byte
+	for i, want := range []uint16{typeCompressedHeader, typeCompressedBody, typeCompressedReceipts} {
+		typ, data, next, err := readEntry(e.f, offset)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		if typ != want {
+			return nil, nil, nil, nil, fmt.Errorf("%w: entry type %#x, want %#x", errMalformed, typ, want)
+		}
+		if blobs[i], err = snappy.Decode(nil, data); err != nil {
+			return nil, nil, nil, nil, err
+		}
+		offset = next
+	}
+	typ, data, _, err := readEntry(e.f, offset)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if typ != typeTotalDifficulty {
+		return nil, nil, nil, nil, fmt.Errorf("%w: entry type %#x, want %#x", errMalformed, typ, typeTotalDifficulty)
+	}
+	return blobs[0], blobs[1], blobs[2], new(big.Int).SetBytes(data), nil
+}
+
+// Accumulator returns the accumulator root stored in the archive.
+func (e *Era) Accumulator() (common.Hash, error) {
+	last := e.offsets[len(e.offsets)-1]
+	for i := 0; i < 5; i++ {
+		typ, data, next, err := readEntry(e.f, last)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		if typ == typeAccumulator {
+			return common.BytesToHash(data), nil
+		}
+		last = next
+	}
+	return common.Hash{}, fmt.Errorf("%w: accumulator missing", errMalformed)
+}
+
+// Verify checks the integrity of the archive: every body and receipt list has
+// to match its header, the headers have to form a chain and the accumulator
+// root recomputed from the blocks has to match the stored one. The root is
+// returned on success.
+func (e *Era) Verify() (common.Hash, error) {
+	var (
+		hashes = make([]common.Hash, 0, len(e.offsets))
+		tds    = make([]*big.Int, 0, len(e.offsets))
+		parent common.Hash
+	)
+	for i := uint64(0); i < e.Count(); i++ {
+		number := e.start + i
+		header, _, _, td, err := e.GetBlock(number)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("block %d: %w", number, err)
+		}
+		if i > 0 && header.ParentHash != parent {
+			return common.Hash{}, fmt.Errorf("block %d: parent hash mismatch: have %x, want %x", number, header.ParentHash, parent)
+		}
+		parent = header.Hash()
+		hashes = append(hashes, parent)
+		tds = append(tds, td)
+	}
+	root, err := ComputeAccumulator(hashes, tds)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	stored, err := e.Accumulator()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if root != stored {
+		return common.Hash{}, fmt.Errorf("accumulator mismatch: have %x, want %x", root, stored)
+	}
+	return root, nil
+}
+
+// GetBlock returns the decoded header, body and receipts of the block with the
+// given number along with its total difficulty. The body and the receipts are
+// checked against the roots in the header.
+func (e *Era) GetBlock(number uint64) (*types.Header, *types.Body, []*types.ReceiptForStorage, *big.Int, error) {
+	rawHeader, rawBody, rawReceipts, td, err := e.GetRawBlock(number)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	header := new(types.Header)
+	if err := rlp.DecodeBytes(rawHeader, header); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if header.Number.Uint64() != number {
+		return nil, nil, nil, nil, fmt.Errorf("number mismatch: have %d, want %d", header.Number, number)
+	}
+	body := new(types.Body)
+	if err := rlp.DecodeBytes(rawBody, body); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if hash := types.DeriveSha(types.Transactions(body.Transactions), trie.NewStackTrie(nil)); hash != header.TxHash {
+		return nil, nil, nil, nil, fmt.Errorf("transaction root mismatch: have %x, want %x", hash, header.TxHash)
+	}
+	if hash := types.CalcUncleHash(body.Uncles); hash != header.UncleHash {
+		return nil, nil, nil, nil, fmt.Errorf("uncle hash mismatch: have %x, want %x", hash, header.UncleHash)
+	}
+	var stored []*types.ReceiptForStorage
+	if err := rlp.DecodeBytes(rawReceipts, &stored); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if len(stored) != len(body.Transactions) {
+		return nil, nil, nil, nil, fmt.Errorf("receipt count mismatch: have %d, want %d", len(stored), len(body.Transactions))
+	}
+	receipts := make(types.Receipts, len(stored))
+	for i, receipt := range stored {
+		receipts[i] = (*types.Receipt)(receipt)
+		receipts[i].Type = body.Transactions[i].Type()
+	}
+	if hash := types.DeriveSha(receipts, trie.NewStackTrie(nil)); hash != header.ReceiptHash {
+		return nil, nil, nil, nil, fmt.Errorf("receipt root mismatch: have %x, want %x", hash, header.ReceiptHash)
+	}
+	return header, body, stored, td, nil
+}
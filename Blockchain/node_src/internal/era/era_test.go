@@ -0,0 +1,173 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package era
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// makeChain creates a chain of blocks with a transaction and receipt each.
+func makeChain(n int) ([]*types.Block, []types.Receipts) {
+	var (
+		blocks   []*types.Block
+		receipts []types.Receipts
+		parent   common.Hash
+	)
+	for i := 0; i < n; i++ {
+		tx := types.NewTransaction(uint64(i), common.Address{0x01}, big.NewInt(1), 21000, big.NewInt(1), nil)
+		receipt := &types.Receipt{
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: 21000,
+			Logs:              []*types.Log{{Address: common.Address{0x02}, Data: []byte{byte(i)}}},
+		}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+		header := &types.Header{
+			ParentHash: parent,
+			Number:     big.NewInt(int64(i)),
+			Difficulty: big.NewInt(2),
+			GasLimit:   8000000,
+		}
+		block := types.NewBlock(header, []*types.Transaction{tx}, nil, []*types.Receipt{receipt}, trie.NewStackTrie(nil))
+		blocks = append(blocks, block)
+		receipts = append(receipts, types.Receipts{receipt})
+		parent = block.Hash()
+	}
+	return blocks, receipts
+}
+
+// writeArchive writes the given blocks into an archive file.
+func writeArchive(t *testing.T, path string, blocks []*types.Block, receipts []types.Receipts) common.Hash {
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	builder := NewBuilder(f)
+	for i, block := range blocks {
+		header, _ := rlp.EncodeToBytes(block.Header())
+		body, _ := rlp.EncodeToBytes(block.Body())
+		stored := make([]*types.ReceiptForStorage, len(receipts[i]))
+		for j, receipt := range receipts[i] {
+			stored[j] = (*types.ReceiptForStorage)(receipt)
+		}
+		enc, _ := rlp.EncodeToBytes(stored)
+		td := big.NewInt(int64(2 * (i + 1)))
+		if err := builder.Add(block.NumberU64(), block.Hash(), header, body, enc, td); err != nil {
+			t.Fatalf("failed to add block %d: %v", i, err)
+		}
+	}
+	root, err := builder.Finalize()
+	if err != nil {
+		t.Fatalf("failed to finalize: %v", err)
+	}
+	return root
+}
+
+// Tests that archives can be written, read back and verified.
+func TestArchiveRoundtrip(t *testing.T) {
+	var (
+		blocks, receipts = makeChain(16)
+		path             = filepath.Join(t.TempDir(), "test.era1")
+		root             = writeArchive(t, path, blocks, receipts)
+	)
+	archive, err := Open(path)
+	if err != nil {
+		t.Fatalf("failed to open archive: %v", err)
+	}
+	defer archive.Close()
+
+	if archive.Start() != 0 || archive.Count() != 16 {
+		t.Fatalf("range mismatch: have [%d, +%d), want [0, +16)", archive.Start(), archive.Count())
+	}
+	verified, err := archive.Verify()
+	if err != nil {
+		t.Fatalf("failed to verify archive: %v", err)
+	}
+	if verified != root {
+		t.Fatalf("accumulator mismatch: have %x, want %x", verified, root)
+	}
+	for i, block := range blocks {
+		header, body, stored, td, err := archive.GetBlock(uint64(i))
+		if err != nil {
+			t.Fatalf("failed to read block %d: %v", i, err)
+		}
+		if header.Hash() != block.Hash() {
+			t.Fatalf("block %d: hash mismatch", i)
+		}
+		if len(body.Transactions) != 1 || body.Transactions[0].Hash() != block.Transactions()[0].Hash() {
+			t.Fatalf("block %d: body mismatch", i)
+		}
+		if len(stored) != 1 || stored[0].Logs[0].Data[0] != byte(i) {
+			t.Fatalf("block %d: receipts mismatch", i)
+		}
+		if td.Int64() != int64(2*(i+1)) {
+			t.Fatalf("block %d: total difficulty mismatch: have %v", i, td)
+		}
+	}
+	if _, _, _, _, err := archive.GetBlock(16); err == nil {
+		t.Fatalf("read block beyond the archive")
+	}
+}
+
+// Tests that tampered archives fail the verification.
+func TestArchiveTampered(t *testing.T) {
+	blocks, receipts := makeChain(4)
+
+	// Swap the receipts of two blocks, each entry is consistent on its own but
+	// not with the header.
+	path := filepath.Join(t.TempDir(), "receipts.era1")
+	receipts[1], receipts[2] = receipts[2], receipts[1]
+	writeArchive(t, path, blocks, receipts)
+
+	archive, err := Open(path)
+	if err != nil {
+		t.Fatalf("failed to open archive: %v", err)
+	}
+	if _, err := archive.Verify(); err == nil {
+		t.Fatalf("archive with mismatching receipts verified")
+	}
+	archive.Close()
+
+	// Drop a block from the middle, breaking the chain
+	receipts[1], receipts[2] = receipts[2], receipts[1]
+	path = filepath.Join(t.TempDir(), "gap.era1")
+	f, _ := os.Create(path)
+	builder := NewBuilder(f)
+	header, _ := rlp.EncodeToBytes(blocks[0].Header())
+	body, _ := rlp.EncodeToBytes(blocks[0].Body())
+	if err := builder.Add(0, blocks[0].Hash(), header, body, []byte{0xc0}, big.NewInt(1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := builder.Add(2, blocks[2].Hash(), header, body, []byte{0xc0}, big.NewInt(1)); err == nil {
+		t.Fatalf("added block out of order")
+	}
+	f.Close()
+}
+
+// Tests the accumulator of an archive commits to the total difficulties.
+func TestAccumulator(t *testing.T) {
+	hashes := []common.Hash{{0x01}, {0x02}}
+	a, err := ComputeAccumulator(hashes, []*big.Int{big.NewInt(1), big.NewInt(2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ComputeAccumulator(hashes, []*big.Int{big.NewInt(1), big.NewInt(3)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == b {
+		t.Fatalf("accumulator doesn't commit to the total difficulty")
+	}
+	if _, err := ComputeAccumulator(make([]common.Hash, MaxEraSize+1), make([]*big.Int, MaxEraSize+1)); err == nil {
+		t.Fatalf("oversized accumulator computed")
+	}
+}
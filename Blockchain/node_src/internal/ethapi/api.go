@@ -288,6 +288,16 @@ func (s *PublicTxPoolAPI) JamIndex() int {
 	return s.b.JamIndex()
 }
 
+// Denied returns the transactions the pool recently rejected because they
+// involve a blacklisted address, along with the reasons, the most recent first.
+func (s *PublicTxPoolAPI) Denied() []*core.DeniedTx {
+	denied := s.b.TxPoolDenied()
+	if denied == nil {
+		denied = []*core.DeniedTx{}
+	}
+	return denied
+}
+
 // PublicAccountAPI provides an API to access accounts managed by this node.
 // It offers only methods that can retrieve accounts.
 type PublicAccountAPI struct {
@@ -1036,6 +1046,9 @@ func DoCall(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash
 	if evm.Cancelled() {
 		return nil, fmt.Errorf("execution aborted (timeout = %v)", timeout)
 	}
+	if errors.Is(err, types.ErrDeveloperNotAllowed) {
+		return result, &developerNotAllowedError{error: err, developer: msg.From()}
+	}
 	if err != nil {
 		return result, fmt.Errorf("err: %w (supplied gas %d)", err, msg.Gas())
 	}
@@ -1072,6 +1085,28 @@ func (e *revertError) ErrorData() interface{} {
 	return e.reason
 }
 
+// developerNotAllowedError is an API error reporting a contract creation by an
+// address which isn't an allowed developer.
+type developerNotAllowedError struct {
+	error
+	developer common.Address
+}
+
+// Unwrap returns the underlying consensus error.
+func (e *developerNotAllowedError) Unwrap() error {
+	return e.error
+}
+
+// ErrorCode returns the JSON error code of a rejected transaction.
+func (e *developerNotAllowedError) ErrorCode() int {
+	return -32003
+}
+
+// ErrorData returns the address which isn't allowed to create contracts.
+func (e *developerNotAllowedError) ErrorData() interface{} {
+	return e.developer
+}
+
 // Call executes the given transaction on the state for the given block number.
 // Additionally, the caller can specify a batch of contract for fields overriding.
 // Note, this function doesn't make and changes in the state/blockchain and is
@@ -1794,16 +1829,25 @@ func (s *PublicTransactionPoolAPI) sign(addr common.Address, tx *types.Transacti
 	return wallet.SignTx(account, tx, s.b.ChainConfig().ChainID)
 }
 
-// SubmitTransaction is a helper function that submits tx to txPool and logs a message.
-func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (common.Hash, error) {
+// CheckSubmittable runs the RPC level sanity checks (fee cap and replay
+// protection) a transaction needs to pass before it's handed to the pool.
+func CheckSubmittable(b Backend, tx *types.Transaction) error {
 	// If the transaction fee cap is already specified, ensure the
 	// fee of the given transaction is _reasonable_.
 	if err := checkTxFee(tx.GasPrice(), tx.Gas(), b.RPCTxFeeCap()); err != nil {
-		return common.Hash{}, err
+		return err
 	}
 	if !b.UnprotectedAllowed() && !tx.Protected() {
 		// Ensure only eip155 signed transactions are submitted if EIP155Required is set.
-		return common.Hash{}, errors.New("only replay-protected (EIP-155) transactions allowed over RPC")
+		return errors.New("only replay-protected (EIP-155) transactions allowed over RPC")
+	}
+	return nil
+}
+
+// SubmitTransaction is a helper function that submits tx to txPool and logs a message.
+func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (common.Hash, error) {
+	if err := CheckSubmittable(b, tx); err != nil {
+		return common.Hash{}, err
 	}
 	if err := b.SendTx(ctx, tx); err != nil {
 		return common.Hash{}, err
@@ -70,6 +70,7 @@ type Backend interface {
 	TxPoolContentFrom(addr common.Address) (types.Transactions, types.Transactions)
 	SubscribeNewTxsEvent(chan<- core.NewTxsEvent) event.Subscription
 	JamIndex() int
+	TxPoolDenied() []*core.DeniedTx
 
 	// Filter API
 	BloomStatus() (uint64, uint64)
@@ -226,6 +226,10 @@ func (b *LesApiBackend) JamIndex() int {
 	return 0 // not implement
 }
 
+func (b *LesApiBackend) TxPoolDenied() []*core.DeniedTx {
+	return nil // light clients don't screen transactions
+}
+
 func (b *LesApiBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
 	return b.eth.txPool.SubscribeNewTxsEvent(ch)
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/mclock"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/congress"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -128,6 +129,11 @@ func New(stack *node.Node, config *ethconfig.Config) (*LightEthereum, error) {
 		return nil, err
 	}
 	leth.chainReader = leth.blockchain
+	// Congress verifies headers from the epoch checkpoints alone, but needs the
+	// chain to look up parent headers when validating calls against blacklists.
+	if congressEngine, ok := leth.engine.(*congress.Congress); ok {
+		congressEngine.SetChain(leth.blockchain.HeaderChain())
+	}
 	leth.txPool = light.NewTxPool(leth.chainConfig, leth.blockchain, leth.relay)
 
 	// Set up checkpoint oracle.
@@ -7,6 +7,7 @@ package light
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
 	"sync"
 	"sync/atomic"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/congress"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
@@ -29,6 +31,10 @@ import (
 var (
 	bodyCacheLimit  = 256
 	blockCacheLimit = 256
+
+	// errNoCheckpoint is returned if no congress checkpoint header is found
+	// within the longest epoch before the one to sync to.
+	errNoCheckpoint = errors.New("no congress checkpoint")
 )
 
 // LightChain represents a canonical chain that by default only handles block
@@ -474,21 +480,26 @@ func (lc *LightChain) GetHeaderByNumberOdr(ctx context.Context, number uint64) (
 func (lc *LightChain) Config() *params.ChainConfig { return lc.hc.Config() }
 
 // SyncCheckpoint fetches the checkpoint point block header according to
-// Note if we are running the clique, fetches the last epoch snapshot header
-// which covered by checkpoint.
+// Note if we are running the clique or congress, fetches the last epoch
+// snapshot header which covered by checkpoint, since the validator set can
+// only be bootstrapped from there.
 func (lc *LightChain) SyncCheckpoint(ctx context.Context, checkpoint *params.TrustedCheckpoint) bool {
 	// Ensure the remote checkpoint head is ahead of us
 	head := lc.CurrentHeader().Number.Uint64()
 
 	latest := (checkpoint.SectionIndex+1)*lc.indexerConfig.ChtSize - 1
-	if clique := lc.hc.Config().Clique; clique != nil {
-		latest -= latest % clique.Epoch // epoch snapshot for clique
+	config := lc.hc.Config()
+	if config.Clique != nil {
+		latest -= latest % config.Clique.Epoch // epoch snapshot for clique
+	}
+	if config.Congress != nil && config.Congress.Epoch != 0 && !config.IsAgora(new(big.Int).SetUint64(latest)) {
+		latest -= latest % config.Congress.Epoch // epoch snapshot for congress
 	}
 	if head >= latest {
 		return true
 	}
 	// Retrieve the latest useful header and update to it
-	if header, err := GetHeaderByNumber(ctx, lc.odr, latest); header != nil && err == nil {
+	if header, err := lc.checkpointHeader(ctx, latest); header != nil && err == nil {
 		lc.chainmu.Lock()
 		defer lc.chainmu.Unlock()
 
@@ -503,6 +514,26 @@ func (lc *LightChain) SyncCheckpoint(ctx context.Context, checkpoint *params.Tru
 	return false
 }
 
+// checkpointHeader retrieves the header to sync to, the one with the given
+// number unless the chain is a congress one past Agora. As governance may change
+// the epoch length since then, the congress checkpoints are told apart by their
+// validator list and the headers are walked back to the closest one, at most
+// the longest epoch governance allows away.
+func (lc *LightChain) checkpointHeader(ctx context.Context, number uint64) (*types.Header, error) {
+	config := lc.hc.Config()
+	if config.Congress == nil {
+		return GetHeaderByNumber(ctx, lc.odr, number)
+	}
+	limit := congress.MaxEpochLength(config.Congress)
+	for n := number; number-n < limit; n-- {
+		header, err := GetHeaderByNumber(ctx, lc.odr, n)
+		if err != nil || header == nil || !config.IsAgora(header.Number) || n == 0 || congress.HasValidators(header) {
+			return header, err
+		}
+	}
+	return nil, fmt.Errorf("%w: none within %d blocks of #%d", errNoCheckpoint, limit, number)
+}
+
 // LockChain locks the chain mutex for reading so that multiple canonical hashes can be
 // retrieved while it is guaranteed that they belong to the same version of the chain
 func (lc *LightChain) LockChain() {
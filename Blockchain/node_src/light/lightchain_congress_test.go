@@ -0,0 +1,248 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package light
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/congress"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// congressOdr serves the headers of a server chain as if they were proven
+// against a trusted CHT.
+type congressOdr struct {
+	OdrBackend
+	sdb, ldb      ethdb.Database
+	indexerConfig *IndexerConfig
+	chtIndexer    *core.ChainIndexer
+}
+
+func (odr *congressOdr) Database() ethdb.Database             { return odr.ldb }
+func (odr *congressOdr) IndexerConfig() *IndexerConfig        { return odr.indexerConfig }
+func (odr *congressOdr) ChtIndexer() *core.ChainIndexer       { return odr.chtIndexer }
+func (odr *congressOdr) BloomTrieIndexer() *core.ChainIndexer { return nil }
+func (odr *congressOdr) BloomIndexer() *core.ChainIndexer     { return nil }
+
+func (odr *congressOdr) Retrieve(ctx context.Context, req OdrRequest) error {
+	if req, ok := req.(*ChtRequest); ok {
+		hash := rawdb.ReadCanonicalHash(odr.sdb, req.BlockNum)
+		req.Header = rawdb.ReadHeader(odr.sdb, hash, req.BlockNum)
+		req.Td = rawdb.ReadTd(odr.sdb, hash, req.BlockNum)
+	}
+	req.StoreResult(odr.ldb)
+	return nil
+}
+
+// makeCongressHeaders creates a chain of headers sealed by the given validators,
+// with the validator set for each epoch returned by validators.
+func makeCongressHeaders(config *params.ChainConfig, parent *types.Header, n int, keys map[common.Address]*ecdsa.PrivateKey, validators func(epoch uint64) []common.Address) []*types.Header {
+	var (
+		epoch   = config.Congress.Epoch
+		headers []*types.Header
+		signers = make(map[uint64]common.Address)
+	)
+	for i := 0; i < n; i++ {
+		number := parent.Number.Uint64() + 1
+		active := validators((number - 1) / epoch * epoch)
+
+		// Prefer the in-turn validator, falling back to the first one which
+		// didn't sign recently
+		recent := func(addr common.Address) bool {
+			for seen := number - uint64(len(active)/2); seen < number; seen++ {
+				if signers[seen] == addr {
+					return true
+				}
+			}
+			return false
+		}
+		difficulty, signer := big.NewInt(2), active[number%uint64(len(active))]
+		if recent(signer) {
+			for _, addr := range active {
+				if !recent(addr) {
+					difficulty, signer = big.NewInt(1), addr
+					break
+				}
+			}
+		}
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			UncleHash:  types.EmptyUncleHash,
+			Coinbase:   signer,
+			Difficulty: difficulty,
+			Number:     new(big.Int).SetUint64(number),
+			GasLimit:   parent.GasLimit,
+			Time:       parent.Time + 1,
+			BaseFee:    misc.CalcBaseFee(config, parent),
+			Extra:      make([]byte, 32),
+		}
+		if number%epoch == 0 {
+			for _, addr := range validators(number) {
+				header.Extra = append(header.Extra, addr.Bytes()...)
+			}
+		}
+		header.Extra = append(header.Extra, make([]byte, crypto.SignatureLength)...)
+		sig, _ := crypto.Sign(congress.SealHash(header).Bytes(), keys[signer])
+		copy(header.Extra[len(header.Extra)-crypto.SignatureLength:], sig)
+
+		signers[number] = signer
+		headers = append(headers, header)
+		parent = header
+	}
+	return headers
+}
+
+// Tests that light clients can follow a congress chain both from the genesis
+// and from a trusted checkpoint, tracking the validator set changes.
+func TestCongressHeaderSync(t *testing.T) {
+	keys := make(map[common.Address]*ecdsa.PrivateKey)
+	var addrs []common.Address
+	for i := 0; i < 4; i++ {
+		key, _ := crypto.GenerateKey()
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		keys[addr] = key
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool { return bytes.Compare(addrs[i][:], addrs[j][:]) < 0 })
+
+	// The last validator joins at the third epoch
+	validators := func(epoch uint64) []common.Address {
+		if epoch < 8 {
+			return addrs[:3]
+		}
+		return addrs
+	}
+	config := *params.AllCongressProtocolChanges
+	config.RedCoastBlock, config.SophonBlock = nil, nil
+	config.Congress = &params.CongressConfig{Epoch: 4}
+
+	extra := make([]byte, 32)
+	for _, addr := range validators(0) {
+		extra = append(extra, addr.Bytes()...)
+	}
+	gspec := &core.Genesis{
+		Config:    &config,
+		ExtraData: append(extra, make([]byte, crypto.SignatureLength)...),
+		GasLimit:  params.GenesisGasLimit,
+		BaseFee:   big.NewInt(params.InitialBaseFee),
+	}
+	// Sync a light chain from the genesis, acting as the server
+	sdb := rawdb.NewMemoryDatabase()
+	genesis := gspec.MustCommit(sdb)
+	headers := makeCongressHeaders(&config, genesis.Header(), 24, keys, validators)
+
+	server, err := NewLightChain(&dummyOdr{db: sdb, indexerConfig: TestClientIndexerConfig}, &config, congress.New(&config, sdb), nil)
+	if err != nil {
+		t.Fatalf("failed to create server chain: %v", err)
+	}
+	if _, err := server.InsertHeaderChain(headers, 1); err != nil {
+		t.Fatalf("failed to sync from genesis: %v", err)
+	}
+	// A header sealed by the last validator before it joined must be rejected
+	forged := makeCongressHeaders(&config, headers[4], 1, keys, func(uint64) []common.Address { return addrs[3:] })
+	if _, err := server.InsertHeaderChain(forged, 1); err == nil {
+		t.Fatalf("header sealed by an unauthorized validator accepted")
+	}
+	// Sync a light chain from a checkpoint which doesn't end at an epoch
+	ldb := rawdb.NewMemoryDatabase()
+	gspec.MustCommit(ldb)
+
+	indexerConfig := &IndexerConfig{ChtSize: 14, ChtConfirms: 1}
+	odr := &congressOdr{sdb: sdb, ldb: ldb, indexerConfig: indexerConfig}
+	odr.chtIndexer = NewChtIndexer(ldb, odr, indexerConfig.ChtSize, indexerConfig.ChtConfirms, true)
+	defer odr.chtIndexer.Close()
+
+	checkpoint := &params.TrustedCheckpoint{SectionHead: headers[12].Hash()}
+	engine := congress.New(&config, ldb)
+	client, err := NewLightChain(odr, &config, engine, checkpoint)
+	if err != nil {
+		t.Fatalf("failed to create client chain: %v", err)
+	}
+	if !client.SyncCheckpoint(context.Background(), checkpoint) {
+		t.Fatalf("failed to sync checkpoint")
+	}
+	if head := client.CurrentHeader(); head.Hash() != headers[11].Hash() {
+		t.Fatalf("checkpoint head mismatch: have #%d, want epoch #12", head.Number)
+	}
+	// Since Agora the epoch length may have been changed by governance, so the
+	// checkpoint is found by its validator list instead of the configured epoch
+	agora := config
+	agora.AgoraBlock, agora.Congress = big.NewInt(0), &params.CongressConfig{Epoch: 5}
+	adb := rawdb.NewMemoryDatabase()
+	gspec.MustCommit(adb)
+	aodr := &congressOdr{sdb: sdb, ldb: adb, indexerConfig: indexerConfig}
+	aodr.chtIndexer = NewChtIndexer(adb, aodr, indexerConfig.ChtSize, indexerConfig.ChtConfirms, true)
+	defer aodr.chtIndexer.Close()
+
+	agoraClient, err := NewLightChain(aodr, &agora, congress.New(&agora, adb), checkpoint)
+	if err != nil {
+		t.Fatalf("failed to create agora client chain: %v", err)
+	}
+	if !agoraClient.SyncCheckpoint(context.Background(), checkpoint) {
+		t.Fatalf("failed to sync agora checkpoint")
+	}
+	if head := agoraClient.CurrentHeader(); head.Hash() != headers[11].Hash() {
+		t.Fatalf("agora checkpoint head mismatch: have #%d, want epoch #12", head.Number)
+	}
+	if _, err := client.InsertHeaderChain(headers[12:], 1); err != nil {
+		t.Fatalf("failed to sync from checkpoint: %v", err)
+	}
+	if head := client.CurrentHeader(); head.Hash() != headers[len(headers)-1].Hash() {
+		t.Fatalf("head mismatch: have #%d, want #%d", head.Number, len(headers))
+	}
+	api := engine.APIs(client.HeaderChain())[0].Service.(*congress.API)
+	latest := rpc.LatestBlockNumber
+	if have, err := api.GetValidators(&latest); err != nil || len(have) != len(addrs) {
+		t.Fatalf("validator set mismatch: have %v, want %v (err %v)", have, addrs, err)
+	}
+}
+
+// Tests that light clients only walk back the longest epoch governance allows
+// to find the congress checkpoint to sync to, instead of the whole chain.
+func TestCongressCheckpointWalkLimit(t *testing.T) {
+	config := *params.AllCongressProtocolChanges
+	config.RedCoastBlock, config.SophonBlock = nil, nil
+	config.Congress = &params.CongressConfig{Epoch: 4}
+
+	db := rawdb.NewMemoryDatabase()
+	genesis := (&core.Genesis{Config: &config, ExtraData: make([]byte, 32+common.AddressLength+crypto.SignatureLength)}).MustCommit(db)
+	config.AgoraBlock = big.NewInt(0)
+
+	// Write headers without any validator list, as if no checkpoint was sealed
+	limit := congress.MaxEpochLength(config.Congress)
+	parent := genesis.Header()
+	for i := uint64(0); i < limit; i++ {
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     new(big.Int).Add(parent.Number, common.Big1),
+			Extra:      make([]byte, 32+crypto.SignatureLength),
+		}
+		rawdb.WriteHeader(db, header)
+		rawdb.WriteCanonicalHash(db, header.Hash(), header.Number.Uint64())
+		parent = header
+	}
+	lc, err := NewLightChain(&dummyOdr{db: db, indexerConfig: TestClientIndexerConfig}, &config, congress.New(&config, db), nil)
+	if err != nil {
+		t.Fatalf("failed to create light chain: %v", err)
+	}
+	if header, err := lc.checkpointHeader(context.Background(), limit-1); err != nil || header.Hash() != genesis.Hash() {
+		t.Errorf("checkpoint mismatch: have %v, want genesis (err %v)", header, err)
+	}
+	if _, err := lc.checkpointHeader(context.Background(), limit); !errors.Is(err, errNoCheckpoint) {
+		t.Errorf("error mismatch: have %v, want %v", err, errNoCheckpoint)
+	}
+}
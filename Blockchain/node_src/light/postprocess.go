@@ -203,7 +203,7 @@ func (c *ChtIndexerBackend) Process(ctx context.Context, header *types.Header) e
 
 // Commit implements core.ChainIndexerBackend
 func (c *ChtIndexerBackend) Commit() error {
-	root, _, err := c.trie.Commit(nil)
+	root, _, _, err := c.trie.Commit(nil)
 	if err != nil {
 		return err
 	}
@@ -438,7 +438,7 @@ func (b *BloomTrieIndexerBackend) Commit() error {
 			b.trie.Delete(encKey[:])
 		}
 	}
-	root, _, err := b.trie.Commit(nil)
+	root, _, _, err := b.trie.Commit(nil)
 	if err != nil {
 		return err
 	}
@@ -5,6 +5,7 @@ package miner
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"math/big"
 	"strconv"
 	"sync"
@@ -776,6 +777,86 @@ func (w *worker) commitTransaction(tx *types.Transaction, coinbase common.Addres
 	return receipt.Logs, nil
 }
 
+// commitBundle applies all transactions of a bundle on top of the current
+// environment. Either every transaction is included, or none of them.
+func (w *worker) commitBundle(bundle *core.Bundle, coinbase common.Address) ([]*types.Log, error) {
+	// Check the replay protection and nonces upfront, skipping the dry run of
+	// bundles which can't be applied anyway
+	nonces := make(map[common.Address]uint64)
+	for _, tx := range bundle.Txs {
+		if tx.Protected() && !w.chainConfig.IsEIP155(w.current.header.Number) {
+			return nil, fmt.Errorf("replay protected bundle tx %v before EIP155", tx.Hash().Hex())
+		}
+		from, _ := types.Sender(w.current.signer, tx)
+		nonce, ok := nonces[from]
+		if !ok {
+			nonce = w.current.state.GetNonce(from)
+		}
+		if tx.Nonce() != nonce {
+			return nil, fmt.Errorf("bundle tx %v nonce mismatch: have %d, want %d", tx.Hash().Hex(), tx.Nonce(), nonce)
+		}
+		nonces[from] = nonce + 1
+	}
+	if w.isPoSA {
+		if err := core.ValidateBundle(w.posa, w.current.signer, bundle.Txs, w.current.header, w.current.state); err != nil {
+			return nil, err
+		}
+	}
+	receipts, err := core.ApplyBundle(w.chainConfig, w.chain, &coinbase, w.current.gasPool, w.current.state, w.current.header, bundle.Txs, w.current.tcount, &w.current.header.GasUsed, *w.chain.GetVMConfig(), w.current.extraValidator)
+	if err != nil {
+		return nil, err
+	}
+	w.current.txs = append(w.current.txs, bundle.Txs...)
+	w.current.receipts = append(w.current.receipts, receipts...)
+	w.current.tcount += len(bundle.Txs)
+
+	var logs []*types.Log
+	for _, receipt := range receipts {
+		logs = append(logs, receipt.Logs...)
+	}
+	return logs, nil
+}
+
+// commitBundles includes the given bundles in order, skipping every bundle which
+// doesn't fit into the block or has any failing transaction. The return value
+// has the same meaning as the one of commitTransactions.
+func (w *worker) commitBundles(bundles []*core.Bundle, coinbase common.Address, interrupt *int32) bool {
+	// Short circuit if current is nil
+	if w.current == nil {
+		return true
+	}
+	if w.current.gasPool == nil {
+		w.current.gasPool = new(core.GasPool).AddGas(w.current.header.GasLimit)
+	}
+	var coalescedLogs []*types.Log
+
+	for _, bundle := range bundles {
+		if interrupt != nil && atomic.LoadInt32(interrupt) != commitInterruptNone {
+			return atomic.LoadInt32(interrupt) == commitInterruptNewHead
+		}
+		if w.current.gasPool.Gas() < bundle.Gas() {
+			log.Trace("Not enough gas for bundle", "hash", bundle.Hash(), "have", w.current.gasPool, "want", bundle.Gas())
+			continue
+		}
+		logs, err := w.commitBundle(bundle, coinbase)
+		if err != nil {
+			log.Debug("Bundle failed, skipped", "hash", bundle.Hash(), "err", err)
+			continue
+		}
+		log.Debug("Committed bundle", "hash", bundle.Hash(), "txs", len(bundle.Txs))
+		coalescedLogs = append(coalescedLogs, logs...)
+	}
+	if !w.isRunning() && len(coalescedLogs) > 0 {
+		cpy := make([]*types.Log, len(coalescedLogs))
+		for i, l := range coalescedLogs {
+			cpy[i] = new(types.Log)
+			*cpy[i] = *l
+		}
+		w.pendingLogsFeed.Send(cpy)
+	}
+	return false
+}
+
 func (w *worker) commitTransactions(txs *types.TransactionsByPriceAndNonce, coinbase common.Address, interrupt *int32) bool {
 	// Short circuit if current is nil
 	if w.current == nil {
@@ -999,12 +1080,20 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64)
 		w.commit(uncles, nil, false, tstart)
 	}
 
+	// Fill the block with the bundles targeting it first, all-or-nothing each.
+	bundles := w.eth.TxPool().Bundles(header.Number.Uint64())
+	if len(bundles) > 0 {
+		if w.commitBundles(bundles, w.coinbase, interrupt) {
+			w.current.state.StopPrefetcher()
+			return
+		}
+	}
 	// Fill the block with all available pending transactions.
 	pending := w.eth.TxPool().Pending(true)
 	// Short circuit if there is no available pending transactions.
 	// But if we disable empty precommit already, ignore it. Since
 	// empty block is necessary to keep the liveness of the network.
-	if len(pending) == 0 && atomic.LoadUint32(&w.noempty) == 0 {
+	if len(pending) == 0 && len(bundles) == 0 && atomic.LoadUint32(&w.noempty) == 0 {
 		w.updateSnapshot()
 		return
 	}
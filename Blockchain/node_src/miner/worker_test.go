@@ -507,3 +507,69 @@ func testAdjustInterval(t *testing.T, chainConfig *params.ChainConfig, engine co
 		t.Error("interval reset timeout")
 	}
 }
+
+// Tests that the worker includes bundles ahead of the pending transactions,
+// either with all of their transactions in order or not at all.
+func TestBundleInclusion(t *testing.T) {
+	engine := ethash.NewFaker()
+	defer engine.Close()
+
+	w, b := newTestWorker(t, ethashChainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+	defer w.close()
+
+	signer := types.LatestSigner(ethashChainConfig)
+	transfer := func(nonce uint64, value *big.Int) *types.Transaction {
+		return types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{
+			Nonce:    nonce,
+			To:       &testUserAddress,
+			Value:    value,
+			Gas:      params.TxGas,
+			GasPrice: big.NewInt(params.InitialBaseFee),
+		})
+	}
+	// The first bundle is valid, the second one fails on its last transaction
+	// and the third one has a nonce gap
+	included := types.Transactions{transfer(0, big.NewInt(1)), transfer(1, big.NewInt(2))}
+	bundles := []*core.Bundle{
+		{Txs: included, MinBlock: 1, MaxBlock: 1},
+		{Txs: types.Transactions{transfer(2, big.NewInt(4)), transfer(3, testBankFunds)}, MinBlock: 1, MaxBlock: 1},
+		{Txs: types.Transactions{transfer(2, big.NewInt(8)), transfer(4, big.NewInt(16))}, MinBlock: 1, MaxBlock: 1},
+	}
+	for i, bundle := range bundles {
+		if _, err := b.txPool.AddBundle(bundle); err != nil {
+			t.Fatalf("bundle %d: failed to add: %v", i, err)
+		}
+	}
+	taskCh := make(chan *task, 1)
+	w.newTaskHook = func(task *task) {
+		if task.block.NumberU64() == 1 && len(task.receipts) > 0 {
+			select {
+			case taskCh <- task:
+			default:
+			}
+		}
+	}
+	w.skipSealHook = func(task *task) bool { return true }
+	w.fullTaskHook = func() {
+		time.Sleep(100 * time.Millisecond)
+	}
+	w.start()
+
+	select {
+	case task := <-taskCh:
+		txs := task.block.Transactions()
+		if len(txs) != len(included) {
+			t.Fatalf("transaction count mismatch: have %d, want %d", len(txs), len(included))
+		}
+		for i, tx := range txs {
+			if tx.Hash() != included[i].Hash() {
+				t.Errorf("transaction %d: hash mismatch: have %x, want %x", i, tx.Hash(), included[i].Hash())
+			}
+		}
+		if balance := task.state.GetBalance(testUserAddress); balance.Cmp(big.NewInt(3)) != 0 {
+			t.Errorf("account balance mismatch: have %d, want %d", balance, 3)
+		}
+	case <-time.NewTimer(3 * time.Second).C:
+		t.Fatal("new task timeout")
+	}
+}
@@ -51,6 +51,11 @@ type Config struct {
 	// in memory.
 	DataDir string
 
+	// DBEngine is the key-value engine backing the databases ("leveldb" or
+	// "pebble"). If empty, the engine of an existing database is used, with
+	// leveldb as the default for new ones.
+	DBEngine string `toml:",omitempty"`
+
 	// Configuration of peer-to-peer networking.
 	P2P p2p.Config
 
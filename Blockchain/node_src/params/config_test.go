@@ -111,6 +111,11 @@ func TestCheckConfigForkOrder(t *testing.T) {
 		{new: &ChainConfig{RedCoastBlock: big.NewInt(1)}, isErr: true},
 		{new: &ChainConfig{SophonBlock: big.NewInt(3)}, isErr: true},
 		{new: &ChainConfig{RedCoastBlock: big.NewInt(2), SophonBlock: big.NewInt(2)}, isErr: true},
+		{new: &ChainConfig{RedCoastBlock: big.NewInt(2), SophonBlock: big.NewInt(3), AgoraBlock: big.NewInt(4)}},
+		{new: &ChainConfig{RedCoastBlock: big.NewInt(2), AgoraBlock: big.NewInt(4)}, isErr: true},
+		{new: &ChainConfig{RedCoastBlock: big.NewInt(2), SophonBlock: big.NewInt(3), AgoraBlock: big.NewInt(3)}, isErr: true},
+		{new: &ChainConfig{RedCoastBlock: big.NewInt(2), SophonBlock: big.NewInt(3), AgoraBlock: big.NewInt(4), KlerosBlock: big.NewInt(5)}},
+		{new: &ChainConfig{RedCoastBlock: big.NewInt(2), SophonBlock: big.NewInt(3), KlerosBlock: big.NewInt(5)}, isErr: true},
 	}
 	for _, tc := range tests {
 		err := tc.new.CheckConfigForkOrder()
@@ -172,6 +172,10 @@ var (
 		accounts.MimetypeClique,
 		0x02,
 	}
+	ApplicationCongress = SigFormat{
+		accounts.MimetypeCongress,
+		0x02,
+	}
 	TextPlain = SigFormat{
 		accounts.MimetypeTextPlain,
 		0x45,
@@ -16,6 +16,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 
 	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/consensus/congress"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -158,6 +159,38 @@ func (api *SignerAPI) determineSignatureFormat(ctx context.Context, contentType
 		// Clique uses V on the form 0 or 1
 		useEthereumV = false
 		req = &SignDataRequest{ContentType: mediaType, Rawdata: cliqueRlp, Messages: messages, Hash: sighash}
+	case apitypes.ApplicationCongress.Mime:
+		// Congress seals headers like Clique, over a different encoding
+		stringData, ok := data.(string)
+		if !ok {
+			return nil, useEthereumV, fmt.Errorf("input for %v must be an hex-encoded string", apitypes.ApplicationCongress.Mime)
+		}
+		congressData, err := hexutil.Decode(stringData)
+		if err != nil {
+			return nil, useEthereumV, err
+		}
+		header := &types.Header{}
+		if err := rlp.DecodeBytes(congressData, header); err != nil {
+			return nil, useEthereumV, err
+		}
+		// The incoming header is sent without the signature in its extradata,
+		// add it back to hash it
+		header.Extra = append(header.Extra, make([]byte, crypto.SignatureLength)...)
+		messages := []*apitypes.NameValueType{
+			{
+				Name:  "Congress header",
+				Typ:   "congress",
+				Value: fmt.Sprintf("congress header %d [0x%x]", header.Number, header.Hash()),
+			},
+			{
+				Name:  "Congress header number",
+				Typ:   "congress-number",
+				Value: header.Number.String(),
+			},
+		}
+		// Congress uses V on the form 0 or 1
+		useEthereumV = false
+		req = &SignDataRequest{ContentType: mediaType, Rawdata: congress.CongressRLP(header), Messages: messages, Hash: congress.SealHash(header).Bytes()}
 	default: // also case TextPlain.Mime:
 		// Calculates an Ethereum ECDSA signature for:
 		// hash = keccak256("\x19${byteVersion}Ethereum Signed Message:\n${message length}${message}")
@@ -9,6 +9,7 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/congress/systemcontract"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 )
 
@@ -69,6 +70,12 @@ func (db *Database) ValidateTransaction(selector *string, tx *apitypes.SendTxArg
 	case tx.GasPrice != nil && tx.MaxPriorityFeePerGas != nil:
 		messages.Crit("Both 'gasPrice' and 'maxPriorityFeePerGas' specified.")
 	}
+	// Congress system governance transactions carry a proposal rather than call
+	// data, they are only sealed into blocks by their validator
+	if tx.To.Address() == systemcontract.SysGovToAddr && tx.GasPrice != nil && tx.GasPrice.ToInt().Sign() == 0 {
+		messages.Info("Transaction executes a Congress system governance proposal")
+		return messages, nil
+	}
 	// Semantic fields validated, try to make heads or tails of the call data
 	db.ValidateCallData(selector, data, messages)
 	return messages, nil
@@ -0,0 +1,23 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package rules
+
+import (
+	_ "embed"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// CongressRules is a rule template for Congress validators, approving their
+// block headers at strictly increasing heights only and their system governance
+// transactions.
+//
+//go:embed congress.js
+var CongressRules string
+
+// CongressRulesFor returns the Congress rule template with the address of the
+// validator filled in.
+func CongressRulesFor(validator common.Address) string {
+	return strings.Replace(CongressRules, `var VALIDATOR = ""`, `var VALIDATOR = "`+validator.Hex()+`"`, 1)
+}
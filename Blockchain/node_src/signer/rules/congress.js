@@ -0,0 +1,141 @@
+/**
+Rule template for Congress validators sealing blocks through clef.
+
+Block headers are only approved at strictly increasing heights, per validator,
+so that a validator never signs two blocks at the same height: not after a
+restart, nor when two nodes share the signer, nor when the node is fed a fork.
+The last approved height is kept in the clef storage, which must therefore be
+backed up along with the keystore.
+
+The system governance transactions of the validator are approved as well:
+zero priced, valueless transactions from VALIDATOR to the governance address,
+carrying a proposal with a known action. Other transactions to the governance
+address are rejected, anything else goes to manual processing.
+**/
+
+// VALIDATOR is the address the node seals with, to be filled in before the
+// rules are attested.
+var VALIDATOR = ""
+
+// GOVERNANCE is the address system governance transactions are sent to.
+var GOVERNANCE = "0x000000000000000000000000000000000000ffff"
+
+// MAX_ACTION is the highest known system governance action.
+var MAX_ACTION = 5
+
+function big(str) {
+	if (str.slice(0, 2) == "0x") {
+		return new BigNumber(str.slice(2), 16)
+	}
+	return new BigNumber(str)
+}
+
+// congressHeight returns the number of the Congress header to sign.
+function congressHeight(r) {
+	for (var i = 0; i < r.messages.length; i++) {
+		if (r.messages[i].type == "congress-number") {
+			return big(r.messages[i].value)
+		}
+	}
+	return null
+}
+
+function ApproveSignData(r) {
+	if (r.content_type != "application/x-congress-header") {
+		return
+	}
+	var height = congressHeight(r)
+	if (height == null) {
+		return "Reject"
+	}
+	var key = "congress-height-" + r.address.toLowerCase()
+	var last = storage.get(key)
+	if (last != "" && height.lte(big(last))) {
+		console.log("Refusing to sign Congress header", height.toString(), "last signed", last)
+		return "Reject"
+	}
+	storage.put(key, height.toString())
+	return "Approve"
+}
+
+// rlpItems decodes the hex encoded RLP list of strings into the hex encoded
+// strings, or returns null if it's anything else.
+function rlpItems(hex) {
+	if (!hex || hex.slice(0, 2) != "0x" || hex.length % 2 != 0) {
+		return null
+	}
+	var data = []
+	for (var i = 2; i < hex.length; i += 2) {
+		data.push(parseInt(hex.slice(i, i+2), 16))
+	}
+	// header returns the offset and the length of the payload at pos
+	function header(pos, short, long) {
+		var b = data[pos]
+		if (b < long) {
+			return {offset: pos + 1, length: b - short}
+		}
+		var size = 0
+		for (var i = 1; i <= b - long + 1; i++) {
+			size = size*256 + data[pos+i]
+		}
+		return {offset: pos + 1 + b - long + 1, length: size}
+	}
+	if (data.length == 0 || data[0] < 0xc0) {
+		return null
+	}
+	var list = header(0, 0xc0, 0xf8)
+	if (list.offset + list.length != data.length) {
+		return null
+	}
+	var items = []
+	for (var pos = list.offset; pos < data.length; ) {
+		var item
+		if (data[pos] < 0x80) {
+			item = {offset: pos, length: 1}
+		} else if (data[pos] < 0xc0) {
+			item = header(pos, 0x80, 0xb8)
+		} else {
+			return null
+		}
+		if (item.offset + item.length > data.length) {
+			return null
+		}
+		items.push("0x" + hex.slice(2 + item.offset*2, 2 + (item.offset+item.length)*2))
+		pos = item.offset + item.length
+	}
+	return items
+}
+
+// isProposal returns whether the transaction data is a system governance
+// proposal (id, action, from, to, value, data) with a known action.
+function isProposal(data) {
+	var items = rlpItems(data)
+	if (items == null || items.length != 6 || items[2].length != 42 || items[3].length != 42) {
+		return false
+	}
+	return items[1] == "0x" || big(items[1]).lte(MAX_ACTION)
+}
+
+function ApproveTx(r) {
+	var tx = r.transaction
+	if (!tx.to || tx.to.toLowerCase() != GOVERNANCE) {
+		return
+	}
+	if (VALIDATOR == "" || tx.from.toLowerCase() != VALIDATOR.toLowerCase()) {
+		console.log("Refusing system transaction from", tx.from)
+		return "Reject"
+	}
+	if (!tx.gasPrice || !big(tx.gasPrice).isZero() || !big(tx.value).isZero()) {
+		console.log("Refusing system transaction with gas price", tx.gasPrice, "value", tx.value)
+		return "Reject"
+	}
+	if (!isProposal(tx.input || tx.data)) {
+		console.log("Refusing system transaction with unknown data")
+		return "Reject"
+	}
+	return "Approve"
+}
+
+function ApproveListing() {
+	return "Approve"
+}
@@ -0,0 +1,177 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package rules
+
+import (
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/external"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/congress"
+	"github.com/ethereum/go-ethereum/consensus/congress/systemcontract"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/ethereum/go-ethereum/signer/storage"
+)
+
+// manualUI denies every request left to manual processing, and counts them.
+type manualUI struct {
+	alwaysDenyUI
+	manual int
+}
+
+func (ui *manualUI) ApproveTx(request *core.SignTxRequest) (core.SignTxResponse, error) {
+	ui.manual++
+	return ui.alwaysDenyUI.ApproveTx(request)
+}
+
+func (ui *manualUI) ApproveSignData(request *core.SignDataRequest) (core.SignDataResponse, error) {
+	ui.manual++
+	return ui.alwaysDenyUI.ApproveSignData(request)
+}
+
+func (ui *manualUI) ShowError(message string)                     {}
+func (ui *manualUI) ShowInfo(message string)                      {}
+func (ui *manualUI) OnApprovedTx(tx ethapi.SignTransactionResult) {}
+
+// passingValidator raises no warning on any transaction.
+type passingValidator struct{}
+
+func (passingValidator) ValidateTransaction(selector *string, tx *apitypes.SendTxArgs) (*apitypes.ValidationMessages, error) {
+	return new(apitypes.ValidationMessages), nil
+}
+
+// Tests that Congress validators seal headers and sign system transactions
+// through an external signer running the Congress rules, which never signs two
+// headers at the same height.
+func TestCongressRules(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.ImportECDSA(key, "validator password")
+	if err != nil {
+		t.Fatalf("failed to import key: %v", err)
+	}
+	otherKey, _ := crypto.GenerateKey()
+	other, err := ks.ImportECDSA(otherKey, "other password")
+	if err != nil {
+		t.Fatalf("failed to import key: %v", err)
+	}
+	credentials := storage.NewEphemeralStorage()
+	credentials.Put(account.Address.Hex(), "validator password")
+	credentials.Put(other.Address.Hex(), "other password")
+
+	ui := &manualUI{}
+	rules, err := NewRuleEvaluator(ui, storage.NewEphemeralStorage())
+	if err != nil {
+		t.Fatalf("failed to create rule evaluator: %v", err)
+	}
+	if err := rules.Init(CongressRulesFor(account.Address)); err != nil {
+		t.Fatalf("failed to load rules: %v", err)
+	}
+	chainID := big.NewInt(1337)
+	api := core.NewSignerAPI(accounts.NewManager(&accounts.Config{}, ks), chainID.Int64(), true, rules, passingValidator{}, false, credentials)
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", api); err != nil {
+		t.Fatalf("failed to register signer: %v", err)
+	}
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+
+	signer, err := external.NewExternalSigner(httpsrv.URL)
+	if err != nil {
+		t.Fatalf("failed to connect to signer: %v", err)
+	}
+	validator := accounts.Account{Address: account.Address}
+
+	// Seal headers as Congress does
+	seal := func(number int64, root common.Hash) error {
+		header := &types.Header{
+			Number:     big.NewInt(number),
+			Difficulty: big.NewInt(2),
+			Root:       root,
+			Extra:      make([]byte, 32+crypto.SignatureLength),
+		}
+		sig, err := signer.SignData(validator, accounts.MimetypeCongress, congress.CongressRLP(header))
+		if err != nil {
+			return err
+		}
+		pubkey, err := crypto.SigToPub(congress.SealHash(header).Bytes(), sig)
+		if err != nil {
+			t.Fatalf("block #%d: failed to recover sealer: %v", number, err)
+		}
+		if sealer := crypto.PubkeyToAddress(*pubkey); sealer != account.Address {
+			t.Fatalf("block #%d: sealer mismatch: have %x, want %x", number, sealer, account.Address)
+		}
+		return nil
+	}
+	for i, tt := range []struct {
+		number int64
+		root   common.Hash
+		ok     bool
+	}{
+		{5, common.Hash{0x1}, true},
+		{6, common.Hash{0x1}, true},
+		{6, common.Hash{0x2}, false}, // Another block at the same height
+		{4, common.Hash{0x1}, false}, // A block below the last height
+		{8, common.Hash{0x1}, true},
+	} {
+		if err := seal(tt.number, tt.root); (err == nil) != tt.ok {
+			t.Errorf("test %d: block #%d: sealing error mismatch: have %v, want ok %v", i, tt.number, err, tt.ok)
+		}
+	}
+	// System governance transactions of the validator are approved, the ones
+	// breaking any rule rejected, other transactions and data are left to
+	// manual processing
+	proposal := func(action uint64) []byte {
+		blob, _ := rlp.EncodeToBytes(&congress.Proposal{Id: big.NewInt(1), Action: new(big.Int).SetUint64(action), Value: new(big.Int), Data: []byte{0x1}})
+		return blob
+	}
+	for action := uint64(congress.ActionEvmCall); action <= congress.ActionRemoveValidator; action++ {
+		sysTx := types.NewTransaction(action, systemcontract.SysGovToAddr, new(big.Int), 1000000, new(big.Int), proposal(action))
+		signed, err := signer.SignTx(validator, sysTx, chainID)
+		if err != nil {
+			t.Fatalf("action %d: failed to sign system transaction: %v", action, err)
+		}
+		if from, err := types.Sender(types.NewEIP155Signer(chainID), signed); err != nil || from != account.Address {
+			t.Errorf("action %d: system transaction sender mismatch: have %x, %v", action, from, err)
+		}
+	}
+	for i, tt := range []struct {
+		from     accounts.Account
+		value    *big.Int
+		gasPrice *big.Int
+		data     []byte
+	}{
+		{accounts.Account{Address: other.Address}, new(big.Int), new(big.Int), proposal(congress.ActionEvmCall)}, // Another sender
+		{validator, big.NewInt(1), new(big.Int), proposal(congress.ActionEvmCall)},                               // Moving funds
+		{validator, new(big.Int), big.NewInt(1), proposal(congress.ActionEvmCall)},                               // Paying gas
+		{validator, new(big.Int), new(big.Int), proposal(congress.ActionRemoveValidator + 1)},                    // Unknown action
+		{validator, new(big.Int), new(big.Int), []byte{0xa9, 0x05, 0x9c, 0xbb}},                                  // Not a proposal
+		{validator, new(big.Int), new(big.Int), append(proposal(congress.ActionEvmCall), 0x0)},                   // Trailing data
+		{validator, new(big.Int), new(big.Int), nil},                                                             // No data
+	} {
+		tx := types.NewTransaction(0, systemcontract.SysGovToAddr, tt.value, 1000000, tt.gasPrice, tt.data)
+		if _, err := signer.SignTx(tt.from, tx, chainID); err == nil {
+			t.Errorf("test %d: invalid system transaction approved", i)
+		}
+	}
+	if _, err := signer.SignTx(validator, types.NewTransaction(1, common.Address{0x1}, new(big.Int), 21000, big.NewInt(1), nil), chainID); err == nil {
+		t.Errorf("plain transaction approved")
+	}
+	if _, err := signer.SignText(validator, []byte("hello")); err == nil {
+		t.Errorf("text approved")
+	}
+	if ui.manual != 2 {
+		t.Errorf("manual requests mismatch: have %d, want 2", ui.manual)
+	}
+}
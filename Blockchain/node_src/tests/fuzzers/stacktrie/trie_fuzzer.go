@@ -159,7 +159,7 @@ func (f *fuzzer) fuzz() int {
 		return 0
 	}
 	// Flush trie -> database
-	rootA, _, err := trieA.Commit(nil)
+	rootA, _, _, err := trieA.Commit(nil)
 	if err != nil {
 		panic(err)
 	}
@@ -80,6 +80,8 @@ type Database struct {
 
 	FlushLatch sync.WaitGroup
 	lock       sync.RWMutex
+
+	pathdb *pathDatabase // Backend of the path scheme, nil in the hash scheme
 }
 
 // Cache used to store <hash, trie> nodes while hashing the trie
@@ -340,6 +342,9 @@ type Config struct {
 	Cache     int    // Memory allowance (MB) to use for caching trie nodes in memory
 	Journal   string // Journal of clean cache to survive node restarts
 	Preimages bool   // Flag whether the preimage of trie key is recorded
+
+	Scheme       string // Storage scheme of the trie nodes, rawdb.HashScheme if empty
+	StateHistory uint64 // Number of recent states to keep reverse diffs for in the path scheme, zero for all
 }
 
 // NewDatabase creates a new trie database to store ephemeral trie content before
@@ -372,6 +377,9 @@ func NewDatabaseWithConfig(diskdb ethdb.KeyValueStore, config *Config) *Database
 	if config == nil || config.Preimages { // TODO(karalabe): Flip to default off in the future
 		db.preimages = make(map[common.Hash][]byte)
 	}
+	if config != nil && config.Scheme == rawdb.PathScheme {
+		db.pathdb = newPathDatabase(diskdb, cleans, config.StateHistory)
+	}
 	return db
 }
 
@@ -478,6 +486,10 @@ func (db *Database) Node(hash common.Hash) ([]byte, error) {
 	if hash == (common.Hash{}) {
 		return nil, errors.New("not found")
 	}
+	// Nodes can't be looked up by hash alone in the path scheme
+	if db.pathdb != nil {
+		return nil, errNotSupported
+	}
 	// Retrieve the node from the clean cache if available
 	if db.cleans != nil {
 		if enc := db.cleans.Get(nil, hash[:]); enc != nil {
@@ -558,6 +570,9 @@ func (db *Database) Nodes() []common.Hash {
 // and external node(e.g. storage trie root), all internal trie nodes
 // are referenced together by database itself.
 func (db *Database) Reference(child common.Hash, parent common.Hash) {
+	if db.pathdb != nil {
+		return
+	}
 	db.lock.Lock()
 	defer db.lock.Unlock()
 
@@ -592,6 +607,9 @@ func (db *Database) Dereference(root common.Hash) {
 		log.Error("Attempted to dereference the trie cache meta root")
 		return
 	}
+	if db.pathdb != nil {
+		return
+	}
 	db.lock.Lock()
 	defer db.lock.Unlock()
 
@@ -664,6 +682,9 @@ func (db *Database) dereference(child common.Hash, parent common.Hash) {
 // Note, this method is a non-synchronized mutator. It is unsafe to call this
 // concurrently with other mutators.
 func (db *Database) Cap(limit common.StorageSize) error {
+	if db.pathdb != nil {
+		return nil
+	}
 	// Create a database batch to flush persistent data out. It is important that
 	// outside code doesn't see an inconsistent state (referenced data removed from
 	// memory cache during commit but not yet in persistent storage). This is ensured
@@ -782,6 +803,15 @@ func (db *Database) Commit(node common.Hash, report bool, callback func(common.H
 		}
 		batch.Reset()
 	}
+	// In the path scheme, flatten all the in-memory states into the disk
+	if db.pathdb != nil {
+		if db.preimages != nil {
+			db.lock.Lock()
+			db.preimages, db.preimagesSize = make(map[common.Hash][]byte), 0
+			db.lock.Unlock()
+		}
+		return db.pathdb.commit(node)
+	}
 	// Move the trie itself into the batch, flushing if enough data is accumulated
 	nodes, storage := len(db.dirties), db.dirtiesSize
 
@@ -908,6 +938,9 @@ func (c *cleaner) Delete(key []byte) error {
 // Size returns the current storage size of the memory cache in front of the
 // persistent database layer.
 func (db *Database) Size() (common.StorageSize, common.StorageSize) {
+	if db.pathdb != nil {
+		return db.pathdb.size(), db.preimagesSize
+	}
 	db.lock.RLock()
 	defer db.lock.RUnlock()
 
@@ -343,7 +343,7 @@ func (it *nodeIterator) resolveHash(hash hashNode, path []byte) (node, error) {
 			}
 		}
 	}
-	resolved, err := it.trie.resolveHash(hash, path)
+	resolved, err := it.trie.resolveNode(hash, path)
 	return resolved, err
 }
 
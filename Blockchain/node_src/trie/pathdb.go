@@ -0,0 +1,754 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package trie
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/fastcache"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	pathCleanHitMeter  = metrics.NewRegisteredMeter("trie/path/clean/hit", nil)
+	pathCleanMissMeter = metrics.NewRegisteredMeter("trie/path/clean/miss", nil)
+	pathDirtyHitMeter  = metrics.NewRegisteredMeter("trie/path/dirty/hit", nil)
+	pathDirtyMissMeter = metrics.NewRegisteredMeter("trie/path/dirty/miss", nil)
+
+	pathCommitTimeTimer  = metrics.NewRegisteredResettingTimer("trie/path/commit/time", nil)
+	pathCommitNodesMeter = metrics.NewRegisteredMeter("trie/path/commit/nodes", nil)
+	pathHistoryTimeTimer = metrics.NewRegisteredResettingTimer("trie/path/history/time", nil)
+	pathHistorySizeMeter = metrics.NewRegisteredMeter("trie/path/history/size", nil)
+)
+
+var (
+	// errStateUnavailable is returned if the requested state is neither held
+	// in memory nor persisted on disk.
+	errStateUnavailable = errors.New("state not available")
+
+	// errStateUnrecoverable is returned if the persisted state cannot be
+	// reverted to the requested one, either because the state is unknown or
+	// because the required state histories were already pruned.
+	errStateUnrecoverable = errors.New("state not recoverable")
+
+	// errNotSupported is returned by the operations which are not available in
+	// the configured state scheme.
+	errNotSupported = errors.New("not supported by the state scheme")
+)
+
+// journalVersion is the version of the serialized in-memory trie node layers.
+// A journal of a different version is discarded on startup.
+const journalVersion uint64 = 0
+
+// NodeSet contains the trie nodes written or deleted by the commit of a single
+// trie in the path scheme, keyed by their path.
+type NodeSet struct {
+	owner common.Hash
+	nodes map[string][]byte   // Written nodes, nil blob for the deleted ones
+	clean map[string]struct{} // Roots of the untouched subtries
+}
+
+// newNodeSet creates an empty node set for the trie of the given owner.
+func newNodeSet(owner common.Hash) *NodeSet {
+	return &NodeSet{
+		owner: owner,
+		nodes: make(map[string][]byte),
+		clean: make(map[string]struct{}),
+	}
+}
+
+// addNode tracks a written trie node, a nil blob marks the node as deleted.
+func (s *NodeSet) addNode(path []byte, blob []byte) {
+	s.nodes[string(path)] = blob
+}
+
+// addClean tracks the root of an untouched subtrie.
+func (s *NodeSet) addClean(path []byte) {
+	s.clean[string(path)] = struct{}{}
+}
+
+// retained reports whether the node at the given path lives in an untouched
+// subtrie and thus remains part of the committed trie.
+func (s *NodeSet) retained(path string) bool {
+	for i := 0; i <= len(path); i++ {
+		if _, ok := s.clean[path[:i]]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of trie nodes written or deleted.
+func (s *NodeSet) Len() int {
+	return len(s.nodes)
+}
+
+// MergedNodeSet gathers the node sets committed by all the tries of a single
+// state transition, to be sealed together into the resulting state.
+type MergedNodeSet struct {
+	sets map[common.Hash]map[string][]byte
+}
+
+// NewMergedNodeSet creates an empty merged node set.
+func NewMergedNodeSet() *MergedNodeSet {
+	return &MergedNodeSet{sets: make(map[common.Hash]map[string][]byte)}
+}
+
+// Merge adds the nodes of the given set. Nodes of the same owner merged before,
+// e.g. the wiped storage of a recreated account, are overwritten. A nil set is
+// ignored.
+func (m *MergedNodeSet) Merge(set *NodeSet) {
+	if set == nil {
+		return
+	}
+	subset := m.sets[set.owner]
+	if subset == nil {
+		subset = make(map[string][]byte, len(set.nodes))
+		m.sets[set.owner] = subset
+	}
+	for path, blob := range set.nodes {
+		subset[path] = blob
+	}
+}
+
+// pathLayer is a single state held by the path database. The bottom layer is
+// the state persisted on disk, all others are diff layers in memory holding the
+// trie nodes changed on top of their parent.
+type pathLayer struct {
+	root   common.Hash                       // Root hash of the state
+	id     uint64                            // Sequential id of the state, zero for the empty state
+	parent *pathLayer                        // Parent layer, nil for the disk layer
+	nodes  map[common.Hash]map[string][]byte // Trie nodes by owner and path, nil blob for deleted ones
+	size   common.StorageSize                // Approximate memory held by the trie nodes
+}
+
+// stateHistory is the reverse diff of a state transition, holding the trie
+// nodes overwritten when the state was persisted.
+type stateHistory struct {
+	Parent common.Hash // Root of the state before the transition
+	Root   common.Hash // Root of the state after the transition
+	Nodes  []historyNode
+}
+
+// historyNode is a trie node as it was before a state transition, an empty
+// blob marks a node which did not exist.
+type historyNode struct {
+	Owner common.Hash
+	Path  []byte
+	Prev  []byte
+}
+
+// journalNode is a trie node of a journalled diff layer, an empty blob marks a
+// deleted node.
+type journalNode struct {
+	Owner common.Hash
+	Path  []byte
+	Blob  []byte
+}
+
+// journalLayer is a journalled diff layer.
+type journalLayer struct {
+	Root  common.Hash
+	Nodes []journalNode
+}
+
+// pathJournal is the serialized set of diff layers stacked on top of the disk
+// layer, saved on shutdown and restored on startup.
+type pathJournal struct {
+	Version  uint64
+	DiskRoot common.Hash
+	Layers   []journalLayer // Ordered from the bottom up
+}
+
+// pathDatabase is the backend of the path scheme. A single state is persisted
+// on disk with the trie nodes keyed by owner and path, the recent states are
+// kept as a tree of diff layers in memory. Whenever a diff layer is flattened
+// into the disk, a state history is written allowing the persisted state to be
+// reverted later on.
+type pathDatabase struct {
+	diskdb  ethdb.KeyValueStore
+	cleans  *fastcache.Cache // Clean trie node cache of the disk layer, keyed by owner and path
+	history uint64           // Number of state histories to retain, zero for unlimited
+
+	layers map[common.Hash]*pathLayer // All the states held, keyed by root
+	disk   *pathLayer                 // The persisted state
+	lock   sync.RWMutex
+}
+
+// newPathDatabase opens the persisted state of the database, restoring the
+// diff layers from the journal if it matches.
+func newPathDatabase(diskdb ethdb.KeyValueStore, cleans *fastcache.Cache, history uint64) *pathDatabase {
+	db := &pathDatabase{
+		diskdb:  diskdb,
+		cleans:  cleans,
+		history: history,
+	}
+	root := emptyRoot
+	if blob := rawdb.ReadAccountTrieNode(diskdb, nil); len(blob) > 0 {
+		root = crypto.Keccak256Hash(blob)
+	}
+	db.disk = &pathLayer{root: root, id: rawdb.ReadPersistentStateID(diskdb)}
+	db.layers = map[common.Hash]*pathLayer{root: db.disk}
+
+	if err := db.loadJournal(); err != nil {
+		log.Info("Discarded trie journal", "err", err)
+	}
+	// The journal is only valid for the shutdown it was written at, drop it so
+	// a crash later on doesn't resurrect stale layers.
+	rawdb.DeleteTrieJournal(diskdb)
+	return db
+}
+
+// cacheKey returns the key of a trie node in the clean cache.
+func cacheKey(owner common.Hash, path []byte) []byte {
+	return append(owner.Bytes(), path...)
+}
+
+// readDisk retrieves the persisted trie node of the given owner at the given
+// path, nil if the node doesn't exist.
+func (db *pathDatabase) readDisk(owner common.Hash, path []byte) []byte {
+	if db.cleans != nil {
+		if blob := db.cleans.Get(nil, cacheKey(owner, path)); len(blob) > 0 {
+			pathCleanHitMeter.Mark(1)
+			return blob
+		}
+		pathCleanMissMeter.Mark(1)
+	}
+	var blob []byte
+	if owner == (common.Hash{}) {
+		blob = rawdb.ReadAccountTrieNode(db.diskdb, path)
+	} else {
+		blob = rawdb.ReadStorageTrieNode(db.diskdb, owner, path)
+	}
+	if len(blob) > 0 && db.cleans != nil {
+		db.cleans.Set(cacheKey(owner, path), blob)
+	}
+	return blob
+}
+
+// writeDisk stores or deletes a trie node in the batch, keeping the clean
+// cache in sync.
+func (db *pathDatabase) writeDisk(batch ethdb.KeyValueWriter, owner common.Hash, path []byte, blob []byte) {
+	if owner == (common.Hash{}) {
+		if len(blob) == 0 {
+			rawdb.DeleteAccountTrieNode(batch, path)
+		} else {
+			rawdb.WriteAccountTrieNode(batch, path, blob)
+		}
+	} else {
+		if len(blob) == 0 {
+			rawdb.DeleteStorageTrieNode(batch, owner, path)
+		} else {
+			rawdb.WriteStorageTrieNode(batch, owner, path, blob)
+		}
+	}
+	if db.cleans != nil {
+		if len(blob) == 0 {
+			db.cleans.Del(cacheKey(owner, path))
+		} else {
+			db.cleans.Set(cacheKey(owner, path), blob)
+		}
+	}
+}
+
+// node retrieves the trie node of the given owner at the given path within
+// the given state. Nil is returned if the node is missing or its hash does not
+// match the expected one.
+func (db *pathDatabase) node(stateRoot common.Hash, owner common.Hash, path []byte, hash common.Hash) ([]byte, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	layer := db.layers[stateRoot]
+	if layer == nil {
+		return nil, fmt.Errorf("%w: %x", errStateUnavailable, stateRoot)
+	}
+	var blob []byte
+	for ; layer.parent != nil; layer = layer.parent {
+		if b, ok := layer.nodes[owner][string(path)]; ok {
+			pathDirtyHitMeter.Mark(1)
+			blob = b
+			break
+		}
+	}
+	if layer.parent == nil {
+		pathDirtyMissMeter.Mark(1)
+		blob = db.readDisk(owner, path)
+	}
+	if len(blob) == 0 || crypto.Keccak256Hash(blob) != hash {
+		return nil, nil
+	}
+	return blob, nil
+}
+
+// update seals the trie nodes committed by a state transition into a new diff
+// layer on top of the parent state. If the state did not change or is already
+// known, the nodes are discarded.
+func (db *pathDatabase) update(root common.Hash, parentRoot common.Hash, nodes *MergedNodeSet) error {
+	if parentRoot == (common.Hash{}) {
+		parentRoot = emptyRoot
+	}
+	if root == parentRoot {
+		return nil
+	}
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if _, ok := db.layers[root]; ok {
+		return nil
+	}
+	parent := db.layers[parentRoot]
+	if parent == nil {
+		return fmt.Errorf("%w: parent %x", errStateUnavailable, parentRoot)
+	}
+	layer := &pathLayer{
+		root:   root,
+		id:     parent.id + 1,
+		parent: parent,
+		nodes:  make(map[common.Hash]map[string][]byte),
+	}
+	if nodes != nil {
+		var count int
+		for owner, subset := range nodes.sets {
+			for path, blob := range subset {
+				layer.size += common.StorageSize(common.HashLength + len(path) + len(blob))
+			}
+			layer.nodes[owner] = subset
+			count += len(subset)
+		}
+		pathCommitNodesMeter.Mark(int64(count))
+	}
+	db.layers[root] = layer
+	return nil
+}
+
+// cap flattens the oldest diff layers below root into the disk until at most
+// the given number of diff layers are left, dropping the states which are no
+// longer reachable from the disk.
+func (db *pathDatabase) cap(root common.Hash, layers int) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	layer := db.layers[root]
+	if layer == nil {
+		return fmt.Errorf("%w: %x", errStateUnavailable, root)
+	}
+	var diffs []*pathLayer
+	for ; layer.parent != nil; layer = layer.parent {
+		diffs = append(diffs, layer)
+	}
+	if len(diffs) <= layers {
+		return nil
+	}
+	for i := len(diffs) - 1; i >= layers; i-- {
+		if err := db.persist(diffs[i]); err != nil {
+			return err
+		}
+	}
+	db.dropStale()
+	return nil
+}
+
+// persist flattens the given diff layer, whose parent must be the disk layer,
+// into the disk, writing the state history of the transition.
+// Note, this method assumes that the database's lock is held!
+func (db *pathDatabase) persist(diff *pathLayer) error {
+	var (
+		start = time.Now()
+		batch = db.diskdb.NewBatch()
+		hist  = &stateHistory{Parent: db.disk.root, Root: diff.root}
+	)
+	owners := make([]common.Hash, 0, len(diff.nodes))
+	for owner := range diff.nodes {
+		owners = append(owners, owner)
+	}
+	sort.Slice(owners, func(i, j int) bool { return bytes.Compare(owners[i][:], owners[j][:]) < 0 })
+
+	for _, owner := range owners {
+		subset := diff.nodes[owner]
+		paths := make([]string, 0, len(subset))
+		for path := range subset {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		for _, path := range paths {
+			prev := db.readDisk(owner, []byte(path))
+			hist.Nodes = append(hist.Nodes, historyNode{Owner: owner, Path: []byte(path), Prev: prev})
+			db.writeDisk(batch, owner, []byte(path), subset[path])
+		}
+	}
+	enc, err := rlp.EncodeToBytes(hist)
+	if err != nil {
+		return err
+	}
+	rawdb.WriteStateHistory(batch, diff.id, enc)
+	rawdb.WriteStateID(batch, diff.root, diff.id)
+	rawdb.WritePersistentStateID(batch, diff.id)
+
+	// Prune the state histories beyond the retention limit
+	if db.history > 0 && diff.id > db.history {
+		tail := rawdb.ReadStateHistoryTail(db.diskdb)
+		if tail == 0 {
+			tail = 1
+		}
+		newTail := diff.id - db.history + 1
+		for id := tail; id < newTail; id++ {
+			if err := db.pruneHistory(batch, id); err != nil {
+				return err
+			}
+		}
+		if newTail > tail {
+			rawdb.WriteStateHistoryTail(batch, newTail)
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	disk := &pathLayer{root: diff.root, id: diff.id}
+	for _, layer := range db.layers {
+		if layer.parent == diff {
+			layer.parent = disk
+		}
+	}
+	delete(db.layers, db.disk.root)
+	db.layers[diff.root] = disk
+	db.disk = disk
+
+	pathHistoryTimeTimer.UpdateSince(start)
+	pathHistorySizeMeter.Mark(int64(len(enc)))
+	log.Debug("Persisted trie layer", "id", diff.id, "root", diff.root, "nodes", len(hist.Nodes), "size", diff.size, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// pruneHistory deletes the state history with the given id, after which the
+// parent state of the transition can no longer be recovered.
+func (db *pathDatabase) pruneHistory(batch ethdb.KeyValueWriter, id uint64) error {
+	enc := rawdb.ReadStateHistory(db.diskdb, id)
+	if len(enc) == 0 {
+		return nil
+	}
+	var hist stateHistory
+	if err := rlp.DecodeBytes(enc, &hist); err != nil {
+		return err
+	}
+	if stored := rawdb.ReadStateID(db.diskdb, hist.Parent); stored != nil && *stored == id-1 {
+		rawdb.DeleteStateID(batch, hist.Parent)
+	}
+	rawdb.DeleteStateHistory(batch, id)
+	return nil
+}
+
+// dropStale removes all the diff layers which are not descendants of the disk
+// layer anymore.
+// Note, this method assumes that the database's lock is held!
+func (db *pathDatabase) dropStale() {
+	for root, layer := range db.layers {
+		bottom := layer
+		for bottom.parent != nil {
+			bottom = bottom.parent
+		}
+		if bottom != db.disk {
+			delete(db.layers, root)
+		}
+	}
+}
+
+// commit flattens all the diff layers below root into the disk.
+func (db *pathDatabase) commit(root common.Hash) error {
+	start := time.Now()
+	if err := db.cap(root, 0); err != nil {
+		return err
+	}
+	pathCommitTimeTimer.UpdateSince(start)
+	return nil
+}
+
+// size returns the memory held by the diff layers.
+func (db *pathDatabase) size() common.StorageSize {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	var size common.StorageSize
+	for _, layer := range db.layers {
+		size += layer.size
+	}
+	return size
+}
+
+// recoverable reports whether the given state is available or can be reached
+// by reverting the persisted state.
+func (db *pathDatabase) recoverable(root common.Hash) bool {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if _, ok := db.layers[root]; ok {
+		return true
+	}
+	return db.recoverableID(root) != nil
+}
+
+// recoverableID returns the id of the given state if the persisted state can
+// be reverted into it, nil otherwise.
+// Note, this method assumes that the database's lock is held!
+func (db *pathDatabase) recoverableID(root common.Hash) *uint64 {
+	id := rawdb.ReadStateID(db.diskdb, root)
+	if id == nil || *id >= db.disk.id {
+		return nil
+	}
+	tail := rawdb.ReadStateHistoryTail(db.diskdb)
+	if tail == 0 {
+		tail = 1
+	}
+	if *id+1 < tail {
+		return nil
+	}
+	return id
+}
+
+// recover reverts the persisted state into the given one by applying the state
+// histories backwards. All the diff layers in memory are discarded as they are
+// descendants of the persisted state.
+func (db *pathDatabase) recover(root common.Hash) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if _, ok := db.layers[root]; ok {
+		return nil
+	}
+	target := db.recoverableID(root)
+	if target == nil {
+		return fmt.Errorf("%w: %x", errStateUnrecoverable, root)
+	}
+	start := time.Now()
+	db.layers = map[common.Hash]*pathLayer{db.disk.root: db.disk}
+
+	for db.disk.id > *target {
+		enc := rawdb.ReadStateHistory(db.diskdb, db.disk.id)
+		if len(enc) == 0 {
+			return fmt.Errorf("state history %d is missing", db.disk.id)
+		}
+		var hist stateHistory
+		if err := rlp.DecodeBytes(enc, &hist); err != nil {
+			return err
+		}
+		if hist.Root != db.disk.root {
+			return fmt.Errorf("state history %d mismatch: have %x, want %x", db.disk.id, hist.Root, db.disk.root)
+		}
+		batch := db.diskdb.NewBatch()
+		for _, n := range hist.Nodes {
+			db.writeDisk(batch, n.Owner, n.Path, n.Prev)
+		}
+		rawdb.DeleteStateHistory(batch, db.disk.id)
+		rawdb.DeleteStateID(batch, hist.Root)
+		rawdb.WritePersistentStateID(batch, db.disk.id-1)
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		db.disk = &pathLayer{root: hist.Parent, id: db.disk.id - 1}
+	}
+	db.layers = map[common.Hash]*pathLayer{db.disk.root: db.disk}
+	rawdb.DeleteTrieJournal(db.diskdb)
+
+	log.Info("Recovered persisted state", "root", root, "id", db.disk.id, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// journal persists the diff layers from root down to the disk layer, so they
+// can be restored after a restart.
+func (db *pathDatabase) journal(root common.Hash) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	layer := db.layers[root]
+	if layer == nil {
+		return fmt.Errorf("%w: %x", errStateUnavailable, root)
+	}
+	var diffs []*pathLayer
+	for ; layer.parent != nil; layer = layer.parent {
+		diffs = append(diffs, layer)
+	}
+	journal := &pathJournal{Version: journalVersion, DiskRoot: db.disk.root}
+	for i := len(diffs) - 1; i >= 0; i-- {
+		entry := journalLayer{Root: diffs[i].root}
+		for owner, subset := range diffs[i].nodes {
+			for path, blob := range subset {
+				entry.Nodes = append(entry.Nodes, journalNode{Owner: owner, Path: []byte(path), Blob: blob})
+			}
+		}
+		journal.Layers = append(journal.Layers, entry)
+	}
+	enc, err := rlp.EncodeToBytes(journal)
+	if err != nil {
+		return err
+	}
+	rawdb.WriteTrieJournal(db.diskdb, enc)
+	log.Info("Persisted trie journal", "layers", len(diffs), "size", common.StorageSize(len(enc)))
+	return nil
+}
+
+// loadJournal restores the diff layers saved at the last shutdown, if they are
+// stacked on top of the persisted state.
+func (db *pathDatabase) loadJournal() error {
+	enc := rawdb.ReadTrieJournal(db.diskdb)
+	if len(enc) == 0 {
+		return nil
+	}
+	var journal pathJournal
+	if err := rlp.DecodeBytes(enc, &journal); err != nil {
+		return err
+	}
+	if journal.Version != journalVersion {
+		return fmt.Errorf("journal version mismatch: have %d, want %d", journal.Version, journalVersion)
+	}
+	if journal.DiskRoot != db.disk.root {
+		return fmt.Errorf("journal root mismatch: have %x, want %x", journal.DiskRoot, db.disk.root)
+	}
+	parent := db.disk
+	for _, entry := range journal.Layers {
+		layer := &pathLayer{
+			root:   entry.Root,
+			id:     parent.id + 1,
+			parent: parent,
+			nodes:  make(map[common.Hash]map[string][]byte),
+		}
+		for _, n := range entry.Nodes {
+			subset := layer.nodes[n.Owner]
+			if subset == nil {
+				subset = make(map[string][]byte)
+				layer.nodes[n.Owner] = subset
+			}
+			var blob []byte
+			if len(n.Blob) > 0 {
+				blob = n.Blob
+			}
+			subset[string(n.Path)] = blob
+			layer.size += common.StorageSize(common.HashLength + len(n.Path) + len(n.Blob))
+		}
+		db.layers[layer.root] = layer
+		parent = layer
+	}
+	log.Info("Loaded trie journal", "layers", len(journal.Layers), "head", parent.root)
+	return nil
+}
+
+// Scheme returns the storage scheme of the trie nodes, either rawdb.HashScheme
+// or rawdb.PathScheme.
+func (db *Database) Scheme() string {
+	if db.pathdb != nil {
+		return rawdb.PathScheme
+	}
+	return rawdb.HashScheme
+}
+
+// Initialized reports whether the state of the given genesis root is available.
+// The path scheme overwrites the genesis state in place once the chain moves
+// on, so any persisted state counts as initialized.
+func (db *Database) Initialized(genesisRoot common.Hash) bool {
+	if genesisRoot == emptyRoot || genesisRoot == (common.Hash{}) {
+		return true
+	}
+	if db.pathdb != nil {
+		return len(rawdb.ReadAccountTrieNode(db.diskdb, nil)) > 0
+	}
+	return db.node(genesisRoot) != nil
+}
+
+// Update seals the trie nodes committed by the state transition from parent to
+// root into the state with the given root, stacked on top of its parent state.
+// It is a no-op in the hash scheme.
+func (db *Database) Update(root common.Hash, parent common.Hash, nodes *MergedNodeSet) error {
+	if db.pathdb == nil {
+		return nil
+	}
+	if err := db.pathdb.update(root, parent, nodes); err != nil {
+		return err
+	}
+	// If the preimage cache got large enough, push to disk
+	if db.preimagesSize > 4*1024*1024 {
+		db.lock.Lock()
+		defer db.lock.Unlock()
+
+		rawdb.WritePreimages(db.diskdb, db.preimages)
+		db.preimages, db.preimagesSize = make(map[common.Hash][]byte), 0
+	}
+	return nil
+}
+
+// Flatten persists the oldest in-memory states below root until at most the
+// given number of states on top of the persisted one are left in memory. It is
+// a no-op in the hash scheme.
+func (db *Database) Flatten(root common.Hash, layers int) error {
+	if db.pathdb == nil {
+		return nil
+	}
+	return db.pathdb.cap(root, layers)
+}
+
+// Journal saves the in-memory states from root down to the persisted one, to
+// be restored after a restart. It is a no-op in the hash scheme.
+func (db *Database) Journal(root common.Hash) error {
+	if db.pathdb == nil {
+		return nil
+	}
+	if db.preimages != nil {
+		db.lock.Lock()
+		rawdb.WritePreimages(db.diskdb, db.preimages)
+		db.preimages, db.preimagesSize = make(map[common.Hash][]byte), 0
+		db.lock.Unlock()
+	}
+	return db.pathdb.journal(root)
+}
+
+// Recoverable reports whether the state with the given root can be made
+// available by reverting the persisted state. Only the path scheme supports
+// reverting states.
+func (db *Database) Recoverable(root common.Hash) bool {
+	if db.pathdb == nil {
+		return false
+	}
+	return db.pathdb.recoverable(root)
+}
+
+// Recover reverts the persisted state into the one with the given root using
+// the state histories, discarding all the in-memory states.
+func (db *Database) Recover(root common.Hash) error {
+	if db.pathdb == nil {
+		return errNotSupported
+	}
+	return db.pathdb.recover(root)
+}
+
+// WipeStorage returns the deletion of all the nodes of the given storage trie,
+// to be merged before the node set of the storage trie of a recreated account
+// and sealed into the next state by Update. It returns nil in the hash scheme.
+func (db *Database) WipeStorage(stateRoot common.Hash, owner common.Hash, root common.Hash) (*NodeSet, error) {
+	if db.pathdb == nil || root == emptyRoot || root == (common.Hash{}) {
+		return nil, nil
+	}
+	t, err := NewWithID(StorageTrieID(stateRoot, owner, root), db)
+	if err != nil {
+		return nil, err
+	}
+	set := newNodeSet(owner)
+	it := t.NodeIterator(nil)
+	for it.Next(true) {
+		if it.Hash() != (common.Hash{}) {
+			set.addNode(it.Path(), nil)
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
@@ -0,0 +1,229 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package trie
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// pathTester commits a sequence of states into a path scheme database, each
+// one changing a part of the keys of its parent.
+type pathTester struct {
+	diskdb ethdb.Database
+	db     *Database
+	roots  []common.Hash
+	states []map[string]string
+}
+
+func newPathTester(t *testing.T, history uint64, blocks int) *pathTester {
+	diskdb := rawdb.NewMemoryDatabase()
+	tester := &pathTester{
+		diskdb: diskdb,
+		db:     NewDatabaseWithConfig(diskdb, &Config{Scheme: rawdb.PathScheme, StateHistory: history}),
+	}
+	var (
+		parent = emptyRoot
+		state  = make(map[string]string)
+	)
+	for i := 0; i < blocks; i++ {
+		tr, err := NewWithID(StateTrieID(parent), tester.db)
+		if err != nil {
+			t.Fatalf("block %d: failed to open trie: %v", i, err)
+		}
+		next := make(map[string]string, len(state))
+		for k, v := range state {
+			next[k] = v
+		}
+		for j := 0; j < 16; j++ {
+			key := fmt.Sprintf("key-%03d", (i*7+j)%64)
+			if j%5 == 4 {
+				delete(next, key)
+				if err := tr.TryDelete([]byte(key)); err != nil {
+					t.Fatalf("block %d: failed to delete: %v", i, err)
+				}
+				continue
+			}
+			val := fmt.Sprintf("value-%d-%d", i, j)
+			next[key] = val
+			if err := tr.TryUpdate([]byte(key), []byte(val)); err != nil {
+				t.Fatalf("block %d: failed to update: %v", i, err)
+			}
+		}
+		root, _, set, err := tr.Commit(nil)
+		if err != nil {
+			t.Fatalf("block %d: failed to commit: %v", i, err)
+		}
+		nodes := NewMergedNodeSet()
+		nodes.Merge(set)
+		if err := tester.db.Update(root, parent, nodes); err != nil {
+			t.Fatalf("block %d: failed to update database: %v", i, err)
+		}
+		tester.roots = append(tester.roots, root)
+		tester.states = append(tester.states, next)
+		parent, state = root, next
+	}
+	return tester
+}
+
+// verify checks that the state with the given index is fully accessible.
+func (tester *pathTester) verify(t *testing.T, index int) {
+	t.Helper()
+
+	tr, err := NewWithID(StateTrieID(tester.roots[index]), tester.db)
+	if err != nil {
+		t.Fatalf("state %d: failed to open trie: %v", index, err)
+	}
+	for k, v := range tester.states[index] {
+		have, err := tr.TryGet([]byte(k))
+		if err != nil {
+			t.Fatalf("state %d: failed to read %s: %v", index, k, err)
+		}
+		if !bytes.Equal(have, []byte(v)) {
+			t.Fatalf("state %d: value mismatch for %s: have %s, want %s", index, k, have, v)
+		}
+	}
+	it := NewIterator(tr.NodeIterator(nil))
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if it.Err != nil {
+		t.Fatalf("state %d: failed to iterate: %v", index, it.Err)
+	}
+	if count != len(tester.states[index]) {
+		t.Fatalf("state %d: leaf count mismatch: have %d, want %d", index, count, len(tester.states[index]))
+	}
+}
+
+// Tests that all the states kept in memory and the flattened one are accessible.
+func TestPathDatabaseFlatten(t *testing.T) {
+	tester := newPathTester(t, 0, 16)
+	for i := range tester.roots {
+		tester.verify(t, i)
+	}
+	if err := tester.db.Flatten(tester.roots[15], 4); err != nil {
+		t.Fatalf("failed to flatten: %v", err)
+	}
+	for i := 11; i < 16; i++ {
+		tester.verify(t, i)
+	}
+	if id := rawdb.ReadPersistentStateID(tester.diskdb); id != 12 {
+		t.Fatalf("persistent state id mismatch: have %d, want %d", id, 12)
+	}
+	// States below the disk layer are only reachable via recovery
+	if _, err := NewWithID(StateTrieID(tester.roots[5]), tester.db); err == nil {
+		t.Fatalf("stale state is accessible")
+	}
+	if !tester.db.Recoverable(tester.roots[5]) {
+		t.Fatalf("stale state is not recoverable")
+	}
+}
+
+// Tests that the persisted state can be reverted to an older one and that the
+// histories beyond the configured limit are pruned.
+func TestPathDatabaseRecover(t *testing.T) {
+	tester := newPathTester(t, 8, 16)
+	if err := tester.db.Flatten(tester.roots[15], 0); err != nil {
+		t.Fatalf("failed to flatten: %v", err)
+	}
+	if tail := rawdb.ReadStateHistoryTail(tester.diskdb); tail != 9 {
+		t.Fatalf("history tail mismatch: have %d, want %d", tail, 9)
+	}
+	if tester.db.Recoverable(tester.roots[6]) {
+		t.Fatalf("pruned state is recoverable")
+	}
+	if err := tester.db.Recover(tester.roots[6]); err == nil {
+		t.Fatalf("pruned state recovered")
+	}
+	if !tester.db.Recoverable(tester.roots[7]) {
+		t.Fatalf("retained state is not recoverable")
+	}
+	if err := tester.db.Recover(tester.roots[7]); err != nil {
+		t.Fatalf("failed to recover: %v", err)
+	}
+	tester.verify(t, 7)
+
+	if id := rawdb.ReadPersistentStateID(tester.diskdb); id != 8 {
+		t.Fatalf("persistent state id mismatch: have %d, want %d", id, 8)
+	}
+	if tester.db.Recoverable(tester.roots[10]) {
+		t.Fatalf("reverted state is recoverable")
+	}
+}
+
+// Tests that the in-memory layers survive a restart through the journal.
+func TestPathDatabaseJournal(t *testing.T) {
+	tester := newPathTester(t, 0, 8)
+	if err := tester.db.Flatten(tester.roots[7], 4); err != nil {
+		t.Fatalf("failed to flatten: %v", err)
+	}
+	if err := tester.db.Journal(tester.roots[7]); err != nil {
+		t.Fatalf("failed to journal: %v", err)
+	}
+	tester.db = NewDatabaseWithConfig(tester.diskdb, &Config{Scheme: rawdb.PathScheme})
+	for i := 3; i < 8; i++ {
+		tester.verify(t, i)
+	}
+	// The journal is consumed on load, a second restart only has the disk state
+	tester.db = NewDatabaseWithConfig(tester.diskdb, &Config{Scheme: rawdb.PathScheme})
+	tester.verify(t, 3)
+	if _, err := NewWithID(StateTrieID(tester.roots[7]), tester.db); err == nil {
+		t.Fatalf("journaled state is accessible after the journal was consumed")
+	}
+}
+
+// Tests that tries committed concurrently on top of the same state, e.g. a
+// reexecution during the block import, don't leak nodes into each other.
+func TestPathDatabaseOverlappingCommits(t *testing.T) {
+	tester := newPathTester(t, 0, 4)
+	parent := tester.roots[3]
+
+	commit := func(key, val string) (common.Hash, *NodeSet) {
+		tr, err := NewWithID(StateTrieID(parent), tester.db)
+		if err != nil {
+			t.Fatalf("failed to open trie: %v", err)
+		}
+		if err := tr.TryUpdate([]byte(key), []byte(val)); err != nil {
+			t.Fatalf("failed to update: %v", err)
+		}
+		root, _, set, err := tr.Commit(nil)
+		if err != nil {
+			t.Fatalf("failed to commit: %v", err)
+		}
+		return root, set
+	}
+	rootA, setA := commit("key-000", "value-a")
+	rootB, setB := commit("key-000", "value-b")
+
+	// Seal the states in the opposite order of their commits
+	for _, c := range []struct {
+		root common.Hash
+		set  *NodeSet
+	}{{rootB, setB}, {rootA, setA}} {
+		nodes := NewMergedNodeSet()
+		nodes.Merge(c.set)
+		if err := tester.db.Update(c.root, parent, nodes); err != nil {
+			t.Fatalf("failed to update database: %v", err)
+		}
+	}
+	for root, want := range map[common.Hash]string{rootA: "value-a", rootB: "value-b"} {
+		tr, err := NewWithID(StateTrieID(root), tester.db)
+		if err != nil {
+			t.Fatalf("failed to open trie %x: %v", root, err)
+		}
+		have, err := tr.TryGet([]byte("key-000"))
+		if err != nil {
+			t.Fatalf("failed to read from %x: %v", root, err)
+		}
+		if string(have) != want {
+			t.Fatalf("value mismatch in %x: have %s, want %s", root, have, want)
+		}
+	}
+	tester.verify(t, 3)
+}
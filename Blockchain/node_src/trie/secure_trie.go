@@ -38,7 +38,16 @@ func NewSecure(root common.Hash, db *Database) (*SecureTrie, error) {
 	if db == nil {
 		panic("trie.NewSecure called without a database")
 	}
-	trie, err := New(root, db)
+	return NewSecureWithID(StateTrieID(root), db)
+}
+
+// NewSecureWithID creates a secure trie identified by the state and owner it
+// belongs to, see NewWithID.
+func NewSecureWithID(id *ID, db *Database) (*SecureTrie, error) {
+	if db == nil {
+		panic("trie.NewSecureWithID called without a database")
+	}
+	trie, err := NewWithID(id, db)
 	if err != nil {
 		return nil, err
 	}
@@ -138,7 +147,7 @@ func (t *SecureTrie) GetKey(shaKey []byte) []byte {
 // Nodes are stored with their sha3 hash as the key.
 // Committing flushes nodes from memory. Subsequent Get calls will load nodes
 // from the database.
-func (t *SecureTrie) Commit(onleaf LeafCallback) (common.Hash, int, error) {
+func (t *SecureTrie) Commit(onleaf LeafCallback) (common.Hash, int, *NodeSet, error) {
 	// Write all the pre-images to the actual disk database
 	if len(t.getSecKeyCache()) > 0 {
 		if t.trie.db.preimages != nil { // Ugly direct check but avoids the below write lock
@@ -163,6 +172,7 @@ func (t *SecureTrie) Hash() common.Hash {
 // Copy returns a copy of SecureTrie.
 func (t *SecureTrie) Copy() *SecureTrie {
 	cpy := *t
+	cpy.trie.tracer = t.trie.tracer.copy()
 	return &cpy
 }
 
@@ -0,0 +1,64 @@
+// Copyright 2025 Silver Bitcoin Foundation
+
+package trie
+
+// tracer tracks the paths of the trie nodes loaded from the database. The path
+// scheme overwrites nodes in place, so any loaded node which is not part of the
+// trie anymore after a commit has to be deleted explicitly.
+// The tracer is only created for tries backed by the path scheme, all methods
+// are no-ops on a nil tracer.
+type tracer struct {
+	loaded map[string]struct{}
+}
+
+// newTracer initializes the tracer for capturing loaded trie nodes.
+func newTracer() *tracer {
+	return &tracer{loaded: make(map[string]struct{})}
+}
+
+// onRead tracks the newly loaded trie node.
+func (t *tracer) onRead(path []byte) {
+	if t == nil {
+		return
+	}
+	t.loaded[string(path)] = struct{}{}
+}
+
+// deletedNodes returns the paths of the loaded nodes which were neither written
+// nor are covered by a clean subtrie in the committed trie.
+func (t *tracer) deletedNodes(set *NodeSet) []string {
+	if t == nil {
+		return nil
+	}
+	var paths []string
+	for path := range t.loaded {
+		if _, ok := set.nodes[path]; ok {
+			continue
+		}
+		if set.retained(path) {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// reset clears the loaded nodes.
+func (t *tracer) reset() {
+	if t == nil {
+		return
+	}
+	t.loaded = make(map[string]struct{})
+}
+
+// copy returns a deep copied tracer instance.
+func (t *tracer) copy() *tracer {
+	if t == nil {
+		return nil
+	}
+	loaded := make(map[string]struct{}, len(t.loaded))
+	for path := range t.loaded {
+		loaded[path] = struct{}{}
+	}
+	return &tracer{loaded: loaded}
+}
@@ -44,10 +44,19 @@ type LeafCallback func(paths [][]byte, hexpath []byte, leaf []byte, parent commo
 type Trie struct {
 	db   *Database
 	root node
+
+	// Identifiers of the trie needed by the path scheme to locate its nodes
+	owner     common.Hash
+	stateRoot common.Hash
+
 	// Keep track of the number leafs which have been inserted since the last
 	// hashing operation. This number will not directly map to the number of
 	// actually unhashed nodes
 	unhashed int
+
+	// tracer tracks the nodes loaded from the database, only set in the path
+	// scheme to delete the nodes which are no longer part of the trie.
+	tracer *tracer
 }
 
 // newFlag returns the cache flag value for a newly created node.
@@ -61,12 +70,25 @@ func (t *Trie) newFlag() nodeFlag {
 // New will panic if db is nil and returns a MissingNodeError if root does
 // not exist in the database. Accessing the trie loads nodes from db on demand.
 func New(root common.Hash, db *Database) (*Trie, error) {
+	return NewWithID(StateTrieID(root), db)
+}
+
+// NewWithID creates a trie with an existing root node from db, identified by
+// the state and owner it belongs to. Storage tries in the path scheme must be
+// opened with their identifier, the hash scheme only needs the root.
+func NewWithID(id *ID, db *Database) (*Trie, error) {
 	if db == nil {
-		panic("trie.New called without a database")
+		panic("trie.NewWithID called without a database")
 	}
 	trie := &Trie{
-		db: db,
+		db:        db,
+		owner:     id.Owner,
+		stateRoot: id.StateRoot,
+	}
+	if db.pathdb != nil {
+		trie.tracer = newTracer()
 	}
+	root := id.Root
 	if root != (common.Hash{}) && root != emptyRoot {
 		rootnode, err := trie.resolveHash(root[:], nil)
 		if err != nil {
@@ -174,7 +196,7 @@ func (t *Trie) tryGetNode(origNode node, path []byte, pos int) (item []byte, new
 		if hash == nil {
 			return nil, origNode, 0, errors.New("non-consensus node")
 		}
-		blob, err := t.db.Node(common.BytesToHash(hash))
+		blob, err := t.resolveBlob(hash, path[:pos])
 		return blob, origNode, 1, err
 	}
 	// Path still needs to be traversed, descend into children
@@ -478,7 +500,27 @@ func (t *Trie) resolve(n node, prefix []byte) (node, error) {
 	return n, nil
 }
 
+// resolveHash loads the node with the given hash at the given path, tracking
+// it as part of the trie.
 func (t *Trie) resolveHash(n hashNode, prefix []byte) (node, error) {
+	node, err := t.resolveNode(n, prefix)
+	if err != nil {
+		return nil, err
+	}
+	t.tracer.onRead(prefix)
+	return node, nil
+}
+
+// resolveNode loads the node with the given hash at the given path without
+// tracking it, used by the readers which don't expand the trie itself.
+func (t *Trie) resolveNode(n hashNode, prefix []byte) (node, error) {
+	if t.db.pathdb != nil {
+		blob, err := t.resolveBlob(n, prefix)
+		if err != nil {
+			return nil, err
+		}
+		return mustDecodeNode(n, blob), nil
+	}
 	hash := common.BytesToHash(n)
 	if node := t.db.node(hash); node != nil {
 		return node, nil
@@ -486,6 +528,22 @@ func (t *Trie) resolveHash(n hashNode, prefix []byte) (node, error) {
 	return nil, &MissingNodeError{NodeHash: hash, Path: prefix}
 }
 
+// resolveBlob loads the encoded node with the given hash at the given path.
+func (t *Trie) resolveBlob(n hashNode, prefix []byte) ([]byte, error) {
+	hash := common.BytesToHash(n)
+	if t.db.pathdb == nil {
+		return t.db.Node(hash)
+	}
+	blob, err := t.db.pathdb.node(t.stateRoot, t.owner, prefix, hash)
+	if err != nil {
+		log.Debug("Failed to resolve trie node", "owner", t.owner, "path", prefix, "err", err)
+	}
+	if blob == nil {
+		return nil, &MissingNodeError{NodeHash: hash, Path: prefix}
+	}
+	return blob, nil
+}
+
 // Hash returns the root hash of the trie. It does not write to the
 // database and can be used even if the trie doesn't have one.
 func (t *Trie) Hash() common.Hash {
@@ -495,13 +553,24 @@ func (t *Trie) Hash() common.Hash {
 }
 
 // Commit writes all nodes to the trie's memory database, tracking the internal
-// and external (for account tries) references.
-func (t *Trie) Commit(onleaf LeafCallback) (common.Hash, int, error) {
+// and external (for account tries) references. In the path scheme the nodes are
+// returned instead, to be sealed into the state by Database.Update; the set is
+// nil in the hash scheme or if nothing changed.
+func (t *Trie) Commit(onleaf LeafCallback) (common.Hash, int, *NodeSet, error) {
 	if t.db == nil {
 		panic("commit called on trie with nil database")
 	}
 	if t.root == nil {
-		return emptyRoot, 0, nil
+		// All the loaded nodes are gone in the path scheme
+		var set *NodeSet
+		if t.db.pathdb != nil && len(t.tracer.loaded) > 0 {
+			set = newNodeSet(t.owner)
+			for _, path := range t.tracer.deletedNodes(set) {
+				set.addNode([]byte(path), nil)
+			}
+			t.tracer.reset()
+		}
+		return emptyRoot, 0, set, nil
 	}
 	// Derive the hash for all dirty nodes first. We hold the assumption
 	// in the following procedure that all nodes are hashed.
@@ -513,7 +582,10 @@ func (t *Trie) Commit(onleaf LeafCallback) (common.Hash, int, error) {
 	// up goroutines. This can happen e.g. if we load a trie for reading storage
 	// values, but don't write to it.
 	if _, dirty := t.root.cache(); !dirty {
-		return rootHash, 0, nil
+		return rootHash, 0, nil, nil
+	}
+	if t.db.pathdb != nil {
+		h.nodes = newNodeSet(t.owner)
 	}
 	var wg sync.WaitGroup
 	if onleaf != nil {
@@ -535,10 +607,17 @@ func (t *Trie) Commit(onleaf LeafCallback) (common.Hash, int, error) {
 		wg.Wait()
 	}
 	if err != nil {
-		return common.Hash{}, 0, err
+		return common.Hash{}, 0, nil, err
+	}
+	set := h.nodes
+	if set != nil {
+		for _, path := range t.tracer.deletedNodes(set) {
+			set.addNode([]byte(path), nil)
+		}
+		t.tracer.reset()
 	}
 	t.root = newRoot
-	return rootHash, committed, nil
+	return rootHash, committed, set, nil
 }
 
 // hashRoot calculates the root hash of the given trie
@@ -548,7 +627,7 @@ func (t *Trie) hashRoot() (node, node, error) {
 	}
 	// If the number of changes is below 100, we let one thread handle it
 	var h *hasher
-	if t.db == nil {
+	if t.db == nil || t.db.pathdb != nil {
 		h = newHasher(t.unhashed >= 100)
 	} else {
 		h = newHasherWithCache(t.unhashed >= 100, t.db.GetDirtyHashCache())
@@ -563,4 +642,5 @@ func (t *Trie) hashRoot() (node, node, error) {
 func (t *Trie) Reset() {
 	t.root = nil
 	t.unhashed = 0
+	t.tracer.reset()
 }